@@ -384,7 +384,31 @@ export const PromSettings = (props: Props) => {
           </div>
 
           <div className="gf-form-inline">
-            {options.jsonData.incrementalQuerying && (
+            <div className="gf-form max-width-30">
+              <InlineField
+                label="Backend incremental querying (beta)"
+                labelWidth={PROM_CONFIG_LABEL_WIDTH}
+                tooltip={
+                  <>
+                    Range query results are cached by the Grafana server, and only new records are requested from the
+                    prometheus instance. Unlike incremental querying, the cache is shared by all users. Turn this on to
+                    decrease database and network load of dashboards with a short refresh interval.
+                  </>
+                }
+                interactive={true}
+                className={styles.switchField}
+                disabled={options.readOnly}
+              >
+                <Switch
+                  value={options.jsonData.backendIncrementalQuerying ?? false}
+                  onChange={onUpdateDatasourceJsonDataOptionChecked(props, 'backendIncrementalQuerying')}
+                />
+              </InlineField>
+            </div>
+          </div>
+
+          <div className="gf-form-inline">
+            {(options.jsonData.incrementalQuerying || options.jsonData.backendIncrementalQuerying) && (
               <InlineField
                 label="Query overlap window"
                 labelWidth={PROM_CONFIG_LABEL_WIDTH}
@@ -43,6 +43,8 @@ export type AppPluginConfig = {
 
 export class GrafanaBootConfig implements GrafanaConfig {
   publicDashboardAccessToken?: string;
+  /** Template variables the viewer of a public dashboard may change */
+  publicDashboardVariables?: string[];
   publicDashboardsEnabled = true;
   snapshotEnabled = true;
   datasources: { [str: string]: DataSourceInstanceSettings } = {};
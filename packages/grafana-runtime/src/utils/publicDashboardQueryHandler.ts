@@ -4,6 +4,7 @@ import { DataQuery, DataQueryRequest, DataQueryResponse } from '@grafana/data';
 
 import { config } from '../config';
 import { getBackendSrv } from '../services/backendSrv';
+import { getTemplateSrv } from '../services/templateSrv';
 
 import { BackendDataSourceResponse, toDataQueryResponse } from './queryResponse';
 
@@ -30,6 +31,7 @@ export function publicDashboardQueryHandler(request: DataQueryRequest<DataQuery>
       to: toRange.valueOf().toString(),
       timezone: request.timezone,
     },
+    variables: getSelectedVariables(),
   };
 
   return getBackendSrv()
@@ -48,3 +50,24 @@ export function publicDashboardQueryHandler(request: DataQueryRequest<DataQuery>
       })
     );
 }
+
+/**
+ * Returns the current values of the template variables the viewer of the public dashboard may change.
+ * The backend validates them against the allow-list of the public dashboard.
+ */
+function getSelectedVariables(): Record<string, string[]> | undefined {
+  const allowed = config.publicDashboardVariables;
+  if (!allowed?.length) {
+    return undefined;
+  }
+
+  const variables: Record<string, string[]> = {};
+  for (const variable of getTemplateSrv().getVariables()) {
+    if (!allowed.includes(variable.name) || !('current' in variable) || variable.current?.value === undefined) {
+      continue;
+    }
+    const value = variable.current.value;
+    variables[variable.name] = (Array.isArray(value) ? value : [value]).map(String);
+  }
+  return variables;
+}
@@ -360,6 +360,12 @@ func (hs *HTTPServer) registerRoutes() {
 			orgsRoute.Put("/quotas/:target", authorizeInOrg(ac.UseOrgFromContextParams, ac.EvalPermission(ac.ActionOrgsQuotasWrite)), routing.Wrap(hs.UpdateOrgQuota))
 		})
 
+		// team quotas
+		apiRoute.Group("/teams/:teamId/quotas", func(teamQuotasRoute routing.RouteRegister) {
+			teamQuotasRoute.Get("/", authorize(ac.EvalPermission(ac.ActionTeamsRead, ac.ScopeTeamsID)), routing.Wrap(hs.GetTeamQuotas))
+			teamQuotasRoute.Put("/:target", reqGrafanaAdmin, routing.Wrap(hs.UpdateTeamQuota))
+		})
+
 		// orgs (admin routes)
 		apiRoute.Get("/orgs/name/:name/", authorizeInOrg(ac.UseGlobalOrg, ac.EvalPermission(ac.ActionOrgsRead)), routing.Wrap(hs.GetOrgByName))
 
@@ -390,6 +396,10 @@ func (hs *HTTPServer) registerRoutes() {
 			datasourceRoute.Delete("/name/:name", authorize(ac.EvalPermission(datasources.ActionDelete, nameScope)), routing.Wrap(hs.DeleteDataSourceByName))
 			datasourceRoute.Get("/:id", authorize(ac.EvalPermission(datasources.ActionRead, idScope)), routing.Wrap(hs.GetDataSourceById))
 			datasourceRoute.Get("/uid/:uid", authorize(ac.EvalPermission(datasources.ActionRead, uidScope)), routing.Wrap(hs.GetDataSourceByUID))
+			datasourceRoute.Get("/uid/:uid/versions", authorize(ac.EvalPermission(datasources.ActionRead, uidScope)), routing.Wrap(hs.GetDataSourceVersions))
+			datasourceRoute.Get("/uid/:uid/versions/:version", authorize(ac.EvalPermission(datasources.ActionRead, uidScope)), routing.Wrap(hs.GetDataSourceVersion))
+			datasourceRoute.Get("/uid/:uid/versions/:version/diff", authorize(ac.EvalPermission(datasources.ActionRead, uidScope)), routing.Wrap(hs.DiffDataSourceVersions))
+			datasourceRoute.Post("/uid/:uid/versions/:version/restore", authorize(ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(hs.RestoreDataSourceVersion))
 			datasourceRoute.Get("/name/:name", authorize(ac.EvalPermission(datasources.ActionRead, nameScope)), routing.Wrap(hs.GetDataSourceByName))
 			datasourceRoute.Get("/id/:name", authorize(ac.EvalPermission(datasources.ActionIDRead, nameScope)), routing.Wrap(hs.GetDataSourceIdByName))
 		})
@@ -465,6 +475,8 @@ func (hs *HTTPServer) registerRoutes() {
 				dashUidRoute.Get("/versions", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersions))
 				dashUidRoute.Post("/restore", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.RestoreDashboardVersion))
 				dashUidRoute.Get("/versions/:id", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersion))
+				dashUidRoute.Put("/versions/:id/label", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.SetDashboardVersionLabel))
+				dashUidRoute.Post("/panels/:panelId/query/export", requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow), authorize(ac.EvalAll(ac.EvalPermission(dashboards.ActionDashboardsRead), ac.EvalPermission(datasources.ActionQuery))), routing.Wrap(hs.QueryMetricsExport))
 				dashUidRoute.Group("/permissions", func(dashboardPermissionRoute routing.RouteRegister) {
 					dashboardPermissionRoute.Get("/", authorize(ac.EvalPermission(dashboards.ActionDashboardsPermissionsRead)), routing.Wrap(hs.GetDashboardPermissionList))
 					dashboardPermissionRoute.Post("/", authorize(ac.EvalPermission(dashboards.ActionDashboardsPermissionsWrite)), routing.Wrap(hs.UpdateDashboardPermissions))
@@ -707,6 +707,7 @@ func (hs *HTTPServer) GetDashboardVersions(c *contextmodel.ReqContext) response.
 			Created:       version.Created,
 			Message:       msg,
 			CreatedBy:     creator,
+			Label:         version.Label,
 		})
 	}
 
@@ -795,11 +796,71 @@ func (hs *HTTPServer) GetDashboardVersion(c *contextmodel.ReqContext) response.R
 		Created:       res.Created,
 		Message:       res.Message,
 		CreatedBy:     creator,
+		Label:         res.Label,
 	}
 
 	return response.JSON(http.StatusOK, dashVersionMeta)
 }
 
+// swagger:route PUT /dashboards/uid/{uid}/versions/{DashboardVersionID}/label dashboard_versions setDashboardVersionLabelByUID
+//
+// Set the label of a dashboard version using UID.
+//
+// Versions with a label listed in the protected labels of the version retention settings are never deleted.
+// An empty label removes it.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) SetDashboardVersionLabel(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.SetDashboardVersionLabelCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.Label = strings.TrimSpace(cmd.Label)
+	if len(cmd.Label) > 255 {
+		return response.Error(http.StatusBadRequest, "Label must be at most 255 characters long", nil)
+	}
+
+	version, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 32)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.SignedInUser.GetOrgID(), 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	err = hs.dashboardVersionService.SetLabel(c.Req.Context(), &dashver.SetDashboardVersionLabelCommand{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		DashboardID:  dash.ID,
+		DashboardUID: dash.UID,
+		Version:      int(version),
+		Label:        cmd.Label,
+	})
+	if err != nil {
+		if errors.Is(err, dashver.ErrDashboardVersionNotFound) {
+			return response.Error(http.StatusNotFound, fmt.Sprintf("Dashboard version %d not found", version), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to set dashboard version label", err)
+	}
+
+	return response.Success("Dashboard version label updated")
+}
+
 // swagger:route POST /dashboards/calculate-diff dashboards calculateDashboardDiff
 //
 // Perform diff on two dashboards.
@@ -1078,6 +1139,18 @@ type GetDashboardVersionByUIDParams struct {
 	UID string `json:"uid"`
 }
 
+// swagger:parameters setDashboardVersionLabelByUID
+type SetDashboardVersionLabelByUIDParams struct {
+	// in:path
+	DashboardVersionID int64
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body dtos.SetDashboardVersionLabelCommand
+}
+
 // swagger:parameters getDashboardVersions getDashboardVersionsByUID
 type GetDashboardVersionsParams struct {
 	// Maximum number of results to return
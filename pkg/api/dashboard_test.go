@@ -1056,6 +1056,16 @@ func (l *mockLibraryElementService) GetElementsForDashboard(c context.Context, d
 	return map[string]model.LibraryElementDTO{}, nil
 }
 
+// GetAllElements gets all elements matching the query.
+func (l *mockLibraryElementService) GetAllElements(c context.Context, signedInUser identity.Requester, query model.SearchLibraryElementsQuery) (model.LibraryElementSearchResult, error) {
+	return model.LibraryElementSearchResult{}, nil
+}
+
+// PatchElement updates an element from a UID.
+func (l *mockLibraryElementService) PatchElement(c context.Context, signedInUser identity.Requester, cmd model.PatchLibraryElementCommand, uid string) (model.LibraryElementDTO, error) {
+	return model.LibraryElementDTO{}, nil
+}
+
 // ConnectElementsToDashboard connects elements to a specific dashboard.
 func (l *mockLibraryElementService) ConnectElementsToDashboard(c context.Context, signedInUser identity.Requester, elementUIDs []string, dashboardID int64) error {
 	return nil
@@ -515,6 +515,7 @@ func (hs *HTTPServer) UpdateDataSourceByID(c *contextmodel.ReqContext) response.
 	}
 	datasourcesLogger.Debug("Received command to update data source", "url", cmd.URL)
 	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.UserID = updatedByUserID(c)
 	var err error
 	if cmd.ID, err = strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64); err != nil {
 		return response.Error(http.StatusBadRequest, "id is invalid", err)
@@ -565,6 +566,7 @@ func (hs *HTTPServer) UpdateDataSourceByUID(c *contextmodel.ReqContext) response
 	}
 	datasourcesLogger.Debug("Received command to update data source", "url", cmd.URL)
 	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.UserID = updatedByUserID(c)
 	if resp := validateURL(cmd.Type, cmd.URL); resp != nil {
 		return resp
 	}
@@ -590,6 +592,288 @@ func (hs *HTTPServer) UpdateDataSourceByUID(c *contextmodel.ReqContext) response
 	return hs.updateDataSourceByID(c, ds, cmd)
 }
 
+// updatedByUserID returns the ID of the user doing the request, 0 for identities
+// which are not users, ex. API keys.
+func updatedByUserID(c *contextmodel.ReqContext) int64 {
+	userID, err := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+	if err != nil {
+		return 0
+	}
+	return userID
+}
+
+// swagger:route GET /datasources/uid/{uid}/versions datasources getDataSourceVersions
+//
+// Get the configuration history of a data source.
+//
+// Secure JSON data values are never returned, only the names of the secure fields
+// configured and changed by each version.
+//
+// Responses:
+// 200: getDataSourceVersionsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDataSourceVersions(c *contextmodel.ReqContext) response.Response {
+	ds, err := hs.getRawDataSourceByUID(c.Req.Context(), web.Params(c.Req)[":uid"], c.SignedInUser.GetOrgID())
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return response.Error(http.StatusNotFound, "Data source not found", nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to query datasource", err)
+	}
+
+	versions, err := hs.DataSourcesService.ListDataSourceVersions(c.Req.Context(), &datasources.ListDataSourceVersionsQuery{
+		OrgID:         ds.OrgID,
+		DataSourceUID: ds.UID,
+		Limit:         c.QueryInt("limit"),
+		Start:         c.QueryInt("start"),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get datasource versions", err)
+	}
+
+	loginMem := make(map[int64]string, len(versions))
+	res := make([]*datasources.DataSourceVersionMeta, 0, len(versions))
+	for _, version := range versions {
+		res = append(res, hs.dataSourceVersionMeta(c.Req.Context(), version, loginMem))
+	}
+
+	return response.JSON(http.StatusOK, res)
+}
+
+// swagger:route GET /datasources/uid/{uid}/versions/{version} datasources getDataSourceVersion
+//
+// Get a version of a data source configuration.
+//
+// Responses:
+// 200: getDataSourceVersionResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDataSourceVersion(c *contextmodel.ReqContext) response.Response {
+	version, err := strconv.Atoi(web.Params(c.Req)[":version"])
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	res, err := hs.DataSourcesService.GetDataSourceVersion(c.Req.Context(), &datasources.GetDataSourceVersionQuery{
+		OrgID:         c.SignedInUser.GetOrgID(),
+		DataSourceUID: web.Params(c.Req)[":uid"],
+		Version:       version,
+	})
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceVersionNotFound) {
+			return response.Error(http.StatusNotFound, "Data source version not found", nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get datasource version", err)
+	}
+
+	return response.JSON(http.StatusOK, hs.dataSourceVersionMeta(c.Req.Context(), res, map[int64]string{}))
+}
+
+// swagger:route GET /datasources/uid/{uid}/versions/{version}/diff datasources diffDataSourceVersions
+//
+// Compare a version of a data source configuration with another one.
+//
+// The version is compared with the `base` version, or with the previous one if
+// `base` is not provided. Changed secure fields are listed without their values.
+//
+// Responses:
+// 200: diffDataSourceVersionsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DiffDataSourceVersions(c *contextmodel.ReqContext) response.Response {
+	newVersion, err := strconv.Atoi(web.Params(c.Req)[":version"])
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+	baseVersion := newVersion - 1
+	if c.Query("base") != "" {
+		if baseVersion, err = strconv.Atoi(c.Query("base")); err != nil {
+			return response.Error(http.StatusBadRequest, "base version is invalid", err)
+		}
+	}
+
+	versions, err := hs.DataSourcesService.ListDataSourceVersions(c.Req.Context(), &datasources.ListDataSourceVersionsQuery{
+		OrgID:         c.SignedInUser.GetOrgID(),
+		DataSourceUID: web.Params(c.Req)[":uid"],
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get datasource versions", err)
+	}
+
+	var base, target *datasources.DataSourceVersion
+	from, to := baseVersion, newVersion
+	if from > to {
+		from, to = to, from
+	}
+	secureJsonChanged := map[string]struct{}{}
+	for _, v := range versions {
+		switch v.Version {
+		case baseVersion:
+			base = v
+		case newVersion:
+			target = v
+		}
+		if v.Version > from && v.Version <= to {
+			for _, field := range v.SecureJsonChanged {
+				secureJsonChanged[field] = struct{}{}
+			}
+		}
+	}
+	if base == nil || target == nil {
+		return response.Error(http.StatusNotFound, "Data source version not found", nil)
+	}
+
+	fields := make([]string, 0, len(secureJsonChanged))
+	for field := range secureJsonChanged {
+		fields = append(fields, field)
+	}
+	changes, err := datasources.DiffDataSourceVersions(base, target, fields)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to compare datasource versions", err)
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"base":    baseVersion,
+		"new":     newVersion,
+		"changes": changes,
+	})
+}
+
+// swagger:route POST /datasources/uid/{uid}/versions/{version}/restore datasources restoreDataSourceVersion
+//
+// Restore the configuration of a data source to a previous version.
+//
+// Secure JSON data is not versioned, the current secure values are kept.
+//
+// Responses:
+// 200: createOrUpdateDatasourceResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (hs *HTTPServer) RestoreDataSourceVersion(c *contextmodel.ReqContext) response.Response {
+	versionID, err := strconv.Atoi(web.Params(c.Req)[":version"])
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	ds, err := hs.getRawDataSourceByUID(c.Req.Context(), web.Params(c.Req)[":uid"], c.SignedInUser.GetOrgID())
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return response.Error(http.StatusNotFound, "Data source not found", nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to query datasource", err)
+	}
+	if ds.ReadOnly {
+		return response.Error(http.StatusForbidden, "Cannot update read-only data source", nil)
+	}
+
+	version, err := hs.DataSourcesService.GetDataSourceVersion(c.Req.Context(), &datasources.GetDataSourceVersionQuery{
+		OrgID:         ds.OrgID,
+		DataSourceUID: ds.UID,
+		Version:       versionID,
+	})
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceVersionNotFound) {
+			return response.Error(http.StatusNotFound, "Data source version not found", nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get datasource version", err)
+	}
+	if version.Data == nil {
+		return response.Error(http.StatusNotFound, "Data source version not found", nil)
+	}
+	if resp := validateURL(version.Data.Type, version.Data.URL); resp != nil {
+		return resp
+	}
+
+	// check if LBAC rules would be modified
+	hasAccess, errAccess := checkTeamHTTPHeaderPermissions(hs, c, ds, datasources.UpdateDataSourceCommand{JsonData: version.Data.JsonData})
+	if !hasAccess {
+		return response.Error(http.StatusForbidden, fmt.Sprintf("You'll need additional permissions to perform this action. Permissions needed: %s", datasources.ActionPermissionsWrite), errAccess)
+	}
+
+	dataSource, err := hs.DataSourcesService.RestoreDataSourceVersion(c.Req.Context(), &datasources.RestoreDataSourceVersionCommand{
+		OrgID:         ds.OrgID,
+		DataSourceUID: ds.UID,
+		Version:       versionID,
+		UserID:        updatedByUserID(c),
+	})
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceNameExists) {
+			return response.Error(http.StatusConflict, "Failed to restore datasource: "+err.Error(), err)
+		}
+		if errors.Is(err, datasources.ErrDataSourceUpdatingOldVersion) {
+			return response.Error(http.StatusConflict, "Datasource has already been updated by someone else. Please reload and try again", err)
+		}
+		if errors.As(err, &secretsPluginError) {
+			return response.Error(http.StatusInternalServerError, "Failed to restore datasource: "+err.Error(), err)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to restore datasource", err)
+	}
+
+	datasourceDTO := hs.convertModelToDtos(c.Req.Context(), dataSource)
+
+	hs.Live.HandleDatasourceUpdate(c.SignedInUser.GetOrgID(), datasourceDTO.UID)
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"message":    fmt.Sprintf("Datasource restored to version %d", versionID),
+		"id":         dataSource.ID,
+		"name":       dataSource.Name,
+		"datasource": datasourceDTO,
+	})
+}
+
+func (hs *HTTPServer) dataSourceVersionMeta(ctx context.Context, version *datasources.DataSourceVersion, loginMem map[int64]string) *datasources.DataSourceVersionMeta {
+	msg := ""
+	switch {
+	case version.RestoredFrom > 0:
+		msg = fmt.Sprintf("Restored from version %d", version.RestoredFrom)
+	case version.Version <= 1:
+		msg = "Initial save"
+	}
+
+	creator := anonString
+	if version.CreatedBy > 0 {
+		login, found := loginMem[version.CreatedBy]
+		if found {
+			creator = login
+		} else {
+			creator = hs.getUserLogin(ctx, version.CreatedBy)
+			if creator != anonString {
+				loginMem[version.CreatedBy] = creator
+			}
+		}
+	}
+
+	secureJsonChanged := version.SecureJsonChanged
+	if secureJsonChanged == nil {
+		secureJsonChanged = []string{}
+	}
+
+	return &datasources.DataSourceVersionMeta{
+		ID:                version.ID,
+		DataSourceUID:     version.DataSourceUID,
+		Version:           version.Version,
+		RestoredFrom:      version.RestoredFrom,
+		Created:           version.Created,
+		CreatedBy:         creator,
+		Message:           msg,
+		Data:              version.Data,
+		SecureJsonChanged: secureJsonChanged,
+	}
+}
+
 func getEncodedString(jsonData *simplejson.Json, key string) string {
 	if jsonData == nil {
 		return ""
@@ -969,6 +1253,69 @@ func (hs *HTTPServer) checkDatasourceHealth(c *contextmodel.ReqContext, ds *data
 	return response.JSON(http.StatusOK, payload)
 }
 
+// swagger:parameters getDataSourceVersions
+type GetDataSourceVersionsParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"uid"`
+	// Maximum number of results to return
+	// in:query
+	// required:false
+	// default:0
+	Limit int `json:"limit"`
+	// Version to start from when returning queries
+	// in:query
+	// required:false
+	// default:0
+	Start int `json:"start"`
+}
+
+// swagger:parameters getDataSourceVersion restoreDataSourceVersion
+type DataSourceVersionParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"uid"`
+	// in:path
+	// required:true
+	Version int `json:"version"`
+}
+
+// swagger:parameters diffDataSourceVersions
+type DiffDataSourceVersionsParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"uid"`
+	// in:path
+	// required:true
+	Version int `json:"version"`
+	// Version to compare with, the previous version by default
+	// in:query
+	// required:false
+	Base int `json:"base"`
+}
+
+// swagger:response getDataSourceVersionsResponse
+type GetDataSourceVersionsResponse struct {
+	// in:body
+	Body []*datasources.DataSourceVersionMeta `json:"body"`
+}
+
+// swagger:response getDataSourceVersionResponse
+type GetDataSourceVersionResponse struct {
+	// in:body
+	Body *datasources.DataSourceVersionMeta `json:"body"`
+}
+
+// swagger:response diffDataSourceVersionsResponse
+type DiffDataSourceVersionsResponse struct {
+	// in:body
+	Body struct {
+		Base    int                                   `json:"base"`
+		New     int                                   `json:"new"`
+		Changes []datasources.DataSourceVersionChange `json:"changes"`
+	} `json:"body"`
+}
+
 // swagger:parameters checkDatasourceHealthByID
 type CheckDatasourceHealthByIDParams struct {
 	// in:path
@@ -27,15 +27,16 @@ type DashboardMeta struct {
 	HasACL     bool      `json:"hasAcl" xorm:"has_acl"`
 	IsFolder   bool      `json:"isFolder"`
 	// Deprecated: use FolderUID instead
-	FolderId               int64                              `json:"folderId"`
-	FolderUid              string                             `json:"folderUid"`
-	FolderTitle            string                             `json:"folderTitle"`
-	FolderUrl              string                             `json:"folderUrl"`
-	Provisioned            bool                               `json:"provisioned"`
-	ProvisionedExternalId  string                             `json:"provisionedExternalId"`
-	AnnotationsPermissions *dashboardsV0.AnnotationPermission `json:"annotationsPermissions"`
-	PublicDashboardUID     string                             `json:"publicDashboardUid,omitempty"`
-	PublicDashboardEnabled bool                               `json:"publicDashboardEnabled,omitempty"`
+	FolderId                 int64                              `json:"folderId"`
+	FolderUid                string                             `json:"folderUid"`
+	FolderTitle              string                             `json:"folderTitle"`
+	FolderUrl                string                             `json:"folderUrl"`
+	Provisioned              bool                               `json:"provisioned"`
+	ProvisionedExternalId    string                             `json:"provisionedExternalId"`
+	AnnotationsPermissions   *dashboardsV0.AnnotationPermission `json:"annotationsPermissions"`
+	PublicDashboardUID       string                             `json:"publicDashboardUid,omitempty"`
+	PublicDashboardEnabled   bool                               `json:"publicDashboardEnabled,omitempty"`
+	PublicDashboardVariables []string                           `json:"publicDashboardVariables,omitempty"`
 }
 
 type DashboardFullWithMeta struct {
@@ -62,3 +63,7 @@ type CalculateDiffTarget struct {
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
+
+type SetDashboardVersionLabelCommand struct {
+	Label string `json:"label"`
+}
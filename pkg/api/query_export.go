@@ -0,0 +1,161 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/query/export"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// QueryMetricsExport runs the queries of a dashboard panel and returns the results as a file.
+// swagger:route POST /dashboards/uid/{uid}/panels/{panelId}/query/export dashboards queryMetricsExport
+//
+// Export the results of the queries of a dashboard panel, including expressions.
+//
+// The body is the same as for `/ds/query`. Without queries the queries of the panel are used as stored in
+// the dashboard, otherwise every query must match a query of the panel by `refId` and data source, which
+// allows exporting queries with interpolated variables. The `format` query parameter selects the file format:
+// `csv` (default), `parquet` or `xlsx`. CSV and Parquet exports of several frames are returned as a zip archive
+// with one file per frame, XLSX exports use one sheet per frame. Queries of library panels are read from the
+// library panel. Exports larger than the configured maximum size are rejected with status 413.
+//
+// If you are running Grafana Enterprise and have Fine-grained access control enabled
+// you need to have a permission with action: `datasources:query` and read access to the dashboard.
+//
+// Produces:
+// - text/csv
+// - application/vnd.apache.parquet
+// - application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// - application/zip
+//
+// Responses:
+// 200: queryMetricsExportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 413: contentTooLargeError
+// 500: internalServerError
+func (hs *HTTPServer) QueryMetricsExport(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.QueryExport.Enabled {
+		return response.Error(http.StatusNotFound, "Query export is disabled", nil)
+	}
+
+	format, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+
+	panelID, err := strconv.ParseInt(web.Params(c.Req)[":panelId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "panelId is invalid", err)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.SignedInUser.GetOrgID(), 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canView, err := guardian.CanView(); err != nil || !canView {
+		return dashboardGuardianResponse(err)
+	}
+
+	reqDTO := dtos.MetricRequest{}
+	if err := web.Bind(c.Req, &reqDTO); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	panel, err := export.FindPanel(dash.Data, panelID)
+	if err != nil {
+		return response.Error(http.StatusNotFound, err.Error(), err)
+	}
+	if uid := export.LibraryPanelUID(panel); uid != "" {
+		element, err := hs.LibraryElementService.GetElement(c.Req.Context(), c.SignedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+		if err != nil {
+			if errors.Is(err, model.ErrLibraryElementNotFound) {
+				return response.Error(http.StatusNotFound, "Library panel not found", err)
+			}
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get library panel", err)
+		}
+		if panel, err = simplejson.NewJson(element.Model); err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to read library panel", err)
+		}
+	}
+	reqDTO.Queries, err = export.SelectQueries(export.PanelQueries(panel), reqDTO.Queries)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+	if len(reqDTO.Queries) == 0 {
+		return response.Error(http.StatusBadRequest, "The panel has no queries", nil)
+	}
+
+	resp, err := hs.queryDataService.QueryData(c.Req.Context(), c.SignedInUser, c.SkipDSCache, reqDTO)
+	if err != nil {
+		return hs.handleQueryMetricsError(err)
+	}
+
+	refIDs := make([]string, 0, len(reqDTO.Queries))
+	for _, q := range reqDTO.Queries {
+		refIDs = append(refIDs, q.Get("refId").MustString("A"))
+	}
+	frames, err := export.Frames(resp, refIDs)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+	if err := export.CheckRows(frames, hs.Cfg.QueryExport.MaxRows); err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+
+	// the file is rendered before anything is sent, so an export exceeding the size
+	// limit is rejected instead of being cut off
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, frames, hs.Cfg.QueryExport.MaxBytes); err != nil {
+		if errors.Is(err, export.ErrTooLarge) {
+			return response.Error(http.StatusRequestEntityTooLarge, err.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to export query results", err)
+	}
+
+	return response.Respond(http.StatusOK, buf.Bytes()).
+		SetHeader("Content-Type", format.ContentType(len(frames))).
+		SetHeader("Content-Disposition", fmt.Sprintf(`attachment;filename="%s"`, format.FileName("query-results", len(frames))))
+}
+
+// swagger:parameters queryMetricsExport
+type QueryMetricsExportParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:path
+	// required:true
+	PanelID int64 `json:"panelId"`
+	// File format of the export.
+	// in:query
+	// required:false
+	// enum: csv,parquet,xlsx
+	// default: csv
+	Format string `json:"format"`
+	// in:body
+	// required:true
+	Body dtos.MetricRequest `json:"body"`
+}
+
+// swagger:response queryMetricsExportResponse
+type QueryMetricsExportResponse struct {
+	// in: body
+	Body []byte `json:"body"`
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestHTTPServer_QueryMetricsExport(t *testing.T) {
+	dashboardData, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{
+				"id": 1,
+				"datasource": { "type": "prometheus", "uid": "ds" },
+				"targets": [{ "refId": "A", "expr": "up" }]
+			}
+		]
+	}`))
+	require.NoError(t, err)
+
+	setup := func(t *testing.T, maxBytes int64) *webtest.Server {
+		t.Helper()
+
+		origNew, origNewByUID, origNewByDashboard, origNewByFolder := guardian.New, guardian.NewByUID, guardian.NewByDashboard, guardian.NewByFolder
+		t.Cleanup(func() {
+			guardian.New, guardian.NewByUID, guardian.NewByDashboard, guardian.NewByFolder = origNew, origNewByUID, origNewByDashboard, origNewByFolder
+		})
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true})
+
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{ID: 1, UID: "1", OrgID: 1, Data: dashboardData}, nil)
+
+		queryService := &query.FakeQueryService{}
+		queryService.On("QueryData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{
+			Responses: backend.Responses{
+				"A": {Frames: data.Frames{data.NewFrame("A", data.NewField("value", nil, []float64{1, 2, 3, 4, 5}))}},
+			},
+		}, nil)
+
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			cfg := setting.NewCfg()
+			cfg.QueryExport = setting.QueryExportSettings{Enabled: true, MaxRows: 1000, MaxBytes: maxBytes}
+			hs.Cfg = cfg
+			hs.DashboardService = dashboardService
+			hs.queryDataService = queryService
+		})
+	}
+
+	send := func(t *testing.T, server *webtest.Server) *http.Response {
+		t.Helper()
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewPostRequest("/api/dashboards/uid/1/panels/1/query/export", strings.NewReader(`{"queries":[]}`)),
+			userWithPermissions(1, []accesscontrol.Permission{
+				{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:1"},
+				{Action: datasources.ActionQuery, Scope: datasources.ScopeAll},
+			}),
+		))
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, res.Body.Close()) })
+		return res
+	}
+
+	t.Run("should return the exported file", func(t *testing.T) {
+		res := send(t, setup(t, 1024))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, `attachment;filename="query-results.csv"`, res.Header.Get("Content-Disposition"))
+	})
+
+	t.Run("should reject exports exceeding the maximum size before sending the file", func(t *testing.T) {
+		res := send(t, setup(t, 10))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+		assert.Empty(t, res.Header.Get("Content-Disposition"))
+	})
+}
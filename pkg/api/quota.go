@@ -1,12 +1,14 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -205,3 +207,102 @@ type GetQuotaResponseResponse struct {
 	// in:body
 	Body []*quota.QuotaDTO `json:"body"`
 }
+
+// swagger:route GET /teams/{team_id}/quotas teams getTeamQuota
+//
+// Fetch team quota.
+//
+// You need to have a permission with action `teams:read` and scope `teams:id:1`.
+//
+// Responses:
+// 200: getQuotaResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetTeamQuotas(c *contextmodel.ReqContext) response.Response {
+	teamID, errResp := hs.teamIDFromQuotaRequest(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	q, err := hs.QuotaService.GetQuotasByScope(c.Req.Context(), quota.TeamScope, teamID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get team quotas", err)
+	}
+	return response.JSON(http.StatusOK, q)
+}
+
+// swagger:route PUT /teams/{team_id}/quotas/{quota_target} teams updateTeamQuota
+//
+// Update team quota.
+//
+// Only available to Grafana server admins.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateTeamQuota(c *contextmodel.ReqContext) response.Response {
+	cmd := quota.UpdateQuotaCmd{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Err(quota.ErrBadRequest.Errorf("bad request data: %w", err))
+	}
+
+	teamID, errResp := hs.teamIDFromQuotaRequest(c)
+	if errResp != nil {
+		return errResp
+	}
+	cmd.TeamID = teamID
+	cmd.Target = web.Params(c.Req)[":target"]
+
+	if err := hs.QuotaService.Update(c.Req.Context(), &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update team quotas", err)
+	}
+	return response.Success("Team quota updated")
+}
+
+// teamIDFromQuotaRequest returns the team id of the request, making sure the team belongs to the current org
+func (hs *HTTPServer) teamIDFromQuotaRequest(c *contextmodel.ReqContext) (int64, response.Response) {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return 0, response.Err(quota.ErrBadRequest.Errorf("teamId is invalid: %w", err))
+	}
+
+	if _, err := hs.teamService.GetTeamByID(c.Req.Context(), &team.GetTeamByIDQuery{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		ID:           teamID,
+		SignedInUser: c.SignedInUser,
+	}); err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return 0, response.Error(http.StatusNotFound, "Team not found", err)
+		}
+		return 0, response.Error(http.StatusInternalServerError, "Failed to get team", err)
+	}
+	return teamID, nil
+}
+
+// swagger:parameters getTeamQuota
+type GetTeamQuotaParams struct {
+	// in:path
+	// required:true
+	TeamID int64 `json:"team_id"`
+}
+
+// swagger:parameters updateTeamQuota
+type UpdateTeamQuotaParams struct {
+	// in:body
+	// required:true
+	Body quota.UpdateQuotaCmd `json:"body"`
+	// in:path
+	// required:true
+	QuotaTarget string `json:"quota_target"`
+	// in:path
+	// required:true
+	TeamID int64 `json:"team_id"`
+}
@@ -67,6 +67,11 @@ type PreconditionFailedError GenericError
 // swagger:response unprocessableEntityError
 type UnprocessableEntityError GenericError
 
+// ContentTooLargeError is returned when the requested content exceeds the configured size limit.
+//
+// swagger:response contentTooLargeError
+type ContentTooLargeError GenericError
+
 // InternalServerError is a general error indicating something went wrong internally.
 //
 // swagger:response internalServerError
@@ -0,0 +1,403 @@
+package datamigrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"xorm.io/core"
+	"xorm.io/xorm"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const defaultMigrateDatabaseBatchSize = 500
+
+// leadingTables are copied before any other table. Grafana doesn't declare foreign keys, but copying the tables
+// other rows refer to first keeps the target consistent if the copy is interrupted.
+var leadingTables = []string{
+	"org",
+	"user",
+	"org_user",
+	"team",
+	"team_member",
+	"role",
+	"folder",
+	"dashboard",
+	"data_source",
+}
+
+// MigrateDatabase copies the content of the configured database into another database backend, for instance to move
+// an instance from SQLite to Postgres. The migrations are run on the target first, then every table is copied with
+// values converted to the column types of the target, sequences are reset and row counts are verified.
+func MigrateDatabase(c utils.CommandLine, cfg *setting.Cfg, sqlStore db.DB) error {
+	ss, ok := sqlStore.(*sqlstore.SQLStore)
+	if !ok {
+		return fmt.Errorf("unsupported database store %T", sqlStore)
+	}
+
+	targetType := c.String("target-type")
+	switch targetType {
+	case migrator.Postgres, migrator.MySQL:
+	default:
+		return fmt.Errorf("unsupported target database type %q, expected %s or %s", targetType, migrator.Postgres, migrator.MySQL)
+	}
+	if targetType == ss.GetDialect().DriverName() {
+		return fmt.Errorf("the target database type must differ from the current one (%s)", targetType)
+	}
+
+	connectionString := c.String("target-connection-string")
+	if connectionString == "" {
+		return errors.New("--target-connection-string is required")
+	}
+
+	batchSize := c.Int("batch-size")
+	if batchSize <= 0 {
+		batchSize = defaultMigrateDatabaseBatchSize
+	}
+
+	target, err := xorm.NewEngine(targetType, connectionString)
+	if err != nil {
+		return fmt.Errorf("connecting to target database: %w", err)
+	}
+	defer func() { _ = target.Close() }()
+	if err := target.Ping(); err != nil {
+		return fmt.Errorf("connecting to target database: %w", err)
+	}
+
+	logger.Infof("Running migrations on the target %s database\n", targetType)
+	if err := ss.MigrateEngine(target); err != nil {
+		return fmt.Errorf("running migrations on target database: %w", err)
+	}
+
+	if !c.Bool("force") {
+		var users int64
+		if _, err := target.SQL("SELECT COUNT(*) FROM " + target.Quote("user")).Get(&users); err != nil {
+			return fmt.Errorf("checking target database: %w", err)
+		}
+		if users > 0 {
+			return errors.New("the target database already contains users, use --force to replace its content")
+		}
+	}
+
+	m := &databaseMigration{
+		source:    ss.GetEngine(),
+		target:    target,
+		batchSize: batchSize,
+	}
+	return m.run(context.Background())
+}
+
+type databaseMigration struct {
+	source    *xorm.Engine
+	target    *xorm.Engine
+	batchSize int
+}
+
+func (m *databaseMigration) run(ctx context.Context) error {
+	sourceTables, err := m.source.DBMetas()
+	if err != nil {
+		return fmt.Errorf("reading source schema: %w", err)
+	}
+	targetTables, err := m.target.DBMetas()
+	if err != nil {
+		return fmt.Errorf("reading target schema: %w", err)
+	}
+
+	targetByName := make(map[string]*core.Table, len(targetTables))
+	for _, t := range targetTables {
+		targetByName[t.Name] = t
+	}
+
+	var tables []tableCopy
+	for _, sourceTable := range sourceTables {
+		if isMigrationLogTable(sourceTable.Name) {
+			continue
+		}
+		targetTable, ok := targetByName[sourceTable.Name]
+		if !ok {
+			logger.Warnf("Skipping table %s, it doesn't exist on the target database\n", sourceTable.Name)
+			continue
+		}
+		tables = append(tables, newTableCopy(sourceTable, targetTable))
+	}
+	sortTables(tables)
+
+	for _, t := range tables {
+		start := time.Now()
+		copied, err := m.copyTable(ctx, t)
+		if err != nil {
+			return fmt.Errorf("copying table %s: %w", t.name, err)
+		}
+		logger.Infof("%s Copied %d rows of %s in %s\n", color.GreenString("✔"), copied, t.name, time.Since(start).Round(time.Millisecond))
+	}
+
+	if err := m.resetSequences(tables); err != nil {
+		return err
+	}
+
+	return m.verify(tables)
+}
+
+// tableCopy describes the columns of a table that exist on both databases
+type tableCopy struct {
+	name          string
+	columns       []*core.Column
+	autoIncrement string
+}
+
+func newTableCopy(source, target *core.Table) tableCopy {
+	t := tableCopy{name: target.Name, autoIncrement: target.AutoIncrement}
+	for _, name := range target.ColumnsSeq() {
+		if source.GetColumn(name) == nil {
+			continue
+		}
+		t.columns = append(t.columns, target.GetColumn(name))
+	}
+	return t
+}
+
+func isMigrationLogTable(name string) bool {
+	return name == "migration_log" || strings.HasSuffix(name, "_migration_log")
+}
+
+// sortTables orders the tables by leadingTables, then by name
+func sortTables(tables []tableCopy) {
+	rank := func(name string) int {
+		for i, t := range leadingTables {
+			if t == name {
+				return i
+			}
+		}
+		return len(leadingTables)
+	}
+	sort.SliceStable(tables, func(i, j int) bool {
+		ri, rj := rank(tables[i].name), rank(tables[j].name)
+		if ri != rj {
+			return ri < rj
+		}
+		return tables[i].name < tables[j].name
+	})
+}
+
+// copyTable replaces the rows of the target table with the ones of the source table. The copy is done in a transaction
+// on the target, so a failing copy leaves the table as it was.
+func (m *databaseMigration) copyTable(ctx context.Context, t tableCopy) (int64, error) {
+	if len(t.columns) == 0 {
+		return 0, nil
+	}
+
+	sess := m.target.NewSession().Context(ctx)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return 0, err
+	}
+
+	copied, err := m.copyRows(ctx, sess, t)
+	if err != nil {
+		if rbErr := sess.Rollback(); rbErr != nil {
+			logger.Errorf("Rolling back the copy of %s: %s\n", t.name, rbErr)
+		}
+		return 0, err
+	}
+	return copied, sess.Commit()
+}
+
+func (m *databaseMigration) copyRows(ctx context.Context, sess *xorm.Session, t tableCopy) (int64, error) {
+	sourceColumns := make([]string, 0, len(t.columns))
+	targetColumns := make([]string, 0, len(t.columns))
+	for _, col := range t.columns {
+		sourceColumns = append(sourceColumns, m.source.Quote(col.Name))
+		targetColumns = append(targetColumns, m.target.Quote(col.Name))
+	}
+
+	// the target may contain rows created by the migrations, the source has them as well
+	if _, err := sess.Exec("DELETE FROM " + m.target.Quote(t.name)); err != nil {
+		return 0, fmt.Errorf("emptying target table: %w", err)
+	}
+
+	rows, err := m.source.DB().DB.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s", strings.Join(sourceColumns, ", "), m.source.Quote(t.name)))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	insertPrefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", m.target.Quote(t.name), strings.Join(targetColumns, ", "))
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(t.columns)), ", ") + ")"
+
+	var copied int64
+	batch := make([][]any, 0, m.batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		values := make([]string, 0, len(batch))
+		args := make([]any, 0, len(batch)*len(t.columns)+1)
+		args = append(args, "")
+		for _, row := range batch {
+			values = append(values, placeholders)
+			args = append(args, row...)
+		}
+		args[0] = insertPrefix + strings.Join(values, ", ")
+		if _, err := sess.Exec(args...); err != nil {
+			return err
+		}
+		copied += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+
+	for rows.Next() {
+		raw := make([]any, len(t.columns))
+		dest := make([]any, len(t.columns))
+		for i := range raw {
+			dest[i] = &raw[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return copied, err
+		}
+		for i, col := range t.columns {
+			v, err := convertValue(raw[i], col)
+			if err != nil {
+				return copied, fmt.Errorf("column %s: %w", col.Name, err)
+			}
+			raw[i] = v
+		}
+		batch = append(batch, raw)
+		if len(batch) >= m.batchSize {
+			if err := flush(); err != nil {
+				return copied, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return copied, err
+	}
+	return copied, flush()
+}
+
+// resetSequences moves the Postgres sequences past the copied ids, MySQL and SQLite adjust auto increments on insert
+func (m *databaseMigration) resetSequences(tables []tableCopy) error {
+	if m.target.DriverName() != migrator.Postgres {
+		return nil
+	}
+	for _, t := range tables {
+		if t.autoIncrement == "" {
+			continue
+		}
+		table, column := m.target.Quote(t.name), m.target.Quote(t.autoIncrement)
+		query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', '%s'), COALESCE(MAX(%s), 1), MAX(%s) IS NOT NULL) FROM %s",
+			table, t.autoIncrement, column, column, table)
+		if _, err := m.target.Exec(query); err != nil {
+			return fmt.Errorf("resetting sequence of %s: %w", t.name, err)
+		}
+	}
+	return nil
+}
+
+func (m *databaseMigration) verify(tables []tableCopy) error {
+	var mismatches []string
+	for _, t := range tables {
+		var sourceCount, targetCount int64
+		if _, err := m.source.SQL("SELECT COUNT(*) FROM " + m.source.Quote(t.name)).Get(&sourceCount); err != nil {
+			return fmt.Errorf("counting rows of %s: %w", t.name, err)
+		}
+		if _, err := m.target.SQL("SELECT COUNT(*) FROM " + m.target.Quote(t.name)).Get(&targetCount); err != nil {
+			return fmt.Errorf("counting rows of %s: %w", t.name, err)
+		}
+		if sourceCount != targetCount {
+			mismatches = append(mismatches, fmt.Sprintf("%s (source: %d, target: %d)", t.name, sourceCount, targetCount))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("row counts differ for %d tables: %s", len(mismatches), strings.Join(mismatches, ", "))
+	}
+	logger.Infof("%s Verified row counts of %d tables\n", color.GreenString("✔"), len(tables))
+	return nil
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// convertValue converts a value read from the source database to the type of the target column. SQLite stores
+// booleans as integers and may return times and texts as strings or bytes.
+func convertValue(value any, col *core.Column) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch {
+	case col.SQLType.Name == core.Bool || col.SQLType.Name == core.Boolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case int64:
+			return v != 0, nil
+		case float64:
+			return v != 0, nil
+		case []byte:
+			return strconv.ParseBool(string(v))
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case col.SQLType.IsTime():
+		switch v := value.(type) {
+		case time.Time:
+			return v, nil
+		case int64:
+			return time.Unix(v, 0).UTC(), nil
+		case []byte:
+			return parseTime(string(v))
+		case string:
+			return parseTime(v)
+		}
+	case col.SQLType.IsBlob():
+		switch v := value.(type) {
+		case string:
+			return []byte(v), nil
+		default:
+			return v, nil
+		}
+	case col.SQLType.IsText():
+		switch v := value.(type) {
+		case []byte:
+			// Postgres doesn't accept NUL characters in texts
+			return strings.ReplaceAll(string(v), "\x00", ""), nil
+		case string:
+			return strings.ReplaceAll(v, "\x00", ""), nil
+		default:
+			return v, nil
+		}
+	default:
+		if v, ok := value.([]byte); ok {
+			return string(v), nil
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("can't convert %T to %s", value, col.SQLType.Name)
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("can't parse time %q", s)
+}
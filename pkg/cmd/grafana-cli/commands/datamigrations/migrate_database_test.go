@@ -0,0 +1,62 @@
+package datamigrations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/core"
+)
+
+func TestConvertValue(t *testing.T) {
+	column := func(sqlType string) *core.Column {
+		return &core.Column{Name: "c", SQLType: core.SQLType{Name: sqlType}}
+	}
+
+	tests := []struct {
+		name     string
+		value    any
+		column   *core.Column
+		expected any
+	}{
+		{name: "nil", value: nil, column: column(core.Bool), expected: nil},
+		{name: "integer to bool", value: int64(1), column: column(core.Bool), expected: true},
+		{name: "string to bool", value: "0", column: column(core.Boolean), expected: false},
+		{name: "string to time", value: "2024-03-01 10:20:30", column: column(core.DateTime), expected: time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{name: "bytes to time", value: []byte("2024-03-01T10:20:30Z"), column: column(core.TimeStamp), expected: time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{name: "string to blob", value: "abc", column: column(core.Blob), expected: []byte("abc")},
+		{name: "bytes to text", value: []byte("a\x00b"), column: column(core.Text), expected: "ab"},
+		{name: "integer", value: int64(42), column: column(core.BigInt), expected: int64(42)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := convertValue(tt.value, tt.column)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+
+	t.Run("invalid time", func(t *testing.T) {
+		_, err := convertValue("yesterday", column(core.DateTime))
+		require.Error(t, err)
+	})
+}
+
+func TestSortTables(t *testing.T) {
+	tables := []tableCopy{{name: "star"}, {name: "dashboard"}, {name: "alert_rule"}, {name: "user"}, {name: "org"}}
+	sortTables(tables)
+
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		names = append(names, table.name)
+	}
+	assert.Equal(t, []string{"org", "user", "dashboard", "alert_rule", "star"}, names)
+}
+
+func TestIsMigrationLogTable(t *testing.T) {
+	assert.True(t, isMigrationLogTable("migration_log"))
+	assert.True(t, isMigrationLogTable("unifiedstorage_migration_log"))
+	assert.False(t, isMigrationLogTable("dashboard"))
+}
@@ -71,6 +71,17 @@ type DataSourceCreated struct {
 	OrgID     int64     `json:"org_id"`
 }
 
+type DataSourceHealthChanged struct {
+	Timestamp      time.Time `json:"timestamp"`
+	Name           string    `json:"name"`
+	UID            string    `json:"uid"`
+	OrgID          int64     `json:"org_id"`
+	Type           string    `json:"type"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status"`
+	Message        string    `json:"message"`
+}
+
 type FolderTitleUpdated struct {
 	Timestamp time.Time `json:"timestamp"`
 	Title     string    `json:"name"`
@@ -0,0 +1,418 @@
+package httpclientprovider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics/metricutil"
+)
+
+const DataSourceLimitsMiddlewareName = "datasource-limits"
+
+const defaultCircuitBreakerOpenDuration = 30 * time.Second
+
+// idleLimiterTTL is how long the limiter of a data source is kept without requests, e.g. after it was deleted
+const idleLimiterTTL = time.Hour
+
+// ErrCircuitOpen is returned without contacting the data source while its circuit breaker is open.
+var ErrCircuitOpen = errors.New("data source circuit breaker is open")
+
+var (
+	datasourceLimitsRejectedCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "grafana",
+			Name:      "datasource_request_limits_rejected_total",
+			Help:      "A counter for outgoing data source requests rejected by the data source limits",
+		},
+		[]string{"datasource", "datasource_type", "reason"},
+	)
+
+	datasourceLimitsWaitHistogram = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "grafana",
+			Name:      "datasource_request_limits_wait_duration_seconds",
+			Help:      "histogram of the time outgoing data source requests waited for the concurrency and rate limits",
+			Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"datasource", "datasource_type"},
+	)
+
+	datasourceCircuitBreakerStateGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "grafana",
+			Name:      "datasource_circuit_breaker_state",
+			Help:      "The state of the data source circuit breaker: 0 closed, 1 half-open, 2 open",
+		},
+		[]string{"datasource", "datasource_type"},
+	)
+)
+
+// DataSourceLimits are the per data source settings protecting the data source backend. They are read from the
+// data source jsonData, a zero value disables the corresponding limit.
+type DataSourceLimits struct {
+	MaxConcurrentRequests          int
+	MaxRequestsPerSecond           float64
+	CircuitBreakerFailureThreshold int
+	CircuitBreakerOpenDuration     time.Duration
+}
+
+func (l DataSourceLimits) enabled() bool {
+	return l.MaxConcurrentRequests > 0 || l.MaxRequestsPerSecond > 0 || l.CircuitBreakerFailureThreshold > 0
+}
+
+// dataSourceLimiters holds the limiters by organization and data source UID, so that every HTTP client of a data
+// source shares them
+var dataSourceLimiters = struct {
+	sync.Mutex
+	byKey     map[string]*dataSourceLimiter
+	lastPrune time.Time
+}{byKey: map[string]*dataSourceLimiter{}}
+
+// DataSourceLimitsMiddleware limits the number of concurrent requests and the request rate sent to a data source and
+// stops sending requests to it for a while after consecutive server errors or timeouts.
+func DataSourceLimitsMiddleware(logger log.Logger) sdkhttpclient.Middleware {
+	return sdkhttpclient.NamedMiddlewareFunc(DataSourceLimitsMiddlewareName, func(opts sdkhttpclient.Options, next http.RoundTripper) http.RoundTripper {
+		if opts.Labels == nil {
+			return next
+		}
+		uid, exists := opts.Labels["datasource_uid"]
+		if !exists || uid == "" {
+			return next
+		}
+
+		key := dataSourceLimiterKey(opts.Labels["datasource_org_id"], uid)
+
+		limits := dataSourceLimitsFromOptions(opts)
+		if !limits.enabled() {
+			// the limits may have been removed from the data source settings
+			removeDataSourceLimiter(key)
+			return next
+		}
+
+		limiter := getDataSourceLimiter(key, limits, dataSourceLimitsLabels(opts.Labels), logger.New("datasource", uid))
+		return sdkhttpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return limiter.roundTrip(req, next)
+		})
+	})
+}
+
+// dataSourceLimiterKey identifies the limiter of a data source. Data source UIDs are only unique within an
+// organization, clients created without the organization label are keyed by UID.
+func dataSourceLimiterKey(orgID string, uid string) string {
+	if orgID == "" {
+		return uid
+	}
+	return orgID + "/" + uid
+}
+
+func dataSourceLimitsLabels(labels map[string]string) prometheus.Labels {
+	name, err := metricutil.SanitizeLabelName(labels["datasource_name"])
+	if err != nil {
+		name = "unknown"
+	}
+	dsType, err := metricutil.SanitizeLabelName(labels["datasource_type"])
+	if err != nil {
+		dsType = "unknown"
+	}
+	return prometheus.Labels{"datasource": name, "datasource_type": dsType}
+}
+
+// dataSourceJSONData returns the jsonData of the data source the client is created for
+func dataSourceJSONData(opts sdkhttpclient.Options) map[string]any {
+	if data, ok := opts.CustomOptions["grafanaData"].(map[string]any); ok {
+		return data
+	}
+	return opts.CustomOptions
+}
+
+func dataSourceLimitsFromOptions(opts sdkhttpclient.Options) DataSourceLimits {
+	jsonData := dataSourceJSONData(opts)
+	limits := DataSourceLimits{
+		MaxConcurrentRequests:          int(numberOption(jsonData, "maxConcurrentRequests")),
+		MaxRequestsPerSecond:           numberOption(jsonData, "maxRequestsPerSecond"),
+		CircuitBreakerFailureThreshold: int(numberOption(jsonData, "circuitBreakerFailureThreshold")),
+		CircuitBreakerOpenDuration:     defaultCircuitBreakerOpenDuration,
+	}
+	if seconds := numberOption(jsonData, "circuitBreakerOpenDuration"); seconds > 0 {
+		limits.CircuitBreakerOpenDuration = time.Duration(seconds * float64(time.Second))
+	}
+	return limits
+}
+
+func numberOption(jsonData map[string]any, key string) float64 {
+	var value float64
+	switch v := jsonData[key].(type) {
+	case float64:
+		value = v
+	case int:
+		value = float64(v)
+	case int64:
+		value = float64(v)
+	case json.Number:
+		value, _ = v.Float64()
+	case string:
+		value, _ = strconv.ParseFloat(v, 64)
+	}
+	if value < 0 {
+		return 0
+	}
+	return value
+}
+
+// getDataSourceLimiter returns the limiter of a data source, replacing it when the data source settings changed
+func getDataSourceLimiter(key string, limits DataSourceLimits, labels prometheus.Labels, logger log.Logger) *dataSourceLimiter {
+	dataSourceLimiters.Lock()
+	defer dataSourceLimiters.Unlock()
+
+	pruneDataSourceLimiters(time.Now())
+
+	if limiter, ok := dataSourceLimiters.byKey[key]; ok && limiter.limits == limits {
+		return limiter
+	}
+	limiter := newDataSourceLimiter(limits, labels, logger)
+	dataSourceLimiters.byKey[key] = limiter
+	return limiter
+}
+
+func removeDataSourceLimiter(key string) {
+	dataSourceLimiters.Lock()
+	defer dataSourceLimiters.Unlock()
+	delete(dataSourceLimiters.byKey, key)
+}
+
+// pruneDataSourceLimiters removes the limiters of data sources that didn't send requests for a while, such as deleted
+// data sources. It must be called with the dataSourceLimiters lock held.
+func pruneDataSourceLimiters(now time.Time) {
+	if now.Sub(dataSourceLimiters.lastPrune) < idleLimiterTTL {
+		return
+	}
+	dataSourceLimiters.lastPrune = now
+
+	for key, limiter := range dataSourceLimiters.byKey {
+		if limiter.idle(now) {
+			delete(dataSourceLimiters.byKey, key)
+		}
+	}
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitHalfOpen
+	circuitOpen
+)
+
+type dataSourceLimiter struct {
+	limits      DataSourceLimits
+	concurrency chan struct{}
+	rate        *rate.Limiter
+	logger      log.Logger
+	now         func() time.Time
+
+	rejected     *prometheus.CounterVec
+	waitDuration prometheus.Observer
+	state        prometheus.Gauge
+
+	// lastUsed is the unix time in nanoseconds of the last request
+	lastUsed atomic.Int64
+
+	mu                  sync.Mutex
+	circuit             circuitState
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool
+}
+
+func newDataSourceLimiter(limits DataSourceLimits, labels prometheus.Labels, logger log.Logger) *dataSourceLimiter {
+	l := &dataSourceLimiter{
+		limits:       limits,
+		logger:       logger,
+		now:          time.Now,
+		rejected:     datasourceLimitsRejectedCounter.MustCurryWith(labels),
+		waitDuration: datasourceLimitsWaitHistogram.With(labels),
+		state:        datasourceCircuitBreakerStateGauge.With(labels),
+	}
+	if limits.MaxConcurrentRequests > 0 {
+		l.concurrency = make(chan struct{}, limits.MaxConcurrentRequests)
+	}
+	if limits.MaxRequestsPerSecond > 0 {
+		burst := int(limits.MaxRequestsPerSecond)
+		if burst < 1 {
+			burst = 1
+		}
+		l.rate = rate.NewLimiter(rate.Limit(limits.MaxRequestsPerSecond), burst)
+	}
+	l.state.Set(float64(circuitClosed))
+	l.lastUsed.Store(l.now().UnixNano())
+	return l
+}
+
+// idle reports whether the limiter has no request in flight and wasn't used for idleLimiterTTL
+func (l *dataSourceLimiter) idle(now time.Time) bool {
+	if l.concurrency != nil && len(l.concurrency) > 0 {
+		return false
+	}
+	return now.Sub(time.Unix(0, l.lastUsed.Load())) >= idleLimiterTTL
+}
+
+func (l *dataSourceLimiter) roundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	l.lastUsed.Store(l.now().UnixNano())
+
+	probe, err := l.allow()
+	if err != nil {
+		l.rejected.WithLabelValues("circuit_open").Inc()
+		return nil, err
+	}
+
+	release, err := l.wait(req.Context())
+	if err != nil {
+		if probe {
+			l.releaseProbe()
+		}
+		return nil, err
+	}
+
+	res, err := next.RoundTrip(req)
+	l.record(probe, isDataSourceFailure(res, err))
+
+	// the request takes its concurrency slot until the response body is read and closed. Upgraded connections
+	// keep their body as is, since it must stay writable.
+	if err != nil || res == nil || res.Body == nil || res.StatusCode == http.StatusSwitchingProtocols {
+		release()
+		return res, err
+	}
+	res.Body = &releaseOnCloseBody{ReadCloser: res.Body, release: release}
+	return res, nil
+}
+
+// releaseOnCloseBody releases the concurrency slot of a request when its response body is closed
+type releaseOnCloseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// wait blocks until the request is allowed by the concurrency and rate limits or the request is canceled
+func (l *dataSourceLimiter) wait(ctx context.Context) (func(), error) {
+	start := time.Now()
+	release := func() {}
+
+	if l.concurrency != nil {
+		select {
+		case l.concurrency <- struct{}{}:
+			release = func() { <-l.concurrency }
+		case <-ctx.Done():
+			l.rejected.WithLabelValues("concurrency").Inc()
+			return nil, ctx.Err()
+		}
+	}
+
+	if l.rate != nil {
+		if err := l.rate.Wait(ctx); err != nil {
+			release()
+			l.rejected.WithLabelValues("rate").Inc()
+			return nil, err
+		}
+	}
+
+	l.waitDuration.Observe(time.Since(start).Seconds())
+	return release, nil
+}
+
+// allow reports whether a request can be sent with respect to the circuit breaker, and whether it's the probe
+// request of a half-open circuit
+func (l *dataSourceLimiter) allow() (bool, error) {
+	if l.limits.CircuitBreakerFailureThreshold <= 0 {
+		return false, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch l.circuit {
+	case circuitOpen:
+		if l.now().Sub(l.openedAt) < l.limits.CircuitBreakerOpenDuration {
+			return false, ErrCircuitOpen
+		}
+		l.setState(circuitHalfOpen)
+		l.probing = true
+		return true, nil
+	case circuitHalfOpen:
+		if l.probing {
+			return false, ErrCircuitOpen
+		}
+		l.probing = true
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (l *dataSourceLimiter) releaseProbe() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.probing = false
+}
+
+func (l *dataSourceLimiter) record(probe bool, failed bool) {
+	if l.limits.CircuitBreakerFailureThreshold <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if probe {
+		l.probing = false
+	}
+
+	if !failed {
+		l.consecutiveFailures = 0
+		if l.circuit != circuitClosed {
+			l.logger.Info("Data source circuit breaker closed")
+			l.setState(circuitClosed)
+		}
+		return
+	}
+
+	l.consecutiveFailures++
+	if probe || (l.circuit == circuitClosed && l.consecutiveFailures >= l.limits.CircuitBreakerFailureThreshold) {
+		l.logger.Warn("Data source circuit breaker opened", "consecutiveFailures", l.consecutiveFailures, "openDuration", l.limits.CircuitBreakerOpenDuration)
+		l.openedAt = l.now()
+		l.setState(circuitOpen)
+	}
+}
+
+func (l *dataSourceLimiter) setState(state circuitState) {
+	l.circuit = state
+	l.state.Set(float64(state))
+}
+
+// isDataSourceFailure reports whether a response counts as a failure of the data source for the circuit breaker.
+// Requests canceled by Grafana are not the data source's fault.
+func isDataSourceFailure(res *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return res != nil && res.StatusCode >= http.StatusInternalServerError
+}
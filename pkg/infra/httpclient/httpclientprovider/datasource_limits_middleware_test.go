@@ -0,0 +1,231 @@
+package httpclientprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestDataSourceLimitsMiddleware(t *testing.T) {
+	t.Run("Without limits configured should return next http.RoundTripper", func(t *testing.T) {
+		ctx := &testContext{}
+		finalRoundTripper := ctx.createRoundTripper("final")
+		mw := DataSourceLimitsMiddleware(log.NewNopLogger())
+		rt := mw.CreateMiddleware(httpclient.Options{
+			Labels: map[string]string{"datasource_uid": "no-limits"},
+		}, finalRoundTripper)
+		require.NotNil(t, rt)
+		middlewareName, ok := mw.(httpclient.MiddlewareName)
+		require.True(t, ok)
+		require.Equal(t, DataSourceLimitsMiddlewareName, middlewareName.MiddlewareName())
+
+		req, err := http.NewRequest(http.MethodGet, "http://", nil)
+		require.NoError(t, err)
+		res, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		if res.Body != nil {
+			require.NoError(t, res.Body.Close())
+		}
+		require.Equal(t, []string{"final"}, ctx.callChain)
+	})
+
+	t.Run("Should read limits from the data source json data", func(t *testing.T) {
+		limits := dataSourceLimitsFromOptions(httpclient.Options{
+			CustomOptions: map[string]any{
+				"grafanaData": map[string]any{
+					"maxConcurrentRequests":          json.Number("4"),
+					"maxRequestsPerSecond":           2.5,
+					"circuitBreakerFailureThreshold": "3",
+					"circuitBreakerOpenDuration":     10,
+				},
+			},
+		})
+		require.Equal(t, DataSourceLimits{
+			MaxConcurrentRequests:          4,
+			MaxRequestsPerSecond:           2.5,
+			CircuitBreakerFailureThreshold: 3,
+			CircuitBreakerOpenDuration:     10 * time.Second,
+		}, limits)
+
+		limits = dataSourceLimitsFromOptions(httpclient.Options{CustomOptions: map[string]any{"maxConcurrentRequests": -1}})
+		require.False(t, limits.enabled())
+		require.Equal(t, defaultCircuitBreakerOpenDuration, limits.CircuitBreakerOpenDuration)
+	})
+
+	t.Run("Should share the limiter between clients of a data source until its settings change", func(t *testing.T) {
+		labels := prometheus.Labels{"datasource": "shared", "datasource_type": "test"}
+		limits := DataSourceLimits{MaxConcurrentRequests: 1}
+		first := getDataSourceLimiter("1/shared", limits, labels, log.NewNopLogger())
+		require.Same(t, first, getDataSourceLimiter("1/shared", limits, labels, log.NewNopLogger()))
+		require.NotSame(t, first, getDataSourceLimiter("2/shared", limits, labels, log.NewNopLogger()))
+
+		limits.MaxConcurrentRequests = 2
+		require.NotSame(t, first, getDataSourceLimiter("1/shared", limits, labels, log.NewNopLogger()))
+	})
+
+	t.Run("Should key the limiters by organization and data source UID", func(t *testing.T) {
+		require.Equal(t, "1/uid", dataSourceLimiterKey("1", "uid"))
+		require.Equal(t, "uid", dataSourceLimiterKey("", "uid"))
+	})
+
+	t.Run("Should remove the limiter when the limits are removed from the data source", func(t *testing.T) {
+		labels := map[string]string{"datasource_uid": "removed", "datasource_org_id": "1"}
+		mw := DataSourceLimitsMiddleware(log.NewNopLogger())
+		mw.CreateMiddleware(httpclient.Options{Labels: labels, CustomOptions: map[string]any{"maxConcurrentRequests": 1}}, (&testContext{}).createRoundTripper("final"))
+		dataSourceLimiters.Lock()
+		require.Contains(t, dataSourceLimiters.byKey, "1/removed")
+		dataSourceLimiters.Unlock()
+
+		mw.CreateMiddleware(httpclient.Options{Labels: labels}, (&testContext{}).createRoundTripper("final"))
+		dataSourceLimiters.Lock()
+		require.NotContains(t, dataSourceLimiters.byKey, "1/removed")
+		dataSourceLimiters.Unlock()
+	})
+
+	t.Run("Should prune the limiters of data sources without requests", func(t *testing.T) {
+		labels := prometheus.Labels{"datasource": "idle", "datasource_type": "test"}
+		idle := getDataSourceLimiter("1/idle", DataSourceLimits{MaxConcurrentRequests: 1}, labels, log.NewNopLogger())
+		busy := getDataSourceLimiter("1/busy", DataSourceLimits{MaxConcurrentRequests: 1}, labels, log.NewNopLogger())
+		release, err := busy.wait(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		now := time.Now().Add(idleLimiterTTL)
+		require.True(t, idle.idle(now))
+		require.False(t, busy.idle(now))
+
+		dataSourceLimiters.Lock()
+		defer dataSourceLimiters.Unlock()
+		dataSourceLimiters.lastPrune = time.Time{}
+		pruneDataSourceLimiters(now)
+		require.NotContains(t, dataSourceLimiters.byKey, "1/idle")
+		require.Contains(t, dataSourceLimiters.byKey, "1/busy")
+	})
+
+	t.Run("Should hold the concurrency slot until the response body is closed", func(t *testing.T) {
+		limiter := newTestDataSourceLimiter(DataSourceLimits{MaxConcurrentRequests: 1})
+		var fail bool
+		next := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if fail {
+				return nil, errors.New("connection refused")
+			}
+			return &http.Response{StatusCode: http.StatusOK, Request: req, Body: io.NopCloser(bytes.NewBufferString("body"))}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodGet, "http://", nil)
+		require.NoError(t, err)
+		res, err := limiter.roundTrip(req, next)
+		require.NoError(t, err)
+		require.Len(t, limiter.concurrency, 1)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, "body", string(body))
+		require.Len(t, limiter.concurrency, 1)
+		require.NoError(t, res.Body.Close())
+		require.NoError(t, res.Body.Close())
+		require.Len(t, limiter.concurrency, 0)
+
+		fail = true
+		_, err = limiter.roundTrip(req, next)
+		require.Error(t, err)
+		require.Len(t, limiter.concurrency, 0)
+	})
+
+	t.Run("Should wait for a concurrency slot until the request is canceled", func(t *testing.T) {
+		limiter := newTestDataSourceLimiter(DataSourceLimits{MaxConcurrentRequests: 1})
+		release, err := limiter.wait(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = limiter.wait(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		release()
+		release, err = limiter.wait(context.Background())
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("Should open the circuit after consecutive failures and close it after a successful probe", func(t *testing.T) {
+		limiter := newTestDataSourceLimiter(DataSourceLimits{CircuitBreakerFailureThreshold: 2, CircuitBreakerOpenDuration: time.Minute})
+		now := time.Now()
+		limiter.now = func() time.Time { return now }
+
+		status := http.StatusInternalServerError
+		var calls int
+		next := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{StatusCode: status, Request: req, Body: io.NopCloser(bytes.NewBufferString(""))}, nil
+		})
+		roundTrip := func() error {
+			req, err := http.NewRequest(http.MethodGet, "http://", nil)
+			require.NoError(t, err)
+			res, err := limiter.roundTrip(req, next)
+			if res != nil {
+				require.NoError(t, res.Body.Close())
+			}
+			return err
+		}
+
+		require.NoError(t, roundTrip())
+		require.NoError(t, roundTrip())
+		require.Equal(t, circuitOpen, limiter.circuit)
+
+		require.ErrorIs(t, roundTrip(), ErrCircuitOpen)
+		require.Equal(t, 2, calls)
+
+		// the probe fails, the circuit opens again
+		now = now.Add(time.Minute)
+		require.NoError(t, roundTrip())
+		require.Equal(t, circuitOpen, limiter.circuit)
+		require.ErrorIs(t, roundTrip(), ErrCircuitOpen)
+
+		// the probe succeeds, the circuit closes
+		now = now.Add(time.Minute)
+		status = http.StatusOK
+		require.NoError(t, roundTrip())
+		require.Equal(t, circuitClosed, limiter.circuit)
+		require.NoError(t, roundTrip())
+		require.Equal(t, 5, calls)
+	})
+
+	t.Run("Should only allow a single probe while the circuit is half-open", func(t *testing.T) {
+		limiter := newTestDataSourceLimiter(DataSourceLimits{CircuitBreakerFailureThreshold: 1, CircuitBreakerOpenDuration: time.Minute})
+		now := time.Now()
+		limiter.now = func() time.Time { return now }
+		limiter.record(false, true)
+		require.Equal(t, circuitOpen, limiter.circuit)
+
+		now = now.Add(time.Minute)
+		probe, err := limiter.allow()
+		require.NoError(t, err)
+		require.True(t, probe)
+		_, err = limiter.allow()
+		require.ErrorIs(t, err, ErrCircuitOpen)
+	})
+
+	t.Run("Should count transport errors and server errors as failures", func(t *testing.T) {
+		require.True(t, isDataSourceFailure(nil, errors.New("timeout")))
+		require.False(t, isDataSourceFailure(nil, context.Canceled))
+		require.True(t, isDataSourceFailure(&http.Response{StatusCode: http.StatusBadGateway}, nil))
+		require.False(t, isDataSourceFailure(&http.Response{StatusCode: http.StatusNotFound}, nil))
+	})
+}
+
+func newTestDataSourceLimiter(limits DataSourceLimits) *dataSourceLimiter {
+	return newDataSourceLimiter(limits, prometheus.Labels{"datasource": "test", "datasource_type": "test"}, log.NewNopLogger())
+}
@@ -22,6 +22,7 @@ func New(cfg *setting.Cfg, validator validations.PluginRequestValidator, tracer
 	logger := log.New("httpclient")
 
 	middlewares := []sdkhttpclient.Middleware{
+		RetryMiddleware(logger),
 		TracingMiddleware(logger, tracer),
 		DataSourceMetricsMiddleware(),
 		sdkhttpclient.ContextualMiddleware(),
@@ -30,6 +31,7 @@ func New(cfg *setting.Cfg, validator validations.PluginRequestValidator, tracer
 		sdkhttpclient.CustomHeadersMiddleware(),
 		sdkhttpclient.ResponseLimitMiddleware(cfg.ResponseLimit),
 		RedirectLimitMiddleware(validator),
+		DataSourceLimitsMiddleware(logger),
 	}
 
 	if cfg.SigV4AuthEnabled {
@@ -0,0 +1,234 @@
+package httpclientprovider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const RetryMiddlewareName = "retry"
+
+const (
+	defaultRetryInitialBackoff = 200 * time.Millisecond
+	defaultRetryMaxBackoff     = 5 * time.Second
+	// maxDrainBytes is the maximum number of bytes read from a discarded response so that its connection can be reused
+	maxDrainBytes = 64 << 10
+)
+
+var datasourceRetriesCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "datasource_request_retries_total",
+		Help:      "A counter for outgoing data source requests retried after a transient failure",
+	},
+	[]string{"datasource", "datasource_type", "reason"},
+)
+
+type retryAttemptKey struct{}
+
+// retryAttemptFromContext returns the number of times the request was resent, 0 for the first attempt
+func retryAttemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(retryAttemptKey{}).(int)
+	return attempt
+}
+
+// RetrySettings are the per data source retry settings read from the data source jsonData
+type RetrySettings struct {
+	// MaxAttempts is the maximum number of attempts including the first one, retries are disabled below 2
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func (s RetrySettings) enabled() bool {
+	return s.MaxAttempts > 1
+}
+
+// RetryMiddleware resends idempotent requests failing with a transport error or a transient status code, waiting
+// with an exponential backoff with jitter or for the delay requested by the Retry-After header of the response.
+// It must run before the tracing and metrics middlewares so that every attempt is traced and counted.
+func RetryMiddleware(logger log.Logger) sdkhttpclient.Middleware {
+	return sdkhttpclient.NamedMiddlewareFunc(RetryMiddlewareName, func(opts sdkhttpclient.Options, next http.RoundTripper) http.RoundTripper {
+		settings := retrySettingsFromOptions(opts)
+		if !settings.enabled() {
+			return next
+		}
+
+		r := &retrier{
+			settings: settings,
+			logger:   logger.New("datasource", opts.Labels["datasource_uid"]),
+			retries:  datasourceRetriesCounter.MustCurryWith(dataSourceLimitsLabels(opts.Labels)),
+			jitter:   rand.Int63n,
+		}
+		return sdkhttpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return r.roundTrip(req, next)
+		})
+	})
+}
+
+func retrySettingsFromOptions(opts sdkhttpclient.Options) RetrySettings {
+	jsonData := dataSourceJSONData(opts)
+	settings := RetrySettings{
+		MaxAttempts:    int(numberOption(jsonData, "retryMaxAttempts")),
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+	}
+	if ms := numberOption(jsonData, "retryInitialBackoffMs"); ms > 0 {
+		settings.InitialBackoff = time.Duration(ms * float64(time.Millisecond))
+	}
+	if ms := numberOption(jsonData, "retryMaxBackoffMs"); ms > 0 {
+		settings.MaxBackoff = time.Duration(ms * float64(time.Millisecond))
+	}
+	if settings.MaxBackoff < settings.InitialBackoff {
+		settings.MaxBackoff = settings.InitialBackoff
+	}
+	return settings
+}
+
+type retrier struct {
+	settings RetrySettings
+	logger   log.Logger
+	retries  *prometheus.CounterVec
+	jitter   func(n int64) int64
+}
+
+func (r *retrier) roundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	if !isIdempotent(req) {
+		return next.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			var err error
+			if attemptReq, err = rewindRequest(req, context.WithValue(ctx, retryAttemptKey{}, attempt)); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := next.RoundTrip(attemptReq)
+		if attempt+1 >= r.settings.MaxAttempts {
+			return res, err
+		}
+
+		reason, retry := retryReason(res, err)
+		if !retry {
+			return res, err
+		}
+
+		delay := r.backoff(attempt)
+		if res != nil {
+			if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+				// don't hold the query longer than configured, the data source asked for more than we are willing to wait
+				if retryAfter > r.settings.MaxBackoff {
+					return res, err
+				}
+				delay = retryAfter
+			}
+			drainAndClose(res)
+		}
+
+		r.retries.WithLabelValues(reason).Inc()
+		r.logger.Debug("Retrying data source request", "attempt", attempt+1, "reason", reason, "delay", delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns an exponential backoff with full jitter for the given attempt
+func (r *retrier) backoff(attempt int) time.Duration {
+	delay := r.settings.InitialBackoff
+	for i := 0; i < attempt && delay < r.settings.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > r.settings.MaxBackoff {
+		delay = r.settings.MaxBackoff
+	}
+	return time.Duration(r.jitter(int64(delay)) + 1)
+}
+
+// isIdempotent reports whether a request can safely be sent again
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	// the body of the first attempt is consumed, it must be possible to get a new one
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func rewindRequest(req *http.Request, ctx context.Context) (*http.Request, error) {
+	r := req.Clone(ctx)
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+// retryReason reports whether a request should be retried and why
+func retryReason(res *http.Response, err error) (string, bool) {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrCircuitOpen) {
+			return "", false
+		}
+		return "error", true
+	}
+	if res == nil {
+		return "", false
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return strconv.Itoa(res.StatusCode), true
+	}
+	return "", false
+}
+
+// parseRetryAfter parses a Retry-After header value in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func drainAndClose(res *http.Response) {
+	if res.Body == nil {
+		return
+	}
+	_, _ = io.CopyN(io.Discard, res.Body, maxDrainBytes)
+	_ = res.Body.Close()
+}
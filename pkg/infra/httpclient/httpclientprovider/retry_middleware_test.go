@@ -0,0 +1,193 @@
+package httpclientprovider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestRetryMiddleware(t *testing.T) {
+	t.Run("Without retries configured should return next http.RoundTripper", func(t *testing.T) {
+		ctx := &testContext{}
+		finalRoundTripper := ctx.createRoundTripper("final")
+		mw := RetryMiddleware(log.NewNopLogger())
+		rt := mw.CreateMiddleware(httpclient.Options{
+			CustomOptions: map[string]any{"grafanaData": map[string]any{"retryMaxAttempts": 1}},
+		}, finalRoundTripper)
+		require.NotNil(t, rt)
+		middlewareName, ok := mw.(httpclient.MiddlewareName)
+		require.True(t, ok)
+		require.Equal(t, RetryMiddlewareName, middlewareName.MiddlewareName())
+
+		req, err := http.NewRequest(http.MethodGet, "http://", nil)
+		require.NoError(t, err)
+		res, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		if res.Body != nil {
+			require.NoError(t, res.Body.Close())
+		}
+		require.Equal(t, []string{"final"}, ctx.callChain)
+	})
+
+	t.Run("Should read retry settings from the data source json data", func(t *testing.T) {
+		settings := retrySettingsFromOptions(httpclient.Options{
+			CustomOptions: map[string]any{
+				"grafanaData": map[string]any{
+					"retryMaxAttempts":      "3",
+					"retryInitialBackoffMs": 50,
+					"retryMaxBackoffMs":     1000,
+				},
+			},
+		})
+		require.Equal(t, RetrySettings{MaxAttempts: 3, InitialBackoff: 50 * time.Millisecond, MaxBackoff: time.Second}, settings)
+
+		settings = retrySettingsFromOptions(httpclient.Options{})
+		require.False(t, settings.enabled())
+		require.Equal(t, defaultRetryInitialBackoff, settings.InitialBackoff)
+		require.Equal(t, defaultRetryMaxBackoff, settings.MaxBackoff)
+	})
+
+	t.Run("Should retry transient failures until the request succeeds", func(t *testing.T) {
+		r := newTestRetrier(3)
+		statuses := []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}
+		var attempts []int
+		var bodies []string
+		next := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts = append(attempts, retryAttemptFromContext(req.Context()))
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			bodies = append(bodies, string(body))
+			status := statuses[len(attempts)-1]
+			return &http.Response{StatusCode: status, Request: req, Body: io.NopCloser(bytes.NewBufferString(""))}, nil
+		})
+
+		req, err := http.NewRequest(http.MethodPut, "http://", strings.NewReader("body"))
+		require.NoError(t, err)
+		res, err := r.roundTrip(req, next)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, []int{0, 1, 2}, attempts)
+		require.Equal(t, []string{"body", "body", "body"}, bodies)
+	})
+
+	t.Run("Should return the last response when attempts are exhausted", func(t *testing.T) {
+		r := newTestRetrier(2)
+		var calls int
+		next := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return nil, errors.New("connection refused")
+		})
+
+		req, err := http.NewRequest(http.MethodGet, "http://", nil)
+		require.NoError(t, err)
+		_, err = r.roundTrip(req, next)
+		require.EqualError(t, err, "connection refused")
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("Should not retry non idempotent requests, client errors and canceled requests", func(t *testing.T) {
+		r := newTestRetrier(3)
+		var calls int
+		status := http.StatusServiceUnavailable
+		var callErr error
+		next := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			if callErr != nil {
+				return nil, callErr
+			}
+			return &http.Response{StatusCode: status, Request: req, Body: io.NopCloser(bytes.NewBufferString(""))}, nil
+		})
+		roundTrip := func(method string) {
+			req, err := http.NewRequest(method, "http://", nil)
+			require.NoError(t, err)
+			res, _ := r.roundTrip(req, next)
+			if res != nil {
+				require.NoError(t, res.Body.Close())
+			}
+		}
+
+		roundTrip(http.MethodPost)
+		require.Equal(t, 1, calls)
+
+		calls, status = 0, http.StatusBadRequest
+		roundTrip(http.MethodGet)
+		require.Equal(t, 1, calls)
+
+		calls, callErr = 0, context.Canceled
+		roundTrip(http.MethodGet)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Should honour Retry-After up to the maximum backoff", func(t *testing.T) {
+		r := newTestRetrier(2)
+		r.settings.MaxBackoff = 2 * time.Second
+		retryAfter := "0"
+		var calls int
+		next := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			res := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}, Request: req, Body: io.NopCloser(bytes.NewBufferString(""))}
+			res.Header.Set("Retry-After", retryAfter)
+			return res, nil
+		})
+		roundTrip := func() {
+			req, err := http.NewRequest(http.MethodGet, "http://", nil)
+			require.NoError(t, err)
+			res, err := r.roundTrip(req, next)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+		}
+
+		roundTrip()
+		require.Equal(t, 2, calls)
+
+		calls, retryAfter = 0, "120"
+		roundTrip()
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Should compute an exponential backoff capped by the maximum backoff", func(t *testing.T) {
+		r := newTestRetrier(5)
+		r.settings.InitialBackoff = 100 * time.Millisecond
+		r.settings.MaxBackoff = 300 * time.Millisecond
+		r.jitter = func(n int64) int64 { return n - 1 }
+		require.Equal(t, 100*time.Millisecond, r.backoff(0))
+		require.Equal(t, 200*time.Millisecond, r.backoff(1))
+		require.Equal(t, 300*time.Millisecond, r.backoff(2))
+		require.Equal(t, 300*time.Millisecond, r.backoff(10))
+	})
+
+	t.Run("Should parse Retry-After in seconds or as an HTTP date", func(t *testing.T) {
+		now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+		delay, ok := parseRetryAfter("3", now)
+		require.True(t, ok)
+		require.Equal(t, 3*time.Second, delay)
+
+		delay, ok = parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)
+		require.True(t, ok)
+		require.Equal(t, time.Minute, delay)
+
+		_, ok = parseRetryAfter("soon", now)
+		require.False(t, ok)
+	})
+}
+
+func newTestRetrier(maxAttempts int) *retrier {
+	return &retrier{
+		settings: RetrySettings{MaxAttempts: maxAttempts, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		logger:   log.NewNopLogger(),
+		retries:  datasourceRetriesCounter.MustCurryWith(dataSourceLimitsLabels(nil)),
+		jitter:   func(n int64) int64 { return 0 },
+	}
+}
@@ -20,6 +20,7 @@ import (
 const (
 	TracingMiddlewareName   = "tracing"
 	httpContentLengthTagKey = "http.content_length"
+	httpResendCountTagKey   = "http.resend_count"
 )
 
 func TracingMiddleware(logger log.Logger, tracer tracing.Tracer) httpclient.Middleware {
@@ -33,6 +34,9 @@ func TracingMiddleware(logger log.Logger, tracer tracing.Tracer) httpclient.Midd
 			for k, v := range opts.Labels {
 				span.SetAttributes(attribute.String(k, v))
 			}
+			if attempt := retryAttemptFromContext(ctx); attempt > 0 {
+				span.SetAttributes(attribute.Int(httpResendCountTagKey, attempt))
+			}
 
 			tracer.Inject(ctx, req.Header, span)
 			res, err := next.RoundTrip(req)
@@ -477,6 +477,15 @@ func ReadLoggingConfig(modes []string, logsPath string, cfg *ini.File) error {
 			sysLogHandler := NewSyslog(sec, format)
 			loggersToClose = append(loggersToClose, sysLogHandler)
 			handler.val = sysLogHandler.logger
+		case "otlp":
+			otlpHandler, err := NewOTLPHandler(sec)
+			if err != nil {
+				_ = level.Error(root).Log("Failed to initialize OTLP log handler", "address", sec.Key("address").String(), "err", err)
+				continue
+			}
+
+			loggersToClose = append(loggersToClose, otlpHandler)
+			handler.val = otlpHandler
 		}
 		if handler.val == nil {
 			panic(fmt.Sprintf("Handler is uninitialized for mode %q", mode))
@@ -0,0 +1,295 @@
+package log
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"gopkg.in/ini.v1"
+)
+
+const otlpExportTimeout = 10 * time.Second
+
+// OTLPHandler exports log records to an OpenTelemetry collector over OTLP/gRPC. Records are queued and exported in
+// batches, they are dropped when the queue is full so that logging never blocks Grafana.
+type OTLPHandler struct {
+	Address      string
+	Insecure     bool
+	ServiceName  string
+	BatchSize    int
+	BatchTimeout time.Duration
+	QueueSize    int
+
+	conn    *grpc.ClientConn
+	export  func(ctx context.Context, records []*logspb.LogRecord) error
+	queue   chan *logspb.LogRecord
+	done    chan struct{}
+	stopped chan struct{}
+
+	closeOnce     sync.Once
+	errorMu       sync.Mutex
+	lastErrorTime time.Time
+	dropped       int
+}
+
+func NewOTLPHandler(sec *ini.Section) (*OTLPHandler, error) {
+	h := &OTLPHandler{
+		Address:      sec.Key("address").MustString("localhost:4317"),
+		Insecure:     sec.Key("insecure").MustBool(true),
+		ServiceName:  sec.Key("service_name").MustString("grafana"),
+		BatchSize:    sec.Key("batch_size").MustInt(512),
+		BatchTimeout: sec.Key("batch_timeout").MustDuration(5 * time.Second),
+		QueueSize:    sec.Key("queue_size").MustInt(2048),
+	}
+
+	creds := insecure.NewCredentials()
+	if !h.Insecure {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+	conn, err := grpc.Dial(h.Address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to OTLP endpoint %s: %w", h.Address, err)
+	}
+	h.conn = conn
+	h.export = h.exportGRPC(collogspb.NewLogsServiceClient(conn))
+	h.start()
+	return h, nil
+}
+
+func (h *OTLPHandler) start() {
+	if h.BatchSize <= 0 {
+		h.BatchSize = 512
+	}
+	if h.QueueSize < h.BatchSize {
+		h.QueueSize = h.BatchSize
+	}
+	if h.BatchTimeout <= 0 {
+		h.BatchTimeout = 5 * time.Second
+	}
+	h.queue = make(chan *logspb.LogRecord, h.QueueSize)
+	h.done = make(chan struct{})
+	h.stopped = make(chan struct{})
+	go h.run()
+}
+
+// Log converts the key values to a log record and queues it for export
+func (h *OTLPHandler) Log(keyvals ...any) error {
+	record := newOTLPLogRecord(now(), keyvals...)
+	select {
+	case <-h.done:
+		return nil
+	default:
+	}
+
+	select {
+	case h.queue <- record:
+	default:
+		h.errorMu.Lock()
+		h.dropped++
+		h.errorMu.Unlock()
+	}
+	return nil
+}
+
+func (h *OTLPHandler) run() {
+	defer close(h.stopped)
+
+	ticker := time.NewTicker(h.BatchTimeout)
+	defer ticker.Stop()
+
+	batch := make([]*logspb.LogRecord, 0, h.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		h.flush(batch)
+		batch = make([]*logspb.LogRecord, 0, h.BatchSize)
+	}
+
+	for {
+		select {
+		case record := <-h.queue:
+			batch = append(batch, record)
+			if len(batch) >= h.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-h.done:
+			// export what is left in the queue before stopping
+			for {
+				select {
+				case record := <-h.queue:
+					batch = append(batch, record)
+					if len(batch) >= h.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (h *OTLPHandler) flush(batch []*logspb.LogRecord) {
+	ctx, cancel := context.WithTimeout(context.Background(), otlpExportTimeout)
+	defer cancel()
+	if err := h.export(ctx, batch); err != nil {
+		h.reportError(err, len(batch))
+	}
+}
+
+// reportError writes export failures to stderr, at most once a minute, as they can't be logged by the logger itself
+func (h *OTLPHandler) reportError(err error, records int) {
+	h.errorMu.Lock()
+	defer h.errorMu.Unlock()
+
+	h.dropped += records
+	if time.Since(h.lastErrorTime) < time.Minute {
+		return
+	}
+	h.lastErrorTime = time.Now()
+	_, _ = fmt.Fprintf(os.Stderr, "Failed to export logs to OTLP endpoint %s, %d log records dropped: %v\n", h.Address, h.dropped, err)
+	h.dropped = 0
+}
+
+func (h *OTLPHandler) exportGRPC(client collogspb.LogsServiceClient) func(ctx context.Context, records []*logspb.LogRecord) error {
+	resource := &resourcepb.Resource{
+		Attributes: []*commonpb.KeyValue{otlpKeyValue("service.name", h.ServiceName)},
+	}
+	hostname, err := os.Hostname()
+	if err == nil {
+		resource.Attributes = append(resource.Attributes, otlpKeyValue("host.name", hostname))
+	}
+
+	return func(ctx context.Context, records []*logspb.LogRecord) error {
+		_, err := client.Export(ctx, &collogspb.ExportLogsServiceRequest{
+			ResourceLogs: []*logspb.ResourceLogs{{
+				Resource: resource,
+				ScopeLogs: []*logspb.ScopeLogs{{
+					Scope:      &commonpb.InstrumentationScope{Name: "github.com/grafana/grafana/pkg/infra/log"},
+					LogRecords: records,
+				}},
+			}},
+		})
+		return err
+	}
+}
+
+// Close exports the queued log records and closes the connection
+func (h *OTLPHandler) Close() error {
+	h.closeOnce.Do(func() {
+		close(h.done)
+		<-h.stopped
+	})
+	if h.conn != nil {
+		return h.conn.Close()
+	}
+	return nil
+}
+
+// newOTLPLogRecord converts the key values of a log line to an OTLP log record. The message becomes the body, the
+// level the severity and the traceID and spanID added by the tracing contextual log provider the trace context.
+func newOTLPLogRecord(t time.Time, keyvals ...any) *logspb.LogRecord {
+	record := &logspb.LogRecord{
+		TimeUnixNano:         uint64(t.UnixNano()),
+		ObservedTimeUnixNano: uint64(t.UnixNano()),
+		SeverityNumber:       logspb.SeverityNumber_SEVERITY_NUMBER_INFO,
+		SeverityText:         "info",
+	}
+
+	for i := 0; i < len(keyvals); i += 2 {
+		key := keyvals[i]
+		var value any
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+
+		if key == level.Key() {
+			if lvl, ok := value.(level.Value); ok {
+				record.SeverityText = lvl.String()
+				record.SeverityNumber = otlpSeverity(lvl)
+			}
+			continue
+		}
+
+		k := fmt.Sprint(key)
+		switch k {
+		case "t":
+			// the timestamp of the record is already set
+		case "msg":
+			record.Body = otlpAnyValue(value)
+		case "traceID":
+			if id, err := hex.DecodeString(fmt.Sprint(value)); err == nil && len(id) == 16 {
+				record.TraceId = id
+			}
+		case "spanID":
+			if id, err := hex.DecodeString(fmt.Sprint(value)); err == nil && len(id) == 8 {
+				record.SpanId = id
+			}
+		default:
+			record.Attributes = append(record.Attributes, &commonpb.KeyValue{Key: k, Value: otlpAnyValue(value)})
+		}
+	}
+	return record
+}
+
+func otlpSeverity(lvl level.Value) logspb.SeverityNumber {
+	switch lvl {
+	case level.DebugValue():
+		return logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG
+	case level.WarnValue():
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case level.ErrorValue():
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	}
+}
+
+func otlpKeyValue(key string, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: otlpAnyValue(value)}
+}
+
+func otlpAnyValue(value any) *commonpb.AnyValue {
+	switch v := value.(type) {
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v}}
+	case int:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case int32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case int64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v}}
+	case uint32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(v)}}
+	case float32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: float64(v)}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v}}
+	case time.Duration:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.String()}}
+	case error:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Error()}}
+	case fmt.Stringer:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.String()}}
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: fmt.Sprint(v)}}
+	}
+}
@@ -0,0 +1,85 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+func TestNewOTLPLogRecord(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	record := newOTLPLogRecord(ts,
+		"t", ts.Format(time.RFC3339),
+		level.Key(), level.WarnValue(),
+		"msg", "hello",
+		"logger", "test",
+		"traceID", "4bf92f3577b34da6a3ce929d0e0e4736",
+		"spanID", "00f067aa0ba902b7",
+		"count", 3,
+		"error", errors.New("boom"),
+	)
+
+	assert.Equal(t, uint64(ts.UnixNano()), record.TimeUnixNano)
+	assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_WARN, record.SeverityNumber)
+	assert.Equal(t, "warn", record.SeverityText)
+	assert.Equal(t, "hello", record.Body.GetStringValue())
+	assert.Len(t, record.TraceId, 16)
+	assert.Len(t, record.SpanId, 8)
+
+	attributes := map[string]any{}
+	for _, kv := range record.Attributes {
+		if v, ok := kv.Value.Value.(*commonpb.AnyValue_IntValue); ok {
+			attributes[kv.Key] = v.IntValue
+			continue
+		}
+		attributes[kv.Key] = kv.Value.GetStringValue()
+	}
+	assert.Equal(t, map[string]any{"logger": "test", "count": int64(3), "error": "boom"}, attributes)
+
+	t.Run("invalid trace context is ignored", func(t *testing.T) {
+		record := newOTLPLogRecord(ts, "msg", "hello", "traceID", "not-a-trace-id")
+		assert.Nil(t, record.TraceId)
+		assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_INFO, record.SeverityNumber)
+	})
+}
+
+func TestOTLPHandler(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]*logspb.LogRecord
+	h := &OTLPHandler{BatchSize: 2, BatchTimeout: time.Hour, QueueSize: 10}
+	h.export = func(_ context.Context, records []*logspb.LogRecord) error {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, records)
+		return nil
+	}
+	h.start()
+
+	for _, msg := range []string{"one", "two", "three"} {
+		require.NoError(t, h.Log(level.Key(), level.InfoValue(), "msg", msg))
+	}
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(batches) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// closing the handler exports the remaining records
+	require.NoError(t, h.Close())
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], 2)
+	require.Len(t, batches[1], 1)
+	assert.Equal(t, "three", batches[1][0].Body.GetStringValue())
+
+	require.NoError(t, h.Log("msg", "after close"))
+	require.NoError(t, h.Close())
+}
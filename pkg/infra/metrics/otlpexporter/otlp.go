@@ -0,0 +1,304 @@
+// Package otlpexporter pushes the metrics of a Prometheus gatherer to an OpenTelemetry collector over OTLP/HTTP.
+package otlpexporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	defaultInterval = 15 * time.Second
+	defaultTimeout  = 10 * time.Second
+
+	scopeName = "github.com/grafana/grafana/pkg/infra/metrics"
+)
+
+// Config defines the OTLP exporter config.
+type Config struct {
+	// The OTLP/HTTP metrics endpoint, for example http://localhost:4318/v1/metrics. Required.
+	URL string
+
+	// Headers sent with every export request, for example for authentication.
+	Headers map[string]string
+
+	// ResourceAttributes identify the Grafana instance the metrics are exported from.
+	ResourceAttributes map[string]string
+
+	// The interval to use for pushing metrics. Defaults to 15 seconds.
+	Interval time.Duration
+
+	// The timeout for pushing metrics. Defaults to 10 seconds.
+	Timeout time.Duration
+
+	// The Gatherer to use for metrics. Defaults to prometheus.DefaultGatherer.
+	Gatherer prometheus.Gatherer
+
+	// The logger that errors are written to.
+	Logger log.Logger
+
+	// The HTTP client used to push metrics. Defaults to a client with the configured timeout.
+	Client *http.Client
+}
+
+// Exporter pushes metrics to the configured OTLP endpoint.
+type Exporter struct {
+	url      string
+	headers  map[string]string
+	interval time.Duration
+	client   *http.Client
+	logger   log.Logger
+	g        prometheus.Gatherer
+
+	resource  *resourcepb.Resource
+	startTime time.Time
+}
+
+// NewExporter returns a pointer to a new Exporter struct.
+func NewExporter(c *Config) (*Exporter, error) {
+	if c.URL == "" {
+		return nil, fmt.Errorf("missing URL")
+	}
+
+	e := &Exporter{
+		url:       c.URL,
+		headers:   c.Headers,
+		interval:  c.Interval,
+		client:    c.Client,
+		logger:    c.Logger,
+		g:         c.Gatherer,
+		resource:  &resourcepb.Resource{Attributes: keyValues(c.ResourceAttributes)},
+		startTime: time.Now(),
+	}
+
+	if e.interval == 0 {
+		e.interval = defaultInterval
+	}
+	if e.client == nil {
+		timeout := c.Timeout
+		if timeout == 0 {
+			timeout = defaultTimeout
+		}
+		e.client = &http.Client{Timeout: timeout}
+	}
+	if e.logger == nil {
+		e.logger = log.NewNopLogger()
+	}
+	if e.g == nil {
+		e.g = prometheus.DefaultGatherer
+	}
+
+	return e, nil
+}
+
+// Run starts the event loop that pushes metrics at the configured interval.
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := e.Push(ctx); err != nil {
+				e.logger.Error("Failed to push metrics to OTLP endpoint", "url", e.url, "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Push gathers the metrics and pushes them to the OTLP endpoint.
+func (e *Exporter) Push(ctx context.Context) error {
+	mfs, err := e.g.Gather()
+	if err != nil && len(mfs) == 0 {
+		return err
+	}
+	if err != nil {
+		// the gatherer returns the metrics it could collect, push them anyway
+		e.logger.Warn("Failed to gather some metrics", "error", err)
+	}
+
+	body, err := proto.Marshal(e.request(mfs, time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("unexpected status code %d: %s", res.StatusCode, msg)
+	}
+	return nil
+}
+
+func (e *Exporter) request(mfs []*dto.MetricFamily, now time.Time) *colmetricspb.ExportMetricsServiceRequest {
+	start, ts := uint64(e.startTime.UnixNano()), uint64(now.UnixNano())
+
+	metrics := make([]*metricspb.Metric, 0, len(mfs))
+	for _, mf := range mfs {
+		if m := convertMetricFamily(mf, start, ts); m != nil {
+			metrics = append(metrics, m)
+		}
+	}
+
+	return &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: e.resource,
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Scope:   &commonpb.InstrumentationScope{Name: scopeName},
+				Metrics: metrics,
+			}},
+		}},
+	}
+}
+
+// convertMetricFamily converts a Prometheus metric family to an OTLP metric. Counters become cumulative monotonic
+// sums, gauges and untyped metrics gauges, histograms cumulative explicit bucket histograms.
+func convertMetricFamily(mf *dto.MetricFamily, start, ts uint64) *metricspb.Metric {
+	m := &metricspb.Metric{Name: mf.GetName(), Description: mf.GetHelp()}
+
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		points := make([]*metricspb.NumberDataPoint, 0, len(mf.Metric))
+		for _, metric := range mf.Metric {
+			points = append(points, numberDataPoint(metric, metric.GetCounter().GetValue(), start, ts))
+		}
+		m.Data = &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+			DataPoints:             points,
+			AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			IsMonotonic:            true,
+		}}
+	case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+		points := make([]*metricspb.NumberDataPoint, 0, len(mf.Metric))
+		for _, metric := range mf.Metric {
+			value := metric.GetGauge().GetValue()
+			if mf.GetType() == dto.MetricType_UNTYPED {
+				value = metric.GetUntyped().GetValue()
+			}
+			points = append(points, numberDataPoint(metric, value, 0, ts))
+		}
+		m.Data = &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{DataPoints: points}}
+	case dto.MetricType_HISTOGRAM:
+		points := make([]*metricspb.HistogramDataPoint, 0, len(mf.Metric))
+		for _, metric := range mf.Metric {
+			points = append(points, histogramDataPoint(metric, start, ts))
+		}
+		m.Data = &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+			DataPoints:             points,
+			AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+		}}
+	case dto.MetricType_SUMMARY:
+		points := make([]*metricspb.SummaryDataPoint, 0, len(mf.Metric))
+		for _, metric := range mf.Metric {
+			s := metric.GetSummary()
+			point := &metricspb.SummaryDataPoint{
+				Attributes:        labelAttributes(metric),
+				StartTimeUnixNano: start,
+				TimeUnixNano:      ts,
+				Count:             s.GetSampleCount(),
+				Sum:               s.GetSampleSum(),
+			}
+			for _, q := range s.GetQuantile() {
+				point.QuantileValues = append(point.QuantileValues, &metricspb.SummaryDataPoint_ValueAtQuantile{
+					Quantile: q.GetQuantile(),
+					Value:    q.GetValue(),
+				})
+			}
+			points = append(points, point)
+		}
+		m.Data = &metricspb.Metric_Summary{Summary: &metricspb.Summary{DataPoints: points}}
+	default:
+		return nil
+	}
+	return m
+}
+
+func numberDataPoint(metric *dto.Metric, value float64, start, ts uint64) *metricspb.NumberDataPoint {
+	return &metricspb.NumberDataPoint{
+		Attributes:        labelAttributes(metric),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      ts,
+		Value:             &metricspb.NumberDataPoint_AsDouble{AsDouble: value},
+	}
+}
+
+// histogramDataPoint converts the cumulative Prometheus buckets to the per bucket counts of OTLP
+func histogramDataPoint(metric *dto.Metric, start, ts uint64) *metricspb.HistogramDataPoint {
+	h := metric.GetHistogram()
+	sum := h.GetSampleSum()
+	point := &metricspb.HistogramDataPoint{
+		Attributes:        labelAttributes(metric),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      ts,
+		Count:             h.GetSampleCount(),
+		Sum:               &sum,
+	}
+
+	var previous uint64
+	for _, b := range h.GetBucket() {
+		if math.IsInf(b.GetUpperBound(), 1) {
+			continue
+		}
+		point.ExplicitBounds = append(point.ExplicitBounds, b.GetUpperBound())
+		point.BucketCounts = append(point.BucketCounts, b.GetCumulativeCount()-previous)
+		previous = b.GetCumulativeCount()
+	}
+	// the last bucket counts the observations above the highest bound
+	point.BucketCounts = append(point.BucketCounts, h.GetSampleCount()-previous)
+	return point
+}
+
+func labelAttributes(metric *dto.Metric) []*commonpb.KeyValue {
+	attributes := make([]*commonpb.KeyValue, 0, len(metric.Label))
+	for _, l := range metric.Label {
+		attributes = append(attributes, keyValue(l.GetName(), l.GetValue()))
+	}
+	return attributes
+}
+
+func keyValues(m map[string]string) []*commonpb.KeyValue {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]*commonpb.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, keyValue(k, m[k]))
+	}
+	return kvs
+}
+
+func keyValue(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
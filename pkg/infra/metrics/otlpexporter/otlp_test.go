@@ -0,0 +1,92 @@
+package otlpexporter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPush(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests_total", Help: "requests"}, []string{"code"})
+	counter.WithLabelValues("200").Add(3)
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "in_flight"})
+	gauge.Set(2)
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "duration_seconds", Buckets: []float64{1, 5}})
+	for _, v := range []float64{0.5, 2, 3, 10} {
+		histogram.Observe(v)
+	}
+	reg.MustRegister(counter, gauge, histogram)
+
+	var received *colmetricspb.ExportMetricsServiceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received = &colmetricspb.ExportMetricsServiceRequest{}
+		require.NoError(t, proto.Unmarshal(body, received))
+	}))
+	t.Cleanup(server.Close)
+
+	e, err := NewExporter(&Config{
+		URL:                server.URL,
+		Headers:            map[string]string{"Authorization": "Bearer token"},
+		ResourceAttributes: map[string]string{"service.name": "grafana", "service.instance.id": "instance-1"},
+		Gatherer:           reg,
+	})
+	require.NoError(t, err)
+	require.NoError(t, e.Push(context.Background()))
+
+	require.Len(t, received.ResourceMetrics, 1)
+	resource := received.ResourceMetrics[0].Resource
+	require.Len(t, resource.Attributes, 2)
+	assert.Equal(t, "service.instance.id", resource.Attributes[0].Key)
+	assert.Equal(t, "instance-1", resource.Attributes[0].Value.GetStringValue())
+
+	metrics := map[string]*metricspb.Metric{}
+	for _, m := range received.ResourceMetrics[0].ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+	require.Len(t, metrics, 3)
+
+	sum := metrics["requests_total"].GetSum()
+	require.NotNil(t, sum)
+	assert.True(t, sum.IsMonotonic)
+	assert.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.AggregationTemporality)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, 3.0, sum.DataPoints[0].GetAsDouble())
+	assert.Equal(t, "code", sum.DataPoints[0].Attributes[0].Key)
+	assert.Equal(t, "200", sum.DataPoints[0].Attributes[0].Value.GetStringValue())
+
+	assert.Equal(t, 2.0, metrics["in_flight"].GetGauge().DataPoints[0].GetAsDouble())
+
+	h := metrics["duration_seconds"].GetHistogram().DataPoints[0]
+	assert.Equal(t, uint64(4), h.Count)
+	assert.Equal(t, 15.5, h.GetSum())
+	assert.Equal(t, []float64{1, 5}, h.ExplicitBounds)
+	assert.Equal(t, []uint64{1, 2, 1}, h.BucketCounts)
+}
+
+func TestPushError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	e, err := NewExporter(&Config{URL: server.URL, Gatherer: prometheus.NewRegistry()})
+	require.NoError(t, err)
+	require.ErrorContains(t, e.Push(context.Background()), "unexpected status code 401")
+
+	_, err = NewExporter(&Config{})
+	require.Error(t, err)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics/graphitebridge"
+	"github.com/grafana/grafana/pkg/infra/metrics/otlpexporter"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -41,6 +42,7 @@ type InternalMetricsService struct {
 
 	intervalSeconds int64
 	graphiteCfg     *graphitebridge.Config
+	otlpCfg         *otlpexporter.Config
 }
 
 func (im *InternalMetricsService) Run(ctx context.Context) error {
@@ -54,6 +56,16 @@ func (im *InternalMetricsService) Run(ctx context.Context) error {
 		}
 	}
 
+	// Start OTLP exporter
+	if im.otlpCfg != nil {
+		exporter, err := otlpexporter.NewExporter(im.otlpCfg)
+		if err != nil {
+			metricsLogger.Error("failed to create otlp metrics exporter", "error", err)
+		} else {
+			go exporter.Run(ctx)
+		}
+	}
+
 	MInstanceStart.Inc()
 
 	<-ctx.Done()
@@ -8,6 +8,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/grafana/grafana/pkg/infra/metrics/graphitebridge"
+	"github.com/grafana/grafana/pkg/infra/metrics/otlpexporter"
 )
 
 func (im *InternalMetricsService) readSettings() error {
@@ -22,6 +23,10 @@ func (im *InternalMetricsService) readSettings() error {
 		return fmt.Errorf("unable to parse metrics graphite section: %w", err)
 	}
 
+	if err := im.parseOTLPSettings(); err != nil {
+		return fmt.Errorf("unable to parse metrics otlp section: %w", err)
+	}
+
 	return nil
 }
 
@@ -59,3 +64,59 @@ func (im *InternalMetricsService) parseGraphiteSettings() error {
 	im.graphiteCfg = bridgeCfg
 	return nil
 }
+
+func (im *InternalMetricsService) parseOTLPSettings() error {
+	otlpSection, err := im.Cfg.Raw.GetSection("metrics.otlp")
+	if err != nil {
+		return nil
+	}
+
+	endpoint := otlpSection.Key("endpoint").String()
+	if endpoint == "" {
+		return nil
+	}
+
+	headers, err := splitKeyValues(otlpSection.Key("headers").String())
+	if err != nil {
+		return fmt.Errorf("invalid headers: %w", err)
+	}
+	attributes, err := splitKeyValues(otlpSection.Key("resource_attributes").String())
+	if err != nil {
+		return fmt.Errorf("invalid resource_attributes: %w", err)
+	}
+
+	attributes["service.name"] = "grafana"
+	attributes["service.version"] = im.Cfg.BuildVersion
+	attributes["service.instance.id"] = im.Cfg.InstanceName
+	if cluster := otlpSection.Key("ha_cluster").String(); cluster != "" {
+		attributes["grafana.ha_cluster"] = cluster
+	}
+
+	im.otlpCfg = &otlpexporter.Config{
+		URL:                endpoint,
+		Headers:            headers,
+		ResourceAttributes: attributes,
+		Interval:           time.Duration(im.intervalSeconds) * time.Second,
+		Timeout:            otlpSection.Key("timeout").MustDuration(10 * time.Second),
+		Gatherer:           prometheus.DefaultGatherer,
+		Logger:             metricsLogger,
+	}
+	return nil
+}
+
+// splitKeyValues parses a comma separated list of key:value pairs
+func splitKeyValues(s string) (map[string]string, error) {
+	res := map[string]string{}
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		parts := strings.SplitN(v, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%q must be in 'key:value' form", v)
+		}
+		res[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return res, nil
+}
@@ -79,7 +79,7 @@ func ProvideService(tracingCfg *TracingConfig) (*TracingService, error) {
 
 	log.RegisterContextualLogProvider(func(ctx context.Context) ([]any, bool) {
 		if traceID := TraceIDFromContext(ctx, false); traceID != "" {
-			return []any{"traceID", traceID}, true
+			return []any{"traceID", traceID, "spanID", SpanIDFromContext(ctx)}, true
 		}
 
 		return nil, false
@@ -109,6 +109,16 @@ func TraceIDFromContext(ctx context.Context, requireSampled bool) string {
 	return spanCtx.TraceID().String()
 }
 
+// SpanIDFromContext returns the ID of the current span, or an empty string when there is none
+func SpanIDFromContext(ctx context.Context) string {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.HasSpanID() {
+		return ""
+	}
+
+	return spanCtx.SpanID().String()
+}
+
 type noopTracerProvider struct {
 	trace.TracerProvider
 }
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// defaultRouteGroup groups the API routes not matching a configured route group
+const defaultRouteGroup = "api"
+
+var (
+	requestsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Subsystem: "api",
+		Name:      "rate_limit_requests_total",
+		Help:      "Number of API requests checked by the rate limiter, by result (allowed, limited or error)",
+	}, []string{"identity_type", "route_group", "result"})
+
+	storeDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "grafana",
+		Subsystem: "api",
+		Name:      "rate_limit_store_duration_seconds",
+		Help:      "Duration of the rate limiter store lookups",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25},
+	})
+)
+
+type Service interface {
+	// Middleware rejects the API requests exceeding the rate limits with 429 Too Many Requests
+	Middleware() web.Handler
+}
+
+type RateLimiter struct {
+	settings setting.RateLimitingSettings
+	store    Store
+	log      log.Logger
+	now      func() time.Time
+}
+
+func ProvideService(cfg *setting.Cfg, remoteCache *remotecache.RemoteCache) *RateLimiter {
+	var store Store = newMemoryStore()
+	if cfg.RateLimiting.Store == setting.RateLimitStoreRemoteCache {
+		store = &remoteCacheStore{cache: remoteCache}
+	}
+	return newRateLimiter(cfg.RateLimiting, store)
+}
+
+func newRateLimiter(settings setting.RateLimitingSettings, store Store) *RateLimiter {
+	return &RateLimiter{
+		settings: settings,
+		store:    store,
+		log:      log.New("ratelimit"),
+		now:      time.Now,
+	}
+}
+
+func (r *RateLimiter) Middleware() web.Handler {
+	return func(c *contextmodel.ReqContext) {
+		if !r.settings.Enabled || c.SignedInUser == nil {
+			return
+		}
+
+		path := c.Req.URL.Path
+		if !strings.HasPrefix(path, "/api/") {
+			return
+		}
+		routeGroup := r.routeGroup(path)
+
+		identityType, id := c.SignedInUser.GetNamespacedID()
+		// anonymous and unauthenticated requests share the same identity, they are limited by client address instead
+		if identityType == "" || identityType == identity.NamespaceAnonymous {
+			identityType, id = identity.NamespaceAnonymous, c.RemoteAddr()
+		}
+		limit, ok := r.settings.Limits[identityType]
+		if !ok || limit.RequestsPerSecond <= 0 {
+			return
+		}
+		orgID := c.SignedInUser.GetOrgID()
+
+		allowed, retryAfter, err := r.take(c, fmt.Sprintf("%s:%s:%d:%s", identityType, id, orgID, routeGroup), limit)
+		if err == nil && allowed && r.settings.Org.RequestsPerSecond > 0 {
+			allowed, retryAfter, err = r.take(c, fmt.Sprintf("org:%d:%s", orgID, routeGroup), r.settings.Org)
+		}
+
+		switch {
+		case err != nil:
+			// the API keeps working when the store is unavailable
+			r.log.Warn("Failed to check rate limit", "error", err)
+			requestsCounter.WithLabelValues(identityType, routeGroup, "error").Inc()
+		case !allowed:
+			requestsCounter.WithLabelValues(identityType, routeGroup, "limited").Inc()
+			c.Resp.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.JsonApiErr(http.StatusTooManyRequests, "Too many requests", nil)
+		default:
+			requestsCounter.WithLabelValues(identityType, routeGroup, "allowed").Inc()
+		}
+	}
+}
+
+func (r *RateLimiter) take(c *contextmodel.ReqContext, key string, limit setting.RateLimit) (bool, time.Duration, error) {
+	start := time.Now()
+	defer func() { storeDuration.Observe(time.Since(start).Seconds()) }()
+	return r.store.Take(c.Req.Context(), key, limit, r.now())
+}
+
+// routeGroup returns the route group with the longest path prefix matching path
+func (r *RateLimiter) routeGroup(path string) string {
+	group, length := defaultRouteGroup, 0
+	for _, g := range r.settings.RouteGroups {
+		if strings.HasPrefix(path, g.PathPrefix) && len(g.PathPrefix) > length {
+			group, length = g.Name, len(g.PathPrefix)
+		}
+	}
+	return group
+}
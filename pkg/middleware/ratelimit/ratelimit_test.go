@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	settings := setting.RateLimitingSettings{
+		Enabled: true,
+		Limits: map[string]setting.RateLimit{
+			"user":            {RequestsPerSecond: 1, Burst: 2},
+			"service-account": {RequestsPerSecond: 1, Burst: 1},
+			"anonymous":       {RequestsPerSecond: 1, Burst: 1},
+		},
+		RouteGroups: []setting.RateLimitRouteGroup{
+			{Name: "query", PathPrefix: "/api/ds/query"},
+			{Name: "query-export", PathPrefix: "/api/ds/query/export"},
+		},
+	}
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: 1}
+
+	t.Run("Should reject requests exceeding the limit with Retry-After", func(t *testing.T) {
+		r := newRateLimiter(settings, newMemoryStore())
+		assert.Equal(t, http.StatusOK, execute(r, "/api/dashboards/uid/abc", signedInUser).Code)
+		assert.Equal(t, http.StatusOK, execute(r, "/api/dashboards/uid/abc", signedInUser).Code)
+
+		resp := execute(r, "/api/dashboards/uid/abc", signedInUser)
+		assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+		assert.Equal(t, "1", resp.Header().Get("Retry-After"))
+	})
+
+	t.Run("Should limit route groups, orgs and identities separately", func(t *testing.T) {
+		r := newRateLimiter(settings, newMemoryStore())
+		for i := 0; i < 2; i++ {
+			assert.Equal(t, http.StatusOK, execute(r, "/api/search", signedInUser).Code)
+		}
+		assert.Equal(t, http.StatusTooManyRequests, execute(r, "/api/search", signedInUser).Code)
+
+		assert.Equal(t, http.StatusOK, execute(r, "/api/ds/query", signedInUser).Code)
+		assert.Equal(t, http.StatusOK, execute(r, "/api/ds/query/export", signedInUser).Code)
+		assert.Equal(t, http.StatusOK, execute(r, "/api/search", &user.SignedInUser{UserID: 1, OrgID: 2}).Code)
+		assert.Equal(t, http.StatusOK, execute(r, "/api/search", &user.SignedInUser{UserID: 2, OrgID: 1}).Code)
+
+		serviceAccount := &user.SignedInUser{UserID: 3, OrgID: 1, IsServiceAccount: true}
+		assert.Equal(t, http.StatusOK, execute(r, "/api/search", serviceAccount).Code)
+		assert.Equal(t, http.StatusTooManyRequests, execute(r, "/api/search", serviceAccount).Code)
+	})
+
+	t.Run("Should limit unauthenticated requests by client address", func(t *testing.T) {
+		r := newRateLimiter(settings, newMemoryStore())
+		assert.Equal(t, http.StatusOK, execute(r, "/api/health-check", &user.SignedInUser{}).Code)
+		assert.Equal(t, http.StatusTooManyRequests, execute(r, "/api/health-check", &user.SignedInUser{IsAnonymous: true}).Code)
+	})
+
+	t.Run("Should apply the org limit to all the identities of an org", func(t *testing.T) {
+		orgSettings := settings
+		orgSettings.Org = setting.RateLimit{RequestsPerSecond: 1, Burst: 1}
+		r := newRateLimiter(orgSettings, newMemoryStore())
+		assert.Equal(t, http.StatusOK, execute(r, "/api/search", signedInUser).Code)
+		assert.Equal(t, http.StatusTooManyRequests, execute(r, "/api/search", &user.SignedInUser{UserID: 2, OrgID: 1}).Code)
+	})
+
+	t.Run("Should not limit requests outside of the API", func(t *testing.T) {
+		r := newRateLimiter(settings, newMemoryStore())
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, http.StatusOK, execute(r, "/d/abc", signedInUser).Code)
+		}
+	})
+
+	t.Run("Should allow requests when the store fails", func(t *testing.T) {
+		r := newRateLimiter(settings, failingStore{})
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, http.StatusOK, execute(r, "/api/search", signedInUser).Code)
+		}
+	})
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, setting.RateLimit, time.Time) (bool, time.Duration, error) {
+	return false, 0, errors.New("unavailable")
+}
+
+func execute(r *RateLimiter, path string, signedInUser *user.SignedInUser) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	c := &contextmodel.ReqContext{
+		Context:      &web.Context{Req: req, Resp: web.NewResponseWriter(http.MethodGet, recorder)},
+		SignedInUser: signedInUser,
+		Logger:       log.NewNopLogger(),
+	}
+
+	r.Middleware().(func(*contextmodel.ReqContext))(c)
+	if !c.Resp.Written() {
+		c.Resp.WriteHeader(http.StatusOK)
+	}
+	return recorder
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Store keeps the token buckets
+type Store interface {
+	// Take takes a token from the bucket identified by key. When the bucket is empty it returns false and the time
+	// until a token is available.
+	Take(ctx context.Context, key string, limit setting.RateLimit, now time.Time) (bool, time.Duration, error)
+}
+
+type bucket struct {
+	Tokens  float64   `json:"tokens"`
+	Updated time.Time `json:"updated"`
+}
+
+func (b *bucket) take(limit setting.RateLimit, now time.Time) (bool, time.Duration) {
+	if b.Updated.IsZero() {
+		b.Tokens = float64(limit.Burst)
+	} else if elapsed := now.Sub(b.Updated).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(float64(limit.Burst), b.Tokens+elapsed*limit.RequestsPerSecond)
+	}
+	b.Updated = now
+
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.Tokens) / limit.RequestsPerSecond * float64(time.Second))
+}
+
+// refillDuration is the time after which an unused bucket is full again and can be forgotten
+func refillDuration(limit setting.RateLimit) time.Duration {
+	return time.Duration(float64(limit.Burst) / limit.RequestsPerSecond * float64(time.Second))
+}
+
+const memoryStoreSweepInterval = time.Minute
+
+type memoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+type memoryBucket struct {
+	bucket
+	expires time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{buckets: map[string]*memoryBucket{}}
+}
+
+func (s *memoryStore) Take(_ context.Context, key string, limit setting.RateLimit, now time.Time) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{}
+		s.buckets[key] = b
+	}
+	allowed, retryAfter := b.take(limit, now)
+	b.expires = now.Add(refillDuration(limit))
+	return allowed, retryAfter, nil
+}
+
+// sweep removes the full buckets, so that the store doesn't grow with every identity that has been seen
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memoryStoreSweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if now.After(b.expires) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// remoteCacheStore keeps the buckets in the remote cache so that they are shared between Grafana instances. Reading
+// and writing a bucket isn't atomic, concurrent requests on different instances may exceed the limit slightly.
+type remoteCacheStore struct {
+	cache remotecache.CacheStorage
+}
+
+const remoteCacheKeyPrefix = "ratelimit-"
+
+func (s *remoteCacheStore) Take(ctx context.Context, key string, limit setting.RateLimit, now time.Time) (bool, time.Duration, error) {
+	var b bucket
+	data, err := s.cache.Get(ctx, remoteCacheKeyPrefix+key)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &b); err != nil {
+			b = bucket{}
+		}
+	case !errors.Is(err, remotecache.ErrCacheItemNotFound):
+		return false, 0, err
+	}
+
+	allowed, retryAfter := b.take(limit, now)
+
+	data, err = json.Marshal(b)
+	if err != nil {
+		return false, 0, err
+	}
+	// a zero expiry means 24h for the remote cache
+	expire := max(refillDuration(limit), time.Second)
+	if err := s.cache.Set(ctx, remoteCacheKeyPrefix+key, data, expire); err != nil {
+		return false, 0, err
+	}
+	return allowed, retryAfter, nil
+}
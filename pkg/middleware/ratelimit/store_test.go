@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestStores(t *testing.T) {
+	stores := map[string]func() Store{
+		"memory":       func() Store { return newMemoryStore() },
+		"remote cache": func() Store { return &remoteCacheStore{cache: remotecache.NewFakeCacheStorage()} },
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+			limit := setting.RateLimit{RequestsPerSecond: 2, Burst: 3}
+			now := time.Now()
+
+			for i := 0; i < 3; i++ {
+				allowed, _, err := store.Take(context.Background(), "key", limit, now)
+				require.NoError(t, err)
+				assert.True(t, allowed)
+			}
+
+			allowed, retryAfter, err := store.Take(context.Background(), "key", limit, now)
+			require.NoError(t, err)
+			assert.False(t, allowed)
+			assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+			// other keys have their own bucket
+			allowed, _, err = store.Take(context.Background(), "other", limit, now)
+			require.NoError(t, err)
+			assert.True(t, allowed)
+
+			// the bucket refills over time
+			allowed, _, err = store.Take(context.Background(), "key", limit, now.Add(500*time.Millisecond))
+			require.NoError(t, err)
+			assert.True(t, allowed)
+			allowed, _, err = store.Take(context.Background(), "key", limit, now.Add(500*time.Millisecond))
+			require.NoError(t, err)
+			assert.False(t, allowed)
+		})
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	store := newMemoryStore()
+	limit := setting.RateLimit{RequestsPerSecond: 1, Burst: 1}
+	now := time.Now()
+
+	_, _, err := store.Take(context.Background(), "old", limit, now)
+	require.NoError(t, err)
+	_, _, err = store.Take(context.Background(), "new", limit, now.Add(2*memoryStoreSweepInterval))
+	require.NoError(t, err)
+
+	assert.Len(t, store.buckets, 1)
+	assert.Contains(t, store.buckets, "new")
+}
@@ -54,7 +54,7 @@ func Test_GetHeuristics(t *testing.T) {
 		httpProvider := newHeuristicsSDKProvider(rt)
 		logger := backend.NewLoggerWith("logger", "test")
 		s := &Service{
-			im:     datasource.NewInstanceManager(newInstanceSettings(httpProvider, logger, mockExtendClientOpts)),
+			im:     datasource.NewInstanceManager(newInstanceSettings(httpProvider, logger, mockExtendClientOpts, nil)),
 			logger: logger,
 		}
 
@@ -76,7 +76,7 @@ func Test_GetHeuristics(t *testing.T) {
 		httpProvider := newHeuristicsSDKProvider(rt)
 		logger := backend.NewLoggerWith("logger", "test")
 		s := &Service{
-			im:     datasource.NewInstanceManager(newInstanceSettings(httpProvider, logger, mockExtendClientOpts)),
+			im:     datasource.NewInstanceManager(newInstanceSettings(httpProvider, logger, mockExtendClientOpts, nil)),
 			logger: logger,
 		}
 
@@ -35,16 +35,25 @@ type instance struct {
 type ExtendOptions func(ctx context.Context, settings backend.DataSourceInstanceSettings, clientOpts *sdkhttpclient.Options) error
 
 func NewService(httpClientProvider *sdkhttpclient.Provider, plog log.Logger, extendOptions ExtendOptions) *Service {
+	return NewServiceWithCache(httpClientProvider, plog, extendOptions, nil)
+}
+
+// NewServiceWithCache creates the service with the cache used by data sources with backend
+// incremental querying enabled. Query results are kept in memory when queryCache is nil.
+func NewServiceWithCache(httpClientProvider *sdkhttpclient.Provider, plog log.Logger, extendOptions ExtendOptions, queryCache querydata.Cache) *Service {
 	if httpClientProvider == nil {
 		httpClientProvider = sdkhttpclient.NewProvider()
 	}
+	if queryCache == nil {
+		queryCache = querydata.NewMemoryCache()
+	}
 	return &Service{
-		im:     datasource.NewInstanceManager(newInstanceSettings(httpClientProvider, plog, extendOptions)),
+		im:     datasource.NewInstanceManager(newInstanceSettings(httpClientProvider, plog, extendOptions, queryCache)),
 		logger: plog,
 	}
 }
 
-func newInstanceSettings(httpClientProvider *sdkhttpclient.Provider, log log.Logger, extendOptions ExtendOptions) datasource.InstanceFactoryFunc {
+func newInstanceSettings(httpClientProvider *sdkhttpclient.Provider, log log.Logger, extendOptions ExtendOptions, queryCache querydata.Cache) datasource.InstanceFactoryFunc {
 	return func(ctx context.Context, settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
 		// Creates a http roundTripper.
 		opts, err := client.CreateTransportOptions(ctx, settings, log)
@@ -65,7 +74,7 @@ func newInstanceSettings(httpClientProvider *sdkhttpclient.Provider, log log.Log
 		}
 
 		// New version using custom client and better response parsing
-		qd, err := querydata.New(httpClient, settings, log)
+		qd, err := querydata.New(httpClient, settings, log, queryCache)
 		if err != nil {
 			return nil, err
 		}
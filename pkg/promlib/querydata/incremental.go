@@ -0,0 +1,335 @@
+package querydata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/data/utils/maputil"
+	"github.com/patrickmn/go-cache"
+
+	"github.com/grafana/grafana/pkg/promlib/client"
+	"github.com/grafana/grafana/pkg/promlib/models"
+)
+
+const (
+	defaultIncrementalOverlapWindow = 10 * time.Minute
+	incrementalCacheTTL             = 10 * time.Minute
+	// defaultMemoryCacheMaxBytes bounds the size of the results kept by the memory cache
+	defaultMemoryCacheMaxBytes = 100 << 20
+)
+
+var errCacheMiss = errors.New("cache miss")
+
+// Cache stores the results of range queries between dashboard refreshes. Its methods
+// match the ones of Grafana's remote cache, so it can be used as is.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, expire time.Duration) error
+}
+
+type memoryCache struct {
+	c        *cache.Cache
+	maxBytes int64
+	// size is the sum of the sizes of the stored results, it is updated when results expire
+	size atomic.Int64
+	// mu serializes the writes so that the size of a replaced result is only removed once
+	mu sync.Mutex
+}
+
+// NewMemoryCache returns a Cache keeping the query results in memory, up to 100MB.
+func NewMemoryCache() Cache {
+	return newMemoryCache(defaultMemoryCacheMaxBytes)
+}
+
+func newMemoryCache(maxBytes int64) *memoryCache {
+	m := &memoryCache{c: cache.New(incrementalCacheTTL, 2*incrementalCacheTTL), maxBytes: maxBytes}
+	m.c.OnEvicted(func(_ string, v any) {
+		m.size.Add(-int64(len(v.([]byte))))
+	})
+	return m
+}
+
+func (m *memoryCache) Get(_ context.Context, key string) ([]byte, error) {
+	v, ok := m.c.Get(key)
+	if !ok {
+		return nil, errCacheMiss
+	}
+	return v.([]byte), nil
+}
+
+// Set stores the value unless the cache is full once the expired results are removed, the query is then run in full
+// next time.
+func (m *memoryCache) Set(_ context.Context, key string, value []byte, expire time.Duration) error {
+	if expire == 0 {
+		expire = cache.DefaultExpiration
+	}
+	size := int64(len(value))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.c.Delete(key)
+	if m.size.Load()+size > m.maxBytes {
+		m.c.DeleteExpired()
+		if m.size.Load()+size > m.maxBytes {
+			return nil
+		}
+	}
+	m.c.Set(key, value, expire)
+	m.size.Add(size)
+	return nil
+}
+
+// incrementalQuerying fetches only the tail of range queries, plus an overlap window
+// for the samples that were not complete yet, and merges it with the previous result.
+type incrementalQuerying struct {
+	cache        Cache
+	overlap      time.Duration
+	dataSourceID int64
+	uid          string
+}
+
+// cachedRange is the result of a range query, the frames are encoded with Arrow
+type cachedRange struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Frames [][]byte  `json:"frames"`
+}
+
+func newIncrementalQuerying(settings backend.DataSourceInstanceSettings, jsonData map[string]any, c Cache) (*incrementalQuerying, error) {
+	enabled, err := maputil.GetBoolOptional(jsonData, "backendIncrementalQuerying")
+	if err != nil {
+		return nil, err
+	}
+	if !enabled || c == nil {
+		return nil, nil
+	}
+
+	overlap := defaultIncrementalOverlapWindow
+	overlapWindow, err := maputil.GetStringOptional(jsonData, "incrementalQueryOverlapWindow")
+	if err != nil {
+		return nil, err
+	}
+	if overlapWindow != "" {
+		overlap, err = gtime.ParseDuration(overlapWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid incremental query overlap window: %w", err)
+		}
+	}
+
+	return &incrementalQuerying{cache: c, overlap: overlap, dataSourceID: settings.ID, uid: settings.UID}, nil
+}
+
+func (s *QueryData) incrementalRangeQuery(ctx context.Context, c *client.Client, q *models.Query, enablePrometheusDataplaneFlag bool, caller incrementalCaller) backend.DataResponse {
+	logger := s.log.FromContext(ctx)
+	key := s.incremental.cacheKey(q, enablePrometheusDataplaneFlag, caller)
+	tr := q.TimeRange()
+
+	cached, cachedFrames, err := s.incremental.get(ctx, key)
+	if err != nil && !errors.Is(err, errCacheMiss) {
+		logger.Debug("Failed to read cached range query result", "error", err)
+	}
+	if cached != nil && (cached.Start.After(tr.Start) || cached.End.Before(tr.Start) || cached.End.After(tr.End)) {
+		// the cached result doesn't cover the beginning of the requested range
+		cached = nil
+	}
+
+	fetch := q
+	if cached != nil {
+		tail := *q
+		tail.Start = cached.End.Add(-s.incremental.overlap)
+		if tail.Start.Before(q.Start) {
+			tail.Start = q.Start
+		}
+		fetch = &tail
+	}
+
+	res := s.rangeQuery(ctx, c, fetch, enablePrometheusDataplaneFlag)
+	if res.Error != nil || !isMatrix(res.Frames) {
+		return res
+	}
+
+	frames := res.Frames
+	if cached != nil {
+		frames = mergeFrames(cachedFrames, res.Frames, tr.Start, fetch.TimeRange().Start)
+		for i, frame := range frames {
+			if frame.Meta == nil {
+				frame.Meta = &data.FrameMeta{}
+			}
+			frame.Meta.ExecutedQueryString = ""
+			if i == 0 {
+				frame.Meta.ExecutedQueryString = executedQueryString(q)
+			}
+		}
+	}
+
+	if err := s.incremental.set(ctx, key, tr, frames); err != nil {
+		logger.Debug("Failed to cache range query result", "error", err)
+	}
+
+	res.Frames = frames
+	return res
+}
+
+// cacheKey identifies the result of a query of a data source for a caller, so that results fetched with the
+// credentials of a user, such as a forwarded OAuth identity, are never served to another one. Data source UIDs are
+// only unique within an organization, hence the organization and the data source ID in the key.
+func (i *incrementalQuerying) cacheKey(q *models.Query, enablePrometheusDataplaneFlag bool, caller incrementalCaller) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n%s\n%d\n%s\n%t", caller.identity, q.Expr, q.Step, q.UtcOffsetSec, q.LegendFormat, enablePrometheusDataplaneFlag)
+	return fmt.Sprintf("prometheus:incremental:%d:%d:%s:%x", caller.orgID, i.dataSourceID, i.uid, h.Sum(nil))
+}
+
+// incrementalCaller is the organization and the identity a query is sent for
+type incrementalCaller struct {
+	orgID    int64
+	identity string
+}
+
+// callerIdentity returns the organization of the request and a hash of the signed in user and of the credentials
+// forwarded to the data source
+func callerIdentity(req *backend.QueryDataRequest) incrementalCaller {
+	h := sha256.New()
+	if user := req.PluginContext.User; user != nil {
+		_, _ = fmt.Fprintf(h, "%s\n", user.Login)
+	}
+	for _, header := range []string{"Authorization", "X-Id-Token", "Cookie"} {
+		_, _ = fmt.Fprintf(h, "%s\n", req.GetHTTPHeader(header))
+	}
+	return incrementalCaller{orgID: req.PluginContext.OrgID, identity: fmt.Sprintf("%x", h.Sum(nil))}
+}
+
+func (i *incrementalQuerying) get(ctx context.Context, key string) (*cachedRange, data.Frames, error) {
+	b, err := i.cache.Get(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	var cached cachedRange
+	if err := json.Unmarshal(b, &cached); err != nil {
+		return nil, nil, err
+	}
+	frames, err := data.UnmarshalArrowFrames(cached.Frames)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &cached, frames, nil
+}
+
+func (i *incrementalQuerying) set(ctx context.Context, key string, tr models.TimeRange, frames data.Frames) error {
+	series := make(data.Frames, 0, len(frames))
+	for _, frame := range frames {
+		if len(frame.Fields) > 0 {
+			series = append(series, frame)
+		}
+	}
+	encoded, err := series.MarshalArrow()
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(cachedRange{Start: tr.Start, End: tr.End, Frames: encoded})
+	if err != nil {
+		return err
+	}
+	return i.cache.Set(ctx, key, b, incrementalCacheTTL)
+}
+
+// isMatrix returns true when all the frames are time series with a single value field.
+// Other results, such as native histograms, are not cached.
+func isMatrix(frames data.Frames) bool {
+	for _, frame := range frames {
+		if len(frame.Fields) == 0 {
+			continue
+		}
+		if len(frame.Fields) != 2 || frame.Fields[0].Type() != data.FieldTypeTime || frame.Fields[1].Type() != data.FieldTypeFloat64 {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeFrames keeps the cached samples between start and the start of the tail query,
+// and appends the samples of the tail query to the series they belong to.
+func mergeFrames(cached, tail data.Frames, start, tailStart time.Time) data.Frames {
+	tailBySeries := make(map[string]*data.Frame, len(tail))
+	for _, frame := range tail {
+		if len(frame.Fields) == 2 {
+			tailBySeries[seriesKey(frame)] = frame
+		}
+	}
+
+	merged := make(data.Frames, 0, len(cached)+len(tail))
+	for _, frame := range cached {
+		if len(frame.Fields) != 2 {
+			continue
+		}
+		key := seriesKey(frame)
+		mergedFrame := sliceFrame(frame, start, tailStart)
+		if tailFrame, ok := tailBySeries[key]; ok {
+			appendRows(mergedFrame, tailFrame)
+			delete(tailBySeries, key)
+		}
+		if mergedFrame.Rows() > 0 {
+			merged = append(merged, mergedFrame)
+		}
+	}
+	for _, frame := range tail {
+		if len(frame.Fields) != 2 {
+			continue
+		}
+		if _, ok := tailBySeries[seriesKey(frame)]; ok {
+			merged = append(merged, frame)
+		}
+	}
+
+	if len(merged) == 0 {
+		// Add frame to attach metadata, same as responses without series
+		merged = append(merged, data.NewFrame(""))
+	}
+	return merged
+}
+
+func seriesKey(frame *data.Frame) string {
+	return frame.Fields[1].Name + frame.Fields[1].Labels.String()
+}
+
+// sliceFrame returns a copy of the frame with the rows in [from, to)
+func sliceFrame(frame *data.Frame, from, to time.Time) *data.Frame {
+	sliced := data.NewFrame(frame.Name)
+	sliced.RefID = frame.RefID
+	sliced.Meta = frame.Meta
+	for _, f := range frame.Fields {
+		field := data.NewFieldFromFieldType(f.Type(), 0)
+		field.Name = f.Name
+		field.Labels = f.Labels
+		field.Config = f.Config
+		sliced.Fields = append(sliced.Fields, field)
+	}
+	for row := 0; row < frame.Rows(); row++ {
+		t := frame.Fields[0].At(row).(time.Time)
+		if t.Before(from) || !t.Before(to) {
+			continue
+		}
+		// Arrow decodes the time values in the local time zone
+		sliced.Fields[0].Append(t.UTC())
+		for i := 1; i < len(frame.Fields); i++ {
+			sliced.Fields[i].Append(frame.Fields[i].CopyAt(row))
+		}
+	}
+	return sliced
+}
+
+func appendRows(frame, rows *data.Frame) {
+	for row := 0; row < rows.Rows(); row++ {
+		for i, f := range rows.Fields {
+			frame.Fields[i].Append(f.CopyAt(row))
+		}
+	}
+}
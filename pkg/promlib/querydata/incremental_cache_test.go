@@ -0,0 +1,62 @@
+package querydata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/promlib/models"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("doesn't store results beyond the size limit", func(t *testing.T) {
+		c := newMemoryCache(10)
+		require.NoError(t, c.Set(ctx, "a", []byte("123456"), 0))
+		require.NoError(t, c.Set(ctx, "b", []byte("123456"), 0))
+
+		_, err := c.Get(ctx, "a")
+		require.NoError(t, err)
+		_, err = c.Get(ctx, "b")
+		require.ErrorIs(t, err, errCacheMiss)
+		require.Equal(t, int64(6), c.size.Load())
+	})
+
+	t.Run("replacing a result doesn't count it twice", func(t *testing.T) {
+		c := newMemoryCache(10)
+		require.NoError(t, c.Set(ctx, "a", []byte("123456"), 0))
+		require.NoError(t, c.Set(ctx, "a", []byte("1234567"), 0))
+
+		v, err := c.Get(ctx, "a")
+		require.NoError(t, err)
+		require.Equal(t, []byte("1234567"), v)
+		require.Equal(t, int64(7), c.size.Load())
+	})
+
+	t.Run("makes room by removing expired results", func(t *testing.T) {
+		c := newMemoryCache(10)
+		require.NoError(t, c.Set(ctx, "a", []byte("123456"), time.Nanosecond))
+		time.Sleep(time.Millisecond)
+		require.NoError(t, c.Set(ctx, "b", []byte("123456"), 0))
+
+		_, err := c.Get(ctx, "b")
+		require.NoError(t, err)
+		require.Equal(t, int64(6), c.size.Load())
+	})
+}
+
+func TestIncrementalCacheKey(t *testing.T) {
+	q := &models.Query{Expr: "up", Step: time.Minute}
+	i := &incrementalQuerying{dataSourceID: 1, uid: "prom"}
+	key := i.cacheKey(q, false, incrementalCaller{orgID: 1, identity: "user"})
+
+	require.Equal(t, key, i.cacheKey(q, false, incrementalCaller{orgID: 1, identity: "user"}))
+	require.NotEqual(t, key, i.cacheKey(q, false, incrementalCaller{orgID: 2, identity: "user"}))
+	require.NotEqual(t, key, i.cacheKey(q, false, incrementalCaller{orgID: 1, identity: "another user"}))
+
+	other := &incrementalQuerying{dataSourceID: 2, uid: "prom"}
+	require.NotEqual(t, key, other.cacheKey(q, false, incrementalCaller{orgID: 1, identity: "user"}))
+}
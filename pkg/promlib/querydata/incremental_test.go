@@ -0,0 +1,139 @@
+package querydata_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	p "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/promlib/models"
+)
+
+func TestPrometheus_incrementalRangeQuery(t *testing.T) {
+	qm := models.QueryModel{
+		PrometheusQueryProperties: models.PrometheusQueryProperties{
+			Expr:  "up",
+			Range: true,
+		},
+		Interval: "1m",
+	}
+	b, err := json.Marshal(&qm)
+	require.NoError(t, err)
+
+	rangeQuery := func(from, to int64) backend.DataQuery {
+		return backend.DataQuery{
+			RefID: "A",
+			TimeRange: backend.TimeRange{
+				From: time.Unix(from, 0).UTC(),
+				To:   time.Unix(to, 0).UTC(),
+			},
+			JSON: b,
+		}
+	}
+
+	matrix := func(from, to int64, offset float64, metric p.Metric) *p.SampleStream {
+		stream := &p.SampleStream{Metric: metric}
+		for ts := from; ts <= to; ts += 60 {
+			stream.Values = append(stream.Values, p.SamplePair{
+				Timestamp: p.TimeFromUnix(ts),
+				Value:     p.SampleValue(offset + float64(ts/60)),
+			})
+		}
+		return stream
+	}
+
+	requestedStart := func(t *testing.T, tctx *testContext) string {
+		t.Helper()
+		require.NoError(t, tctx.httpProvider.req.ParseForm())
+		return tctx.httpProvider.req.Form.Get("start")
+	}
+
+	t.Run("fetches only the tail of the range and merges it with the cached result", func(t *testing.T) {
+		tctx, err := setupWithJSONData(`{"timeInterval": "1m", "backendIncrementalQuerying": true, "incrementalQueryOverlapWindow": "5m"}`)
+		require.NoError(t, err)
+
+		res, err := execute(tctx, rangeQuery(0, 3600), queryResult{
+			Type:   p.ValMatrix,
+			Result: p.Matrix{matrix(0, 3600, 0, p.Metric{"job": "a"})},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "0", requestedStart(t, tctx))
+		require.Len(t, res, 1)
+		require.Equal(t, 61, res[0].Rows())
+
+		res, err = execute(tctx, rangeQuery(600, 4200), queryResult{
+			Type: p.ValMatrix,
+			Result: p.Matrix{
+				matrix(3300, 4200, 100, p.Metric{"job": "a"}),
+				matrix(4200, 4200, 0, p.Metric{"job": "b"}),
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "3300", requestedStart(t, tctx))
+
+		require.Len(t, res, 2)
+		require.Equal(t, "job=a", res[0].Fields[1].Labels.String())
+		require.Equal(t, 61, res[0].Rows())
+		require.Equal(t, time.Unix(600, 0).UTC(), res[0].Fields[0].At(0))
+		require.Equal(t, float64(10), res[0].Fields[1].At(0))
+		require.Equal(t, time.Unix(3300, 0).UTC(), res[0].Fields[0].At(45))
+		require.Equal(t, float64(155), res[0].Fields[1].At(45))
+		require.Equal(t, "Expr: up\nStep: 1m0s", res[0].Meta.ExecutedQueryString)
+
+		require.Equal(t, "job=b", res[1].Fields[1].Labels.String())
+		require.Equal(t, 1, res[1].Rows())
+	})
+
+	t.Run("fetches the full range when the cached result doesn't cover its start", func(t *testing.T) {
+		tctx, err := setupWithJSONData(`{"timeInterval": "1m", "backendIncrementalQuerying": true}`)
+		require.NoError(t, err)
+
+		_, err = execute(tctx, rangeQuery(3600, 7200), queryResult{
+			Type:   p.ValMatrix,
+			Result: p.Matrix{matrix(3600, 7200, 0, p.Metric{"job": "a"})},
+		})
+		require.NoError(t, err)
+
+		_, err = execute(tctx, rangeQuery(0, 7200), queryResult{
+			Type:   p.ValMatrix,
+			Result: p.Matrix{matrix(0, 7200, 0, p.Metric{"job": "a"})},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "0", requestedStart(t, tctx))
+	})
+
+	t.Run("doesn't share cached results between callers", func(t *testing.T) {
+		tctx, err := setupWithJSONData(`{"timeInterval": "1m", "backendIncrementalQuerying": true}`)
+		require.NoError(t, err)
+
+		_, err = executeWithHeaders(tctx, rangeQuery(0, 3600), queryResult{
+			Type:   p.ValMatrix,
+			Result: p.Matrix{matrix(0, 3600, 0, p.Metric{"job": "a"})},
+		}, map[string]string{"http_Authorization": "Bearer alice"})
+		require.NoError(t, err)
+
+		_, err = executeWithHeaders(tctx, rangeQuery(600, 4200), queryResult{
+			Type:   p.ValMatrix,
+			Result: p.Matrix{matrix(600, 4200, 0, p.Metric{"job": "a"})},
+		}, map[string]string{"http_Authorization": "Bearer bob"})
+		require.NoError(t, err)
+		require.Equal(t, "600", requestedStart(t, tctx))
+	})
+
+	t.Run("fetches the full range when incremental querying is disabled", func(t *testing.T) {
+		tctx, err := setupWithJSONData(`{"timeInterval": "1m"}`)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			_, err = execute(tctx, rangeQuery(0, 3600), queryResult{
+				Type:   p.ValMatrix,
+				Result: p.Matrix{matrix(0, 3600, 0, p.Metric{"job": "a"})},
+			})
+			require.NoError(t, err)
+			require.Equal(t, "0", requestedStart(t, tctx))
+		}
+	})
+}
@@ -44,12 +44,16 @@ type QueryData struct {
 	URL                string
 	TimeInterval       string
 	exemplarSampler    func() exemplar.Sampler
+	incremental        *incrementalQuerying
 }
 
+// New creates the query handler of a data source. The cache stores range query results
+// when backend incremental querying is enabled in the data source settings.
 func New(
 	httpClient *http.Client,
 	settings backend.DataSourceInstanceSettings,
 	plog log.Logger,
+	cache Cache,
 ) (*QueryData, error) {
 	jsonData, err := utils.GetJsonData(settings)
 	if err != nil {
@@ -66,6 +70,11 @@ func New(
 		httpMethod = http.MethodPost
 	}
 
+	incremental, err := newIncrementalQuerying(settings, jsonData, cache)
+	if err != nil {
+		return nil, err
+	}
+
 	promClient := client.NewClient(httpClient, httpMethod, settings.URL)
 
 	// standard deviation sampler is the default for backwards compatibility
@@ -80,6 +89,7 @@ func New(
 		ID:                 settings.ID,
 		URL:                settings.URL,
 		exemplarSampler:    exemplarSampler,
+		incremental:        incremental,
 	}, nil
 }
 
@@ -93,13 +103,18 @@ func (s *QueryData) Execute(ctx context.Context, req *backend.QueryDataRequest)
 	hasPromQLScopeFeatureFlag := cfg.FeatureToggles().IsEnabled("promQLScope")
 	hasPrometheusDataplaneFeatureFlag := cfg.FeatureToggles().IsEnabled("prometheusDataplane")
 
+	var caller incrementalCaller
+	if s.incremental != nil {
+		caller = callerIdentity(req)
+	}
+
 	for _, q := range req.Queries {
 		query, err := models.Parse(q, s.TimeInterval, s.intervalCalculator, fromAlert, hasPromQLScopeFeatureFlag)
 		if err != nil {
 			return &result, err
 		}
 
-		r := s.fetch(ctx, s.client, query, hasPrometheusDataplaneFeatureFlag)
+		r := s.fetch(ctx, s.client, query, hasPrometheusDataplaneFeatureFlag, caller)
 		if r == nil {
 			s.log.FromContext(ctx).Debug("Received nil response from runQuery", "query", query.Expr)
 			continue
@@ -110,7 +125,7 @@ func (s *QueryData) Execute(ctx context.Context, req *backend.QueryDataRequest)
 	return &result, nil
 }
 
-func (s *QueryData) fetch(ctx context.Context, client *client.Client, q *models.Query, enablePrometheusDataplane bool) *backend.DataResponse {
+func (s *QueryData) fetch(ctx context.Context, client *client.Client, q *models.Query, enablePrometheusDataplane bool, caller incrementalCaller) *backend.DataResponse {
 	traceCtx, end := s.trace(ctx, q)
 	defer end()
 
@@ -130,7 +145,12 @@ func (s *QueryData) fetch(ctx context.Context, client *client.Client, q *models.
 	}
 
 	if q.RangeQuery {
-		res := s.rangeQuery(traceCtx, client, q, enablePrometheusDataplane)
+		var res backend.DataResponse
+		if s.incremental != nil {
+			res = s.incrementalRangeQuery(traceCtx, client, q, enablePrometheusDataplane, caller)
+		} else {
+			res = s.rangeQuery(traceCtx, client, q, enablePrometheusDataplane)
+		}
 		if res.Error != nil {
 			if dr.Error == nil {
 				dr.Error = res.Error
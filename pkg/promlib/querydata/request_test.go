@@ -422,6 +422,10 @@ type testContext struct {
 }
 
 func setup() (*testContext, error) {
+	return setupWithJSONData(`{"timeInterval": "15s"}`)
+}
+
+func setupWithJSONData(jsonData string) (*testContext, error) {
 	httpProvider := &fakeHttpClientProvider{
 		opts: httpclient.Options{
 			Timeouts: &httpclient.DefaultTimeoutOptions,
@@ -433,7 +437,8 @@ func setup() (*testContext, error) {
 	}
 	settings := backend.DataSourceInstanceSettings{
 		URL:      "http://localhost:9090",
-		JSONData: json.RawMessage(`{"timeInterval": "15s"}`),
+		UID:      "prometheus",
+		JSONData: json.RawMessage(jsonData),
 	}
 
 	opts, err := client.CreateTransportOptions(context.Background(), settings, log.New())
@@ -446,7 +451,7 @@ func setup() (*testContext, error) {
 		return nil, err
 	}
 
-	queryData, _ := querydata.New(httpClient, settings, log.New())
+	queryData, _ := querydata.New(httpClient, settings, log.New(), querydata.NewMemoryCache())
 
 	return &testContext{
 		httpProvider: httpProvider,
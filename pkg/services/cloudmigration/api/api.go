@@ -3,10 +3,12 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
@@ -23,18 +25,21 @@ type CloudMigrationAPI struct {
 	routeRegister         routing.RouteRegister
 	log                   log.Logger
 	tracer                tracing.Tracer
+	maxSnapshotSize       int64
 }
 
 func RegisterApi(
 	rr routing.RouteRegister,
 	cms cloudmigration.Service,
 	tracer tracing.Tracer,
+	maxSnapshotSize int64,
 ) *CloudMigrationAPI {
 	api := &CloudMigrationAPI{
 		log:                   log.New("cloudmigrations.api"),
 		routeRegister:         rr,
 		cloudMigrationService: cms,
 		tracer:                tracer,
+		maxSnapshotSize:       maxSnapshotSize,
 	}
 	api.registerEndpoints()
 	return api
@@ -52,6 +57,9 @@ func (cma *CloudMigrationAPI) registerEndpoints() {
 		cloudMigrationRoute.Get("/migration/:id/run", routing.Wrap(cma.GetMigrationRunList))
 		cloudMigrationRoute.Get("/migration/:id/run/:runID", routing.Wrap(cma.GetMigrationRun))
 		cloudMigrationRoute.Post("/token", routing.Wrap(cma.CreateToken))
+		// snapshot
+		cloudMigrationRoute.Get("/snapshot/export", routing.Wrap(cma.ExportSnapshot))
+		cloudMigrationRoute.Post("/snapshot/import", routing.Wrap(cma.ImportSnapshot))
 	}, middleware.ReqOrgAdmin)
 }
 
@@ -374,6 +382,96 @@ type DeleteMigrationRequest struct {
 	ID int64 `json:"id"`
 }
 
+// swagger:route GET /cloudmigration/snapshot/export migrations exportCloudMigrationSnapshot
+//
+// Export the resources of the current organization to a snapshot archive.
+//
+// The archive can be imported on another instance. Secrets are not part of the archive: data sources are exported
+// without their secure json data and the secure settings of contact points are redacted.
+//
+// Produces:
+// - application/gzip
+//
+// Responses:
+// 200: cloudMigrationSnapshotResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (cma *CloudMigrationAPI) ExportSnapshot(c *contextmodel.ReqContext) response.Response {
+	ctx, span := cma.tracer.Start(c.Req.Context(), "MigrationAPI.ExportSnapshot")
+	defer span.End()
+
+	archive, err := cma.cloudMigrationService.ExportSnapshot(ctx)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "snapshot export error", err)
+	}
+
+	fileName := fmt.Sprintf("grafana-snapshot-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	return response.Respond(http.StatusOK, archive).
+		SetHeader("Content-Type", "application/gzip").
+		SetHeader("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+}
+
+// swagger:route POST /cloudmigration/snapshot/import migrations importCloudMigrationSnapshot
+//
+// Import a snapshot archive into the current organization.
+//
+// Consumes:
+// - application/gzip
+//
+// Responses:
+// 200: cloudMigrationRunResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (cma *CloudMigrationAPI) ImportSnapshot(c *contextmodel.ReqContext) response.Response {
+	ctx, span := cma.tracer.Start(c.Req.Context(), "MigrationAPI.ImportSnapshot")
+	defer span.End()
+
+	strategy, err := cloudmigration.ParseConflictStrategy(c.Query("conflict"))
+	if err != nil {
+		return response.Err(err)
+	}
+
+	archive, err := io.ReadAll(http.MaxBytesReader(c.Resp, c.Req.Body, cma.maxSnapshotSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return response.Error(http.StatusBadRequest, fmt.Sprintf("snapshot archive exceeds the maximum size of %d bytes", maxBytesErr.Limit), err)
+		}
+		return response.Error(http.StatusBadRequest, "reading snapshot archive", err)
+	}
+
+	result, err := cma.cloudMigrationService.ImportSnapshot(ctx, archive, strategy)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "snapshot import error", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:parameters importCloudMigrationSnapshot
+type ImportSnapshotParams struct {
+	// How resources that already exist are handled, defaults to skip
+	//
+	// in: query
+	// enum: skip,overwrite,fail
+	Conflict string `json:"conflict"`
+
+	// Snapshot archive created by an export
+	//
+	// in: body
+	// swagger:type file
+	Body []byte `json:"body"`
+}
+
+// swagger:response cloudMigrationSnapshotResponse
+type CloudMigrationSnapshotResponse struct {
+	// in: body
+	// swagger:type file
+	Body []byte
+}
+
 // swagger:response cloudMigrationRunResponse
 type CloudMigrationRunResponse struct {
 	// in: body
@@ -17,6 +17,9 @@ type Service interface {
 	GetMigrationStatusList(context.Context, string) ([]*CloudMigrationRun, error)
 	DeleteMigration(context.Context, int64) (*CloudMigration, error)
 	SaveMigrationRun(context.Context, *CloudMigrationRun) (int64, error)
+	// snapshot
+	ExportSnapshot(context.Context) ([]byte, error)
+	ImportSnapshot(context.Context, []byte, ConflictStrategy) (*MigrateDataResponseDTO, error)
 
 	ParseCloudMigrationConfig() (string, error)
 }
@@ -0,0 +1,188 @@
+package cloudmigrationimpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/cloudmigration"
+	ngapi "github.com/grafana/grafana/pkg/services/ngalert/api"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// notificationPolicyRefID is the reference of the single notification policy tree of an org
+const notificationPolicyRefID = "root"
+
+// alertRuleData is the payload of an ALERT_RULE item. The group interval is kept along the rule since the
+// provisioned format doesn't carry it.
+type alertRuleData struct {
+	Rule            definitions.ProvisionedAlertRule `json:"rule"`
+	IntervalSeconds int64                            `json:"intervalSeconds"`
+}
+
+// getAlertingResources returns the contact points, the notification policy tree and the alert rules of the signed in
+// user's org. Secure settings of contact points are redacted. Nothing is returned when unified alerting is disabled.
+func (s *Service) getAlertingResources(ctx context.Context, user identity.Requester) ([]cloudmigration.MigrateDataRequestItemDTO, error) {
+	var items []cloudmigration.MigrateDataRequestItemDTO
+	if !s.alertingEnabled() {
+		return items, nil
+	}
+
+	contactPoints, err := s.contactPointService.GetContactPoints(ctx, provisioning.ContactPointQuery{
+		OrgID: user.GetOrgID(),
+	}, user)
+	if err != nil {
+		return nil, fmt.Errorf("getting contact points: %w", err)
+	}
+	for _, cp := range contactPoints {
+		items = append(items, cloudmigration.MigrateDataRequestItemDTO{
+			Type:  cloudmigration.ContactPointDataType,
+			RefID: cp.UID,
+			Name:  cp.Name,
+			Data:  cp,
+		})
+	}
+
+	policies, err := s.notificationPolicyService.GetPolicyTree(ctx, user.GetOrgID())
+	if err != nil && !errors.Is(err, provisioning.ErrNoAlertmanagerConfiguration) {
+		return nil, fmt.Errorf("getting notification policies: %w", err)
+	}
+	if err == nil {
+		items = append(items, cloudmigration.MigrateDataRequestItemDTO{
+			Type:  cloudmigration.NotificationPolicyDataType,
+			RefID: notificationPolicyRefID,
+			Name:  "Notification policies",
+			Data:  policies,
+		})
+	}
+
+	rules, provenances, err := s.alertRuleService.GetAlertRules(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("getting alert rules: %w", err)
+	}
+	for _, rule := range rules {
+		items = append(items, cloudmigration.MigrateDataRequestItemDTO{
+			Type:  cloudmigration.AlertRuleDataType,
+			RefID: rule.UID,
+			Name:  rule.Title,
+			Data: alertRuleData{
+				Rule:            ngapi.ProvisionedAlertRuleFromAlertRule(*rule, provenances[rule.UID]),
+				IntervalSeconds: rule.IntervalSeconds,
+			},
+		})
+	}
+
+	return items, nil
+}
+
+// alertingEnabled tells whether the alerting provisioning services are available, they are not when unified alerting
+// is disabled.
+func (s *Service) alertingEnabled() bool {
+	return s.alertRuleService != nil && s.contactPointService != nil && s.notificationPolicyService != nil
+}
+
+func (i *snapshotImporter) importContactPoint(ctx context.Context, item snapshotItem) (cloudmigration.ItemStatus, error) {
+	var contactPoint definitions.EmbeddedContactPoint
+	if err := json.Unmarshal(item.Data, &contactPoint); err != nil {
+		return "", err
+	}
+
+	existing, err := i.s.contactPointService.GetContactPoints(ctx, provisioning.ContactPointQuery{OrgID: i.orgID}, i.user)
+	if err != nil {
+		return "", err
+	}
+	exists := false
+	for _, cp := range existing {
+		if cp.UID == contactPoint.UID {
+			exists = true
+			break
+		}
+	}
+
+	if exists {
+		if status, err := i.conflict(item); status != "" || err != nil {
+			return status, err
+		}
+		if err := i.s.contactPointService.UpdateContactPoint(ctx, i.orgID, contactPoint, ngmodels.ProvenanceNone); err != nil {
+			return "", err
+		}
+		return cloudmigration.ItemStatusOK, nil
+	}
+
+	// secure settings are redacted in snapshots, there is no stored value to replace them with on creation
+	if contactPoint.Settings != nil {
+		secretKeys, err := channels_config.GetSecretKeysForContactPointType(contactPoint.Type)
+		if err != nil {
+			return "", err
+		}
+		for _, key := range secretKeys {
+			if contactPoint.Settings.Get(key).MustString() == definitions.RedactedValue {
+				contactPoint.Settings.Del(key)
+			}
+		}
+	}
+	if _, err := i.s.contactPointService.CreateContactPoint(ctx, i.orgID, contactPoint, ngmodels.ProvenanceNone); err != nil {
+		return "", err
+	}
+	return cloudmigration.ItemStatusOK, nil
+}
+
+// importNotificationPolicy replaces the policy tree of the org. Every org has a policy tree, so the default one is
+// only replaced when the conflict strategy is overwrite.
+func (i *snapshotImporter) importNotificationPolicy(ctx context.Context, item snapshotItem) (cloudmigration.ItemStatus, error) {
+	var tree definitions.Route
+	if err := json.Unmarshal(item.Data, &tree); err != nil {
+		return "", err
+	}
+
+	if status, err := i.conflict(item); status != "" || err != nil {
+		return status, err
+	}
+	if err := i.s.notificationPolicyService.UpdatePolicyTree(ctx, i.orgID, tree, ngmodels.ProvenanceNone); err != nil {
+		return "", err
+	}
+	return cloudmigration.ItemStatusOK, nil
+}
+
+func (i *snapshotImporter) importAlertRule(ctx context.Context, item snapshotItem) (cloudmigration.ItemStatus, error) {
+	var data alertRuleData
+	if err := json.Unmarshal(item.Data, &data); err != nil {
+		return "", err
+	}
+	rule, err := ngapi.AlertRuleFromProvisionedAlertRule(data.Rule)
+	if err != nil {
+		return "", err
+	}
+	rule.ID = 0
+	rule.OrgID = i.orgID
+	rule.IntervalSeconds = data.IntervalSeconds
+
+	_, _, err = i.s.alertRuleService.GetAlertRule(ctx, i.user, rule.UID)
+	switch {
+	case err == nil:
+		if status, err := i.conflict(item); status != "" || err != nil {
+			return status, err
+		}
+		if _, err := i.s.alertRuleService.UpdateAlertRule(ctx, i.user, rule, ngmodels.ProvenanceNone); err != nil {
+			return "", err
+		}
+	case errors.Is(err, ngmodels.ErrAlertRuleNotFound):
+		if _, err := i.s.alertRuleService.CreateAlertRule(ctx, i.user, rule, ngmodels.ProvenanceNone); err != nil {
+			return "", err
+		}
+	default:
+		return "", err
+	}
+
+	if data.IntervalSeconds > 0 {
+		if err := i.s.alertRuleService.UpdateRuleGroup(ctx, i.user, rule.NamespaceUID, rule.RuleGroup, data.IntervalSeconds); err != nil {
+			return "", fmt.Errorf("setting rule group interval: %w", err)
+		}
+	}
+	return cloudmigration.ItemStatusOK, nil
+}
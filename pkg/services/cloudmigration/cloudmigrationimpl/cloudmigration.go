@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/cloudmigration"
 	"github.com/grafana/grafana/pkg/services/cloudmigration/api"
 	"github.com/grafana/grafana/pkg/services/contexthandler"
@@ -21,7 +22,12 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/gcom"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/ngalert"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -41,6 +47,15 @@ type Service struct {
 	folderService    folder.Service
 	secretsService   secrets.Service
 
+	libraryElementService     libraryelements.Service
+	teamService               team.Service
+	userService               user.Service
+	dashboardPermissions      accesscontrol.DashboardPermissionsService
+	folderPermissions         accesscontrol.FolderPermissionsService
+	alertRuleService          *provisioning.AlertRuleService
+	contactPointService       *provisioning.ContactPointService
+	notificationPolicyService *provisioning.NotificationPolicyService
+
 	api     *api.CloudMigrationAPI
 	tracer  tracing.Tracer
 	metrics *Metrics
@@ -70,6 +85,12 @@ func ProvideService(
 	tracer tracing.Tracer,
 	dashboardService dashboards.DashboardService,
 	folderService folder.Service,
+	libraryElementService libraryelements.Service,
+	teamService team.Service,
+	userService user.Service,
+	dashboardPermissions accesscontrol.DashboardPermissionsService,
+	folderPermissions accesscontrol.FolderPermissionsService,
+	ng *ngalert.AlertNG,
 ) cloudmigration.Service {
 	if !features.IsEnabledGlobally(featuremgmt.FlagOnPremToCloudMigrations) {
 		return &NoopServiceImpl{}
@@ -87,8 +108,18 @@ func ProvideService(
 		secretsService:   secretsService,
 		dashboardService: dashboardService,
 		folderService:    folderService,
+
+		libraryElementService: libraryElementService,
+		teamService:           teamService,
+		userService:           userService,
+		dashboardPermissions:  dashboardPermissions,
+		folderPermissions:     folderPermissions,
+
+		alertRuleService:          ng.GetAlertRuleService(),
+		contactPointService:       ng.GetContactPointService(),
+		notificationPolicyService: ng.GetNotificationPolicyService(),
 	}
-	s.api = api.RegisterApi(routeRegister, s, tracer)
+	s.api = api.RegisterApi(routeRegister, s, tracer, cfg.CloudMigration.SnapshotMaxSize)
 
 	if err := s.registerMetrics(prom, s.metrics); err != nil {
 		s.log.Warn("error registering prom metrics", "error", err.Error())
@@ -383,6 +414,94 @@ func (s *Service) GetMigrationDataJSON(ctx context.Context, id int64) ([]byte, e
 	return result, nil
 }
 
+// getSnapshotData collects the resources of the signed in user's org in the order they have to be created in
+func (s *Service) getSnapshotData(ctx context.Context) ([]cloudmigration.MigrateDataRequestItemDTO, error) {
+	var migrationDataSlice []cloudmigration.MigrateDataRequestItemDTO
+	orgID := contexthandler.FromContext(ctx).SignedInUser.GetOrgID()
+
+	// Folders
+	folders, err := s.getFolders(ctx, 0)
+	if err != nil {
+		s.log.Error("Failed to get folders", "err", err)
+		return nil, err
+	}
+
+	for _, f := range sortFoldersByParent(folders) {
+		migrationDataSlice = append(migrationDataSlice, cloudmigration.MigrateDataRequestItemDTO{
+			Type:  cloudmigration.FolderDataType,
+			RefID: f.UID,
+			Name:  f.Title,
+			Data:  f,
+		})
+	}
+
+	// Data sources
+	dataSources, err := s.getSnapshotDataSources(ctx, orgID)
+	if err != nil {
+		s.log.Error("Failed to get datasources", "err", err)
+		return nil, err
+	}
+	migrationDataSlice = append(migrationDataSlice, dataSources...)
+
+	// Teams
+	teams, err := s.getTeams(ctx)
+	if err != nil {
+		s.log.Error("Failed to get teams", "err", err)
+		return nil, err
+	}
+	migrationDataSlice = append(migrationDataSlice, teams...)
+
+	// Library elements
+	libraryElements, err := s.getLibraryElements(ctx)
+	if err != nil {
+		s.log.Error("Failed to get library elements", "err", err)
+		return nil, err
+	}
+	migrationDataSlice = append(migrationDataSlice, libraryElements...)
+
+	// Dashboards
+	allDashboards, err := s.getDashboards(ctx, 0)
+	if err != nil {
+		s.log.Error("Failed to get dashboards", "err", err)
+		return nil, err
+	}
+
+	orgDashboards := make([]dashboards.Dashboard, 0, len(allDashboards))
+	for _, dashboard := range allDashboards {
+		if dashboard.OrgID == orgID && !dashboard.IsFolder {
+			orgDashboards = append(orgDashboards, dashboard)
+		}
+	}
+
+	for _, dashboard := range orgDashboards {
+		dashboard.Data.Del("id")
+		migrationDataSlice = append(migrationDataSlice, cloudmigration.MigrateDataRequestItemDTO{
+			Type:  cloudmigration.DashboardDataType,
+			RefID: dashboard.UID,
+			Name:  dashboard.Title,
+			Data:  cloudmigration.DashboardData{Dashboard: dashboard.Data, FolderUID: dashboard.FolderUID},
+		})
+	}
+
+	// Permissions
+	permissions, err := s.getPermissions(ctx, folders, orgDashboards)
+	if err != nil {
+		s.log.Error("Failed to get permissions", "err", err)
+		return nil, err
+	}
+	migrationDataSlice = append(migrationDataSlice, permissions...)
+
+	// Alerting
+	alerting, err := s.getAlertingResources(ctx, contexthandler.FromContext(ctx).SignedInUser)
+	if err != nil {
+		s.log.Error("Failed to get alerting resources", "err", err)
+		return nil, err
+	}
+	migrationDataSlice = append(migrationDataSlice, alerting...)
+
+	return migrationDataSlice, nil
+}
+
 func (s *Service) getDataSources(ctx context.Context, id int64) ([]datasources.AddDataSourceCommand, error) {
 	dataSources, err := s.dsService.GetAllDataSources(ctx, &datasources.GetAllDataSourcesQuery{})
 	if err != nil {
@@ -58,6 +58,14 @@ func (s *NoopServiceImpl) GetMigrationDataJSON(ctx context.Context, id int64) ([
 	return nil, cloudmigration.ErrFeatureDisabledError
 }
 
+func (s *NoopServiceImpl) ExportSnapshot(ctx context.Context) ([]byte, error) {
+	return nil, cloudmigration.ErrFeatureDisabledError
+}
+
+func (s *NoopServiceImpl) ImportSnapshot(ctx context.Context, archive []byte, strategy cloudmigration.ConflictStrategy) (*cloudmigration.MigrateDataResponseDTO, error) {
+	return nil, cloudmigration.ErrFeatureDisabledError
+}
+
 func (s *NoopServiceImpl) ParseCloudMigrationConfig() (string, error) {
 	return "", cloudmigration.ErrFeatureDisabledError
 }
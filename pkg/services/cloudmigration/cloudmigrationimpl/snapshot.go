@@ -0,0 +1,684 @@
+package cloudmigrationimpl
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/cloudmigration"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const (
+	snapshotManifestFile = "manifest.json"
+	snapshotItemsFile    = "items.json"
+
+	permissionKindDashboards = "dashboards"
+	permissionKindFolders    = "folders"
+)
+
+// snapshotItem is a MigrateDataRequestItemDTO read back from an archive, its payload is decoded based on the type
+type snapshotItem struct {
+	Type  cloudmigration.MigrateDataType `json:"type"`
+	RefID string                         `json:"refId"`
+	Name  string                         `json:"name"`
+	Data  json.RawMessage                `json:"data"`
+}
+
+// ExportSnapshot packages the resources of the signed in user's org into a gzipped tar archive, which can be imported
+// on another instance with ImportSnapshot.
+func (s *Service) ExportSnapshot(ctx context.Context) ([]byte, error) {
+	ctx, span := s.tracer.Start(ctx, "CloudMigrationService.ExportSnapshot")
+	defer span.End()
+
+	items, err := s.getSnapshotData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := cloudmigration.SnapshotManifest{
+		Version:        cloudmigration.SnapshotVersion,
+		GrafanaVersion: s.cfg.BuildVersion,
+		Created:        time.Now(),
+		Items:          make(map[cloudmigration.MigrateDataType]int),
+	}
+	for _, item := range items {
+		manifest.Items[item.Type]++
+	}
+
+	return writeSnapshot(manifest, items)
+}
+
+func writeSnapshot(manifest cloudmigration.SnapshotManifest, items []cloudmigration.MigrateDataRequestItemDTO) ([]byte, error) {
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding manifest: %w", err)
+	}
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("encoding items: %w", err)
+	}
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{
+		{name: snapshotManifestFile, data: manifestJSON},
+		{name: snapshotItemsFile, data: itemsJSON},
+	} {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    f.name,
+			Mode:    0600,
+			Size:    int64(len(f.data)),
+			ModTime: manifest.Created,
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readSnapshot reads the manifest and the items of an archive. The decompressed content of the archive may not exceed
+// maxSize bytes.
+func readSnapshot(archive []byte, maxSize int64) (cloudmigration.SnapshotManifest, []snapshotItem, error) {
+	var manifest cloudmigration.SnapshotManifest
+	var items []snapshotItem
+	var hasManifest, hasItems bool
+
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return manifest, nil, cloudmigration.ErrInvalidSnapshot.Errorf("reading archive: %w", err)
+	}
+	defer func() { _ = gr.Close() }()
+
+	// one byte past the limit is allowed so that reaching it can be told apart from the end of the archive
+	lr := &io.LimitedReader{R: gr, N: maxSize + 1}
+	tooLarge := func() error {
+		return cloudmigration.ErrInvalidSnapshot.Errorf("archive exceeds the maximum size of %d bytes", maxSize)
+	}
+
+	tr := tar.NewReader(lr)
+	for {
+		header, err := tr.Next()
+		if lr.N <= 0 {
+			return manifest, nil, tooLarge()
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return manifest, nil, cloudmigration.ErrInvalidSnapshot.Errorf("reading archive: %w", err)
+		}
+
+		var target any
+		switch header.Name {
+		case snapshotManifestFile:
+			target, hasManifest = &manifest, true
+		case snapshotItemsFile:
+			target, hasItems = &items, true
+		default:
+			continue
+		}
+		if err := json.NewDecoder(tr).Decode(target); err != nil {
+			if lr.N <= 0 {
+				return manifest, nil, tooLarge()
+			}
+			return manifest, nil, cloudmigration.ErrInvalidSnapshot.Errorf("decoding %s: %w", header.Name, err)
+		}
+	}
+
+	if !hasManifest || !hasItems {
+		return manifest, nil, cloudmigration.ErrInvalidSnapshot.Errorf("archive must contain %s and %s", snapshotManifestFile, snapshotItemsFile)
+	}
+	if manifest.Version > cloudmigration.SnapshotVersion {
+		return manifest, nil, cloudmigration.ErrInvalidSnapshot.Errorf("unsupported snapshot version %d", manifest.Version)
+	}
+	return manifest, items, nil
+}
+
+// ImportSnapshot creates the resources of an archive in the signed in user's org. Items are imported in the order of
+// the archive, resources that already exist are handled according to the conflict strategy. Failing items don't stop
+// the import, their error is reported in the result.
+func (s *Service) ImportSnapshot(ctx context.Context, archive []byte, strategy cloudmigration.ConflictStrategy) (*cloudmigration.MigrateDataResponseDTO, error) {
+	ctx, span := s.tracer.Start(ctx, "CloudMigrationService.ImportSnapshot")
+	defer span.End()
+	logger := s.log.FromContext(ctx)
+
+	manifest, items, err := readSnapshot(archive, s.cfg.CloudMigration.SnapshotMaxSize)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("importing snapshot", "version", manifest.Version, "grafanaVersion", manifest.GrafanaVersion, "created", manifest.Created, "items", len(items))
+
+	signedInUser := contexthandler.FromContext(ctx).SignedInUser
+	importer := &snapshotImporter{
+		s:        s,
+		user:     signedInUser,
+		orgID:    signedInUser.GetOrgID(),
+		strategy: strategy,
+	}
+
+	result := &cloudmigration.MigrateDataResponseDTO{Items: make([]cloudmigration.MigrateDataResponseItemDTO, 0, len(items))}
+	for _, item := range items {
+		status, err := importer.importItem(ctx, item)
+		resultItem := cloudmigration.MigrateDataResponseItemDTO{
+			Type:   item.Type,
+			RefID:  item.RefID,
+			Status: status,
+		}
+		if err != nil {
+			logger.Warn("failed to import snapshot item", "type", item.Type, "refId", item.RefID, "error", err)
+			resultItem.Status = cloudmigration.ItemStatusError
+			resultItem.Error = err.Error()
+		}
+		result.Items = append(result.Items, resultItem)
+	}
+	return result, nil
+}
+
+type snapshotImporter struct {
+	s        *Service
+	user     identity.Requester
+	orgID    int64
+	strategy cloudmigration.ConflictStrategy
+}
+
+var errResourceExists = errors.New("resource already exists")
+
+// conflict decides what happens to an item whose resource already exists. It returns the status of the item when it
+// must not be imported, and an error when the conflict must be reported.
+func (i *snapshotImporter) conflict(item snapshotItem) (cloudmigration.ItemStatus, error) {
+	switch i.strategy {
+	case cloudmigration.ConflictStrategyOverwrite:
+		return "", nil
+	case cloudmigration.ConflictStrategyFail:
+		return "", fmt.Errorf("%s %s: %w", item.Type, item.RefID, errResourceExists)
+	default:
+		return cloudmigration.ItemStatusSkipped, nil
+	}
+}
+
+var errAlertingDisabled = errors.New("unified alerting is disabled")
+
+func (i *snapshotImporter) importItem(ctx context.Context, item snapshotItem) (cloudmigration.ItemStatus, error) {
+	switch item.Type {
+	case cloudmigration.ContactPointDataType, cloudmigration.NotificationPolicyDataType, cloudmigration.AlertRuleDataType:
+		if !i.s.alertingEnabled() {
+			return "", errAlertingDisabled
+		}
+	}
+
+	switch item.Type {
+	case cloudmigration.FolderDataType:
+		return i.importFolder(ctx, item)
+	case cloudmigration.DatasourceDataType:
+		return i.importDataSource(ctx, item)
+	case cloudmigration.TeamDataType:
+		return i.importTeam(ctx, item)
+	case cloudmigration.LibraryElementDataType:
+		return i.importLibraryElement(ctx, item)
+	case cloudmigration.DashboardDataType:
+		return i.importDashboard(ctx, item)
+	case cloudmigration.PermissionDataType:
+		return i.importPermission(ctx, item)
+	case cloudmigration.ContactPointDataType:
+		return i.importContactPoint(ctx, item)
+	case cloudmigration.NotificationPolicyDataType:
+		return i.importNotificationPolicy(ctx, item)
+	case cloudmigration.AlertRuleDataType:
+		return i.importAlertRule(ctx, item)
+	default:
+		return "", fmt.Errorf("unsupported item type %s", item.Type)
+	}
+}
+
+func (i *snapshotImporter) importFolder(ctx context.Context, item snapshotItem) (cloudmigration.ItemStatus, error) {
+	var f folder.Folder
+	if err := json.Unmarshal(item.Data, &f); err != nil {
+		return "", err
+	}
+
+	_, err := i.s.folderService.Get(ctx, &folder.GetFolderQuery{UID: &f.UID, OrgID: i.orgID, SignedInUser: i.user})
+	switch {
+	case err == nil:
+		if status, err := i.conflict(item); status != "" || err != nil {
+			return status, err
+		}
+		_, err = i.s.folderService.Update(ctx, &folder.UpdateFolderCommand{
+			UID:            f.UID,
+			OrgID:          i.orgID,
+			NewTitle:       &f.Title,
+			NewDescription: &f.Description,
+			Overwrite:      true,
+			SignedInUser:   i.user,
+		})
+	case errors.Is(err, folder.ErrFolderNotFound) || errors.Is(err, dashboards.ErrFolderNotFound):
+		_, err = i.s.folderService.Create(ctx, &folder.CreateFolderCommand{
+			UID:          f.UID,
+			OrgID:        i.orgID,
+			Title:        f.Title,
+			Description:  f.Description,
+			ParentUID:    f.ParentUID,
+			SignedInUser: i.user,
+		})
+	}
+	if err != nil {
+		return "", err
+	}
+	return cloudmigration.ItemStatusOK, nil
+}
+
+func (i *snapshotImporter) importTeam(ctx context.Context, item snapshotItem) (cloudmigration.ItemStatus, error) {
+	var data cloudmigration.TeamData
+	if err := json.Unmarshal(item.Data, &data); err != nil {
+		return "", err
+	}
+
+	existing, err := i.s.findTeamByName(ctx, i.user, data.Name)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		if status, err := i.conflict(item); status != "" || err != nil {
+			return status, err
+		}
+		if err := i.s.teamService.UpdateTeam(ctx, &team.UpdateTeamCommand{
+			ID:    existing.ID,
+			Name:  data.Name,
+			Email: data.Email,
+			OrgID: i.orgID,
+		}); err != nil {
+			return "", err
+		}
+		return cloudmigration.ItemStatusOK, nil
+	}
+
+	if _, err := i.s.teamService.CreateTeam(data.Name, data.Email, i.orgID); err != nil {
+		return "", err
+	}
+	return cloudmigration.ItemStatusOK, nil
+}
+
+func (i *snapshotImporter) importLibraryElement(ctx context.Context, item snapshotItem) (cloudmigration.ItemStatus, error) {
+	var element model.LibraryElementDTO
+	if err := json.Unmarshal(item.Data, &element); err != nil {
+		return "", err
+	}
+
+	existing, err := i.s.libraryElementService.GetElement(ctx, i.user, model.GetLibraryElementCommand{UID: element.UID})
+	switch {
+	case err == nil:
+		if status, err := i.conflict(item); status != "" || err != nil {
+			return status, err
+		}
+		_, err = i.s.libraryElementService.PatchElement(ctx, i.user, model.PatchLibraryElementCommand{
+			FolderUID: &element.FolderUID,
+			Name:      element.Name,
+			Model:     element.Model,
+			Kind:      element.Kind,
+			Version:   existing.Version,
+			UID:       element.UID,
+		}, element.UID)
+	case errors.Is(err, model.ErrLibraryElementNotFound):
+		_, err = i.s.libraryElementService.CreateElement(ctx, i.user, model.CreateLibraryElementCommand{
+			FolderUID: &element.FolderUID,
+			Name:      element.Name,
+			Model:     element.Model,
+			Kind:      element.Kind,
+			UID:       element.UID,
+		})
+	}
+	if err != nil {
+		return "", err
+	}
+	return cloudmigration.ItemStatusOK, nil
+}
+
+func (i *snapshotImporter) importPermission(ctx context.Context, item snapshotItem) (cloudmigration.ItemStatus, error) {
+	var data cloudmigration.PermissionData
+	if err := json.Unmarshal(item.Data, &data); err != nil {
+		return "", err
+	}
+
+	var service accesscontrol.PermissionsService
+	switch data.Kind {
+	case permissionKindDashboards:
+		service = i.s.dashboardPermissions
+	case permissionKindFolders:
+		service = i.s.folderPermissions
+	default:
+		return "", fmt.Errorf("unsupported permission kind %s", data.Kind)
+	}
+
+	commands := make([]accesscontrol.SetResourcePermissionCommand, 0, len(data.Permissions))
+	for _, p := range data.Permissions {
+		cmd := accesscontrol.SetResourcePermissionCommand{BuiltinRole: p.BuiltInRole, Permission: p.Permission}
+		switch {
+		case p.UserLogin != "":
+			u, err := i.s.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: p.UserLogin})
+			if err != nil {
+				return "", fmt.Errorf("finding user %s: %w", p.UserLogin, err)
+			}
+			cmd.UserID = u.ID
+		case p.TeamName != "":
+			t, err := i.s.findTeamByName(ctx, i.user, p.TeamName)
+			if err != nil {
+				return "", err
+			}
+			if t == nil {
+				return "", fmt.Errorf("finding team %s: %w", p.TeamName, team.ErrTeamNotFound)
+			}
+			cmd.TeamID = t.ID
+		}
+		commands = append(commands, cmd)
+	}
+
+	// permissions are merged into the existing ones, so there is no conflict to handle
+	if _, err := service.SetPermissions(ctx, i.orgID, data.ResourceUID, commands...); err != nil {
+		return "", err
+	}
+	return cloudmigration.ItemStatusOK, nil
+}
+
+func (i *snapshotImporter) importDataSource(ctx context.Context, item snapshotItem) (cloudmigration.ItemStatus, error) {
+	var cmd datasources.AddDataSourceCommand
+	if err := json.Unmarshal(item.Data, &cmd); err != nil {
+		return "", err
+	}
+	cmd.OrgID = i.orgID
+	userID, err := identity.UserIdentifier(i.user.GetNamespacedID())
+	if err != nil {
+		return "", err
+	}
+	cmd.UserID = userID
+
+	existing, err := i.s.dsService.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: cmd.UID, OrgID: i.orgID})
+	switch {
+	case err == nil:
+		if status, err := i.conflict(item); status != "" || err != nil {
+			return status, err
+		}
+		_, err = i.s.dsService.UpdateDataSource(ctx, &datasources.UpdateDataSourceCommand{
+			ID:              existing.ID,
+			UID:             existing.UID,
+			OrgID:           i.orgID,
+			Name:            cmd.Name,
+			Type:            cmd.Type,
+			Access:          cmd.Access,
+			URL:             cmd.URL,
+			User:            cmd.User,
+			Database:        cmd.Database,
+			BasicAuth:       cmd.BasicAuth,
+			BasicAuthUser:   cmd.BasicAuthUser,
+			WithCredentials: cmd.WithCredentials,
+			IsDefault:       cmd.IsDefault,
+			JsonData:        cmd.JsonData,
+			SecureJsonData:  cmd.SecureJsonData,
+			Version:         existing.Version,
+			ReadOnly:        existing.ReadOnly,
+		})
+	case errors.Is(err, datasources.ErrDataSourceNotFound):
+		_, err = i.s.dsService.AddDataSource(ctx, &cmd)
+	}
+	if err != nil {
+		return "", err
+	}
+	return cloudmigration.ItemStatusOK, nil
+}
+
+func (i *snapshotImporter) importDashboard(ctx context.Context, item snapshotItem) (cloudmigration.ItemStatus, error) {
+	var data struct {
+		Dashboard *simplejson.Json `json:"dashboard"`
+		FolderUID string           `json:"folderUid"`
+	}
+	if err := json.Unmarshal(item.Data, &data); err != nil {
+		return "", err
+	}
+	if data.Dashboard == nil {
+		return "", fmt.Errorf("missing dashboard model")
+	}
+	data.Dashboard.Del("id")
+
+	dash := dashboards.NewDashboardFromJson(data.Dashboard)
+	dash.OrgID = i.orgID
+	dash.FolderUID = data.FolderUID
+
+	overwrite := false
+	_, err := i.s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: dash.UID, OrgID: i.orgID})
+	switch {
+	case err == nil:
+		if status, err := i.conflict(item); status != "" || err != nil {
+			return status, err
+		}
+		overwrite = true
+	case !errors.Is(err, dashboards.ErrDashboardNotFound):
+		return "", err
+	}
+
+	if _, err := i.s.dashboardService.SaveDashboard(ctx, &dashboards.SaveDashboardDTO{
+		OrgID:     i.orgID,
+		User:      i.user,
+		Message:   "Imported from snapshot",
+		Overwrite: overwrite,
+		Dashboard: dash,
+	}, false); err != nil {
+		return "", err
+	}
+	return cloudmigration.ItemStatusOK, nil
+}
+
+// sortFoldersByParent orders folders so that parents always come before their subfolders
+func sortFoldersByParent(folders []folder.Folder) []folder.Folder {
+	byUID := make(map[string]struct{}, len(folders))
+	for _, f := range folders {
+		byUID[f.UID] = struct{}{}
+	}
+
+	sorted := make([]folder.Folder, 0, len(folders))
+	added := make(map[string]struct{}, len(folders))
+	for len(sorted) < len(folders) {
+		progress := false
+		for _, f := range folders {
+			if _, ok := added[f.UID]; ok {
+				continue
+			}
+			_, parentExported := byUID[f.ParentUID]
+			_, parentAdded := added[f.ParentUID]
+			if f.ParentUID == "" || !parentExported || parentAdded {
+				sorted = append(sorted, f)
+				added[f.UID] = struct{}{}
+				progress = true
+			}
+		}
+		if !progress {
+			// cycles can't happen in practice, keep the remaining folders in their original order
+			for _, f := range folders {
+				if _, ok := added[f.UID]; !ok {
+					sorted = append(sorted, f)
+					added[f.UID] = struct{}{}
+				}
+			}
+		}
+	}
+	return sorted
+}
+
+func (s *Service) findTeamByName(ctx context.Context, signedInUser identity.Requester, name string) (*team.TeamDTO, error) {
+	result, err := s.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        signedInUser.GetOrgID(),
+		Name:         name,
+		Limit:        1,
+		SignedInUser: signedInUser,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Teams) == 0 {
+		return nil, nil
+	}
+	return result.Teams[0], nil
+}
+
+// getSnapshotDataSources returns the data sources of the org without their secure json data: a snapshot is a plain
+// file, so the credentials have to be entered again on the instance it is imported into.
+func (s *Service) getSnapshotDataSources(ctx context.Context, orgID int64) ([]cloudmigration.MigrateDataRequestItemDTO, error) {
+	dataSources, err := s.dsService.GetDataSources(ctx, &datasources.GetDataSourcesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]cloudmigration.MigrateDataRequestItemDTO, 0, len(dataSources))
+	for _, ds := range dataSources {
+		items = append(items, cloudmigration.MigrateDataRequestItemDTO{
+			Type:  cloudmigration.DatasourceDataType,
+			RefID: ds.UID,
+			Name:  ds.Name,
+			Data: datasources.AddDataSourceCommand{
+				OrgID:           ds.OrgID,
+				Name:            ds.Name,
+				Type:            ds.Type,
+				Access:          ds.Access,
+				URL:             ds.URL,
+				User:            ds.User,
+				Database:        ds.Database,
+				BasicAuth:       ds.BasicAuth,
+				BasicAuthUser:   ds.BasicAuthUser,
+				WithCredentials: ds.WithCredentials,
+				IsDefault:       ds.IsDefault,
+				JsonData:        ds.JsonData,
+				ReadOnly:        ds.ReadOnly,
+				UID:             ds.UID,
+			},
+		})
+	}
+	return items, nil
+}
+
+func (s *Service) getTeams(ctx context.Context) ([]cloudmigration.MigrateDataRequestItemDTO, error) {
+	signedInUser := contexthandler.FromContext(ctx).SignedInUser
+	result, err := s.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        signedInUser.GetOrgID(),
+		SignedInUser: signedInUser,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]cloudmigration.MigrateDataRequestItemDTO, 0, len(result.Teams))
+	for _, t := range result.Teams {
+		items = append(items, cloudmigration.MigrateDataRequestItemDTO{
+			Type:  cloudmigration.TeamDataType,
+			RefID: t.UID,
+			Name:  t.Name,
+			Data:  cloudmigration.TeamData{Name: t.Name, Email: t.Email},
+		})
+	}
+	return items, nil
+}
+
+func (s *Service) getLibraryElements(ctx context.Context) ([]cloudmigration.MigrateDataRequestItemDTO, error) {
+	signedInUser := contexthandler.FromContext(ctx).SignedInUser
+
+	var items []cloudmigration.MigrateDataRequestItemDTO
+	for page := 1; ; page++ {
+		result, err := s.libraryElementService.GetAllElements(ctx, signedInUser, model.SearchLibraryElementsQuery{
+			PerPage: 100,
+			Page:    page,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, element := range result.Elements {
+			items = append(items, cloudmigration.MigrateDataRequestItemDTO{
+				Type:  cloudmigration.LibraryElementDataType,
+				RefID: element.UID,
+				Name:  element.Name,
+				Data:  element,
+			})
+		}
+		if len(result.Elements) < result.PerPage || int64(len(items)) >= result.TotalCount {
+			return items, nil
+		}
+	}
+}
+
+// getPermissions returns the managed permissions of the exported folders and dashboards. Inherited permissions and
+// the ones granted to service accounts are left out since they can't be recreated as is on another instance.
+func (s *Service) getPermissions(ctx context.Context, folders []folder.Folder, dashs []dashboards.Dashboard) ([]cloudmigration.MigrateDataRequestItemDTO, error) {
+	signedInUser := contexthandler.FromContext(ctx).SignedInUser
+
+	var items []cloudmigration.MigrateDataRequestItemDTO
+	add := func(service accesscontrol.PermissionsService, kind, uid, title string) error {
+		permissions, err := service.GetPermissions(ctx, signedInUser, uid)
+		if err != nil {
+			return fmt.Errorf("getting permissions of %s %s: %w", kind, uid, err)
+		}
+
+		entries := make([]cloudmigration.PermissionEntry, 0, len(permissions))
+		for _, p := range permissions {
+			if !p.IsManaged || p.IsInherited || p.IsServiceAccount {
+				continue
+			}
+			entries = append(entries, cloudmigration.PermissionEntry{
+				UserLogin:   p.UserLogin,
+				TeamName:    p.Team,
+				BuiltInRole: p.BuiltInRole,
+				Permission:  service.MapActions(p),
+			})
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		items = append(items, cloudmigration.MigrateDataRequestItemDTO{
+			Type:  cloudmigration.PermissionDataType,
+			RefID: kind + "/" + uid,
+			Name:  title,
+			Data: cloudmigration.PermissionData{
+				Kind:        kind,
+				ResourceUID: uid,
+				Permissions: entries,
+			},
+		})
+		return nil
+	}
+
+	for _, f := range folders {
+		if err := add(s.folderPermissions, permissionKindFolders, f.UID, f.Title); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range dashs {
+		if err := add(s.dashboardPermissions, permissionKindDashboards, d.UID, d.Title); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,200 @@
+package cloudmigrationimpl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/cloudmigration"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testSnapshotMaxSize = 1 << 20
+
+func Test_SnapshotArchiveRoundTrip(t *testing.T) {
+	manifest := cloudmigration.SnapshotManifest{
+		Version: cloudmigration.SnapshotVersion,
+		Created: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Items:   map[cloudmigration.MigrateDataType]int{cloudmigration.TeamDataType: 1, cloudmigration.FolderDataType: 1},
+	}
+	items := []cloudmigration.MigrateDataRequestItemDTO{
+		{Type: cloudmigration.FolderDataType, RefID: "f1", Name: "Folder", Data: folder.Folder{UID: "f1", Title: "Folder"}},
+		{Type: cloudmigration.TeamDataType, RefID: "t1", Name: "Team", Data: cloudmigration.TeamData{Name: "Team", Email: "team@example.com"}},
+	}
+
+	archive, err := writeSnapshot(manifest, items)
+	require.NoError(t, err)
+
+	readManifest, readItems, err := readSnapshot(archive, testSnapshotMaxSize)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, readManifest)
+	require.Len(t, readItems, 2)
+	assert.Equal(t, cloudmigration.FolderDataType, readItems[0].Type)
+	assert.Equal(t, "f1", readItems[0].RefID)
+	assert.JSONEq(t, `{"name":"Team","email":"team@example.com"}`, string(readItems[1].Data))
+}
+
+func Test_ReadSnapshotRejectsInvalidArchives(t *testing.T) {
+	t.Run("not gzipped", func(t *testing.T) {
+		_, _, err := readSnapshot([]byte("not an archive"), testSnapshotMaxSize)
+		require.ErrorIs(t, err, cloudmigration.ErrInvalidSnapshot)
+	})
+
+	t.Run("missing files", func(t *testing.T) {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		require.NoError(t, gw.Close())
+		_, _, err := readSnapshot(buf.Bytes(), testSnapshotMaxSize)
+		require.ErrorIs(t, err, cloudmigration.ErrInvalidSnapshot)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		items := []cloudmigration.MigrateDataRequestItemDTO{
+			{Type: cloudmigration.TeamDataType, RefID: "t1", Name: strings.Repeat("a", testSnapshotMaxSize)},
+		}
+		archive, err := writeSnapshot(cloudmigration.SnapshotManifest{Version: cloudmigration.SnapshotVersion}, items)
+		require.NoError(t, err)
+		require.Less(t, len(archive), testSnapshotMaxSize)
+		_, _, err = readSnapshot(archive, testSnapshotMaxSize)
+		require.ErrorIs(t, err, cloudmigration.ErrInvalidSnapshot)
+		require.ErrorContains(t, err, "maximum size")
+	})
+
+	t.Run("newer version", func(t *testing.T) {
+		archive, err := writeSnapshot(cloudmigration.SnapshotManifest{Version: cloudmigration.SnapshotVersion + 1}, nil)
+		require.NoError(t, err)
+		_, _, err = readSnapshot(archive, testSnapshotMaxSize)
+		require.ErrorIs(t, err, cloudmigration.ErrInvalidSnapshot)
+	})
+}
+
+func Test_SortFoldersByParent(t *testing.T) {
+	folders := []folder.Folder{
+		{UID: "c", ParentUID: "b"},
+		{UID: "b", ParentUID: "a"},
+		{UID: "d", ParentUID: "not-exported"},
+		{UID: "a"},
+	}
+
+	sorted := sortFoldersByParent(folders)
+	uids := make([]string, 0, len(sorted))
+	for _, f := range sorted {
+		uids = append(uids, f.UID)
+	}
+	assert.Equal(t, []string{"d", "a", "b", "c"}, uids)
+}
+
+func Test_ParseConflictStrategy(t *testing.T) {
+	strategy, err := cloudmigration.ParseConflictStrategy("")
+	require.NoError(t, err)
+	assert.Equal(t, cloudmigration.ConflictStrategySkip, strategy)
+
+	strategy, err = cloudmigration.ParseConflictStrategy("overwrite")
+	require.NoError(t, err)
+	assert.Equal(t, cloudmigration.ConflictStrategyOverwrite, strategy)
+
+	_, err = cloudmigration.ParseConflictStrategy("merge")
+	require.ErrorIs(t, err, cloudmigration.ErrInvalidConflictStrategy)
+}
+
+func Test_ImportSnapshot(t *testing.T) {
+	archive, err := writeSnapshot(cloudmigration.SnapshotManifest{Version: cloudmigration.SnapshotVersion}, []cloudmigration.MigrateDataRequestItemDTO{
+		{Type: cloudmigration.DatasourceDataType, RefID: "existing", Name: "New name", Data: datasources.AddDataSourceCommand{UID: "existing", Name: "New name", Type: "prometheus"}},
+		{Type: cloudmigration.DatasourceDataType, RefID: "created", Name: "Created", Data: datasources.AddDataSourceCommand{UID: "created", Name: "Created", Type: "loki"}},
+		{Type: cloudmigration.ContactPointDataType, RefID: "cp", Name: "Contact point", Data: map[string]any{"uid": "cp"}},
+	})
+	require.NoError(t, err)
+
+	setup := func(t *testing.T) (*Service, *fakes.FakeDataSourceService, context.Context) {
+		t.Helper()
+		dsService := &fakes.FakeDataSourceService{DataSources: []*datasources.DataSource{
+			{ID: 1, UID: "existing", Name: "Old name", Type: "prometheus", OrgID: 1},
+		}}
+		cfg := setting.NewCfg()
+		cfg.CloudMigration.SnapshotMaxSize = testSnapshotMaxSize
+		s := &Service{
+			log:       log.New(LogPrefix),
+			cfg:       cfg,
+			dsService: dsService,
+			tracer:    tracing.InitializeTracerForTest(),
+		}
+		ctx := ctxkey.Set(context.Background(), &contextmodel.ReqContext{
+			SignedInUser: &user.SignedInUser{OrgID: 1, UserID: 1},
+		})
+		return s, dsService, ctx
+	}
+
+	importStatuses := func(t *testing.T, result *cloudmigration.MigrateDataResponseDTO) map[string]cloudmigration.ItemStatus {
+		t.Helper()
+		statuses := make(map[string]cloudmigration.ItemStatus, len(result.Items))
+		for _, item := range result.Items {
+			statuses[item.RefID] = item.Status
+		}
+		return statuses
+	}
+
+	t.Run("skip keeps existing resources", func(t *testing.T) {
+		s, dsService, ctx := setup(t)
+		result, err := s.ImportSnapshot(ctx, archive, cloudmigration.ConflictStrategySkip)
+		require.NoError(t, err)
+
+		statuses := importStatuses(t, result)
+		assert.Equal(t, cloudmigration.ItemStatusSkipped, statuses["existing"])
+		assert.Equal(t, cloudmigration.ItemStatusOK, statuses["created"])
+		assert.Equal(t, "Old name", dsService.DataSources[0].Name)
+		require.Len(t, dsService.DataSources, 2)
+	})
+
+	t.Run("overwrite updates existing resources", func(t *testing.T) {
+		s, dsService, ctx := setup(t)
+		result, err := s.ImportSnapshot(ctx, archive, cloudmigration.ConflictStrategyOverwrite)
+		require.NoError(t, err)
+
+		statuses := importStatuses(t, result)
+		assert.Equal(t, cloudmigration.ItemStatusOK, statuses["existing"])
+		assert.Equal(t, cloudmigration.ItemStatusOK, statuses["created"])
+		assert.Equal(t, "New name", dsService.DataSources[0].Name)
+		require.Len(t, dsService.DataSources, 2)
+	})
+
+	t.Run("fail reports existing resources", func(t *testing.T) {
+		s, dsService, ctx := setup(t)
+		result, err := s.ImportSnapshot(ctx, archive, cloudmigration.ConflictStrategyFail)
+		require.NoError(t, err)
+
+		statuses := importStatuses(t, result)
+		assert.Equal(t, cloudmigration.ItemStatusError, statuses["existing"])
+		assert.Contains(t, result.Items[0].Error, errResourceExists.Error())
+		assert.Equal(t, cloudmigration.ItemStatusOK, statuses["created"])
+		assert.Equal(t, "Old name", dsService.DataSources[0].Name)
+	})
+
+	t.Run("alerting items fail when alerting is disabled", func(t *testing.T) {
+		s, _, ctx := setup(t)
+		result, err := s.ImportSnapshot(ctx, archive, cloudmigration.ConflictStrategySkip)
+		require.NoError(t, err)
+
+		assert.Equal(t, cloudmigration.ItemStatusError, importStatuses(t, result)["cp"])
+		assert.Equal(t, errAlertingDisabled.Error(), result.Items[2].Error)
+	})
+
+	t.Run("invalid archive", func(t *testing.T) {
+		s, _, ctx := setup(t)
+		_, err := s.ImportSnapshot(ctx, []byte("not an archive"), cloudmigration.ConflictStrategySkip)
+		require.ErrorIs(t, err, cloudmigration.ErrInvalidSnapshot)
+	})
+}
@@ -14,6 +14,8 @@ var (
 	ErrMigrationNotFound           = errutil.NotFound("cloudmigrations.migrationNotFound", errutil.WithPublicMessage("Migration not found"))
 	ErrMigrationRunNotFound        = errutil.NotFound("cloudmigrations.migrationRunNotFound", errutil.WithPublicMessage("Migration run not found"))
 	ErrMigrationNotDeleted         = errutil.Internal("cloudmigrations.migrationNotDeleted", errutil.WithPublicMessage("Migration not deleted"))
+	ErrInvalidSnapshot             = errutil.BadRequest("cloudmigrations.invalidSnapshot", errutil.WithPublicMessage("Invalid snapshot archive"))
+	ErrInvalidConflictStrategy     = errutil.BadRequest("cloudmigrations.invalidConflictStrategy", errutil.WithPublicMessage("Invalid conflict strategy"))
 )
 
 // cloud migration api dtos
@@ -147,9 +149,15 @@ type Base64HGInstance struct {
 type MigrateDataType string
 
 const (
-	DashboardDataType  MigrateDataType = "DASHBOARD"
-	DatasourceDataType MigrateDataType = "DATASOURCE"
-	FolderDataType     MigrateDataType = "FOLDER"
+	DashboardDataType          MigrateDataType = "DASHBOARD"
+	DatasourceDataType         MigrateDataType = "DATASOURCE"
+	FolderDataType             MigrateDataType = "FOLDER"
+	LibraryElementDataType     MigrateDataType = "LIBRARY_ELEMENT"
+	AlertRuleDataType          MigrateDataType = "ALERT_RULE"
+	ContactPointDataType       MigrateDataType = "CONTACT_POINT"
+	NotificationPolicyDataType MigrateDataType = "NOTIFICATION_POLICY"
+	TeamDataType               MigrateDataType = "TEAM"
+	PermissionDataType         MigrateDataType = "PERMISSION"
 )
 
 type MigrateDataRequestDTO struct {
@@ -167,8 +175,9 @@ type MigrateDataRequestItemDTO struct {
 type ItemStatus string
 
 const (
-	ItemStatusOK    ItemStatus = "OK"
-	ItemStatusError ItemStatus = "ERROR"
+	ItemStatusOK      ItemStatus = "OK"
+	ItemStatusError   ItemStatus = "ERROR"
+	ItemStatusSkipped ItemStatus = "SKIPPED"
 )
 
 type MigrateDataResponseDTO struct {
@@ -185,3 +194,68 @@ type MigrateDataResponseItemDTO struct {
 	Status ItemStatus `json:"status"`
 	Error  string     `json:"error,omitempty"`
 }
+
+// snapshot archives, used to move resources between self-hosted instances
+
+// SnapshotVersion is the version of the archive format written by the export
+const SnapshotVersion = 1
+
+// SnapshotManifest describes the content of a snapshot archive
+type SnapshotManifest struct {
+	Version        int                     `json:"version"`
+	GrafanaVersion string                  `json:"grafanaVersion"`
+	Created        time.Time               `json:"created"`
+	Items          map[MigrateDataType]int `json:"items"`
+}
+
+// swagger:enum ConflictStrategy
+type ConflictStrategy string
+
+const (
+	// ConflictStrategySkip keeps the resources that already exist on the target instance
+	ConflictStrategySkip ConflictStrategy = "skip"
+	// ConflictStrategyOverwrite replaces the resources that already exist on the target instance
+	ConflictStrategyOverwrite ConflictStrategy = "overwrite"
+	// ConflictStrategyFail reports an error for every resource that already exists on the target instance
+	ConflictStrategyFail ConflictStrategy = "fail"
+)
+
+func ParseConflictStrategy(s string) (ConflictStrategy, error) {
+	switch ConflictStrategy(s) {
+	case "":
+		return ConflictStrategySkip, nil
+	case ConflictStrategySkip, ConflictStrategyOverwrite, ConflictStrategyFail:
+		return ConflictStrategy(s), nil
+	default:
+		return "", ErrInvalidConflictStrategy.Errorf("unknown conflict strategy %q", s)
+	}
+}
+
+// DashboardData is the payload of a DASHBOARD item
+type DashboardData struct {
+	Dashboard any    `json:"dashboard"`
+	FolderUID string `json:"folderUid,omitempty"`
+}
+
+// TeamData is the payload of a TEAM item
+type TeamData struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// PermissionData is the payload of a PERMISSION item, it holds the managed permissions of a dashboard or folder
+type PermissionData struct {
+	// Kind is either dashboards or folders
+	Kind        string            `json:"kind"`
+	ResourceUID string            `json:"resourceUid"`
+	Permissions []PermissionEntry `json:"permissions"`
+}
+
+// PermissionEntry grants a permission to a user, a team or a basic role. Users and teams are identified by login and
+// name since their ids differ between instances.
+type PermissionEntry struct {
+	UserLogin   string `json:"userLogin,omitempty"`
+	TeamName    string `json:"teamName,omitempty"`
+	BuiltInRole string `json:"builtInRole,omitempty"`
+	Permission  string `json:"permission"`
+}
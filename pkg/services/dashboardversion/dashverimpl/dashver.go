@@ -3,6 +3,8 @@ package dashverimpl
 import (
 	"context"
 	"errors"
+	"sort"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -64,14 +66,108 @@ func (s *Service) Get(ctx context.Context, query *dashver.GetDashboardVersionQue
 	return version.ToDTO(query.DashboardUID), nil
 }
 
+// retentionPolicy describes which versions DeleteExpired may remove from
+// the dashboards it applies to.
+type retentionPolicy struct {
+	versionsToKeep  int
+	createdBefore   time.Time
+	protectedLabels []string
+	// folderUIDs restricts the policy to dashboards in these folders, or to
+	// dashboards in any other folder when excludeFolders is set.
+	folderUIDs     []string
+	excludeFolders bool
+}
+
+func newRetentionPolicy(retention setting.DashboardVersionRetention, now time.Time) retentionPolicy {
+	policy := retentionPolicy{
+		versionsToKeep:  retention.VersionsToKeep,
+		protectedLabels: retention.ProtectedLabels,
+	}
+	if policy.versionsToKeep < 1 {
+		policy.versionsToKeep = 1
+	}
+	if retention.MaxAge > 0 {
+		policy.createdBefore = now.Add(-retention.MaxAge)
+	}
+	return policy
+}
+
+// retentionPolicies returns one policy per folder with its own retention
+// settings, followed by the default policy for every other dashboard. The
+// policy of a folder applies to its subfolders as well, unless they have
+// their own retention settings.
+func (s *Service) retentionPolicies(ctx context.Context, now time.Time) ([]retentionPolicy, error) {
+	configured := make([]string, 0, len(s.cfg.DashboardVersionFolderRetention))
+	for uid := range s.cfg.DashboardVersionFolderRetention {
+		configured = append(configured, uid)
+	}
+	sort.Strings(configured)
+
+	policies := make([]retentionPolicy, 0, len(configured)+1)
+	var folderUIDs []string
+	for _, uid := range configured {
+		subtree, err := s.folderSubtree(ctx, uid)
+		if err != nil {
+			return nil, err
+		}
+		policy := newRetentionPolicy(s.cfg.DashboardVersionFolderRetention[uid], now)
+		policy.folderUIDs = subtree
+		policies = append(policies, policy)
+		folderUIDs = append(folderUIDs, subtree...)
+	}
+	sort.Strings(folderUIDs)
+
+	retention := s.cfg.DashboardVersionRetention
+	// The retention settings are not populated when the configuration
+	// wasn't loaded from an ini file.
+	if retention.VersionsToKeep == 0 {
+		retention.VersionsToKeep = s.cfg.DashboardVersionsToKeep
+	}
+	policy := newRetentionPolicy(retention, now)
+	policy.folderUIDs = folderUIDs
+	policy.excludeFolders = true
+	return append(policies, policy), nil
+}
+
+// folderSubtree returns the folder and its subfolders, leaving out the
+// subfolders with their own retention settings and their descendants.
+func (s *Service) folderSubtree(ctx context.Context, uid string) ([]string, error) {
+	subtree := []string{uid}
+	seen := map[string]bool{uid: true}
+	for parents := subtree; len(parents) > 0; {
+		children, err := s.store.GetSubfolderUIDs(ctx, parents)
+		if err != nil {
+			return nil, err
+		}
+		parents = nil
+		for _, child := range children {
+			if _, ok := s.cfg.DashboardVersionFolderRetention[child]; ok || seen[child] {
+				continue
+			}
+			seen[child] = true
+			parents = append(parents, child)
+		}
+		subtree = append(subtree, parents...)
+	}
+	return subtree, nil
+}
+
 func (s *Service) DeleteExpired(ctx context.Context, cmd *dashver.DeleteExpiredVersionsCommand) error {
-	versionsToKeep := s.cfg.DashboardVersionsToKeep
-	if versionsToKeep < 1 {
-		versionsToKeep = 1
+	policies, err := s.retentionPolicies(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if err := s.deleteExpired(ctx, cmd, policy); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+func (s *Service) deleteExpired(ctx context.Context, cmd *dashver.DeleteExpiredVersionsCommand, policy retentionPolicy) error {
 	for batch := 0; batch < maxVersionDeletionBatches; batch++ {
-		versionIdsToDelete, batchErr := s.store.GetBatch(ctx, cmd, maxVersionsToDeletePerBatch, versionsToKeep)
+		versionIdsToDelete, batchErr := s.store.GetBatch(ctx, cmd, maxVersionsToDeletePerBatch, policy)
 		if batchErr != nil {
 			return batchErr
 		}
@@ -94,6 +190,18 @@ func (s *Service) DeleteExpired(ctx context.Context, cmd *dashver.DeleteExpiredV
 	return nil
 }
 
+// SetLabel sets or removes the label of a dashboard version.
+func (s *Service) SetLabel(ctx context.Context, cmd *dashver.SetDashboardVersionLabelCommand) error {
+	if cmd.DashboardID == 0 {
+		id, err := s.getDashIDMaybeEmpty(ctx, cmd.DashboardUID)
+		if err != nil {
+			return err
+		}
+		cmd.DashboardID = id
+	}
+	return s.store.SetLabel(ctx, cmd)
+}
+
 // List all dashboard versions for the given dashboard ID.
 func (s *Service) List(ctx context.Context, query *dashver.ListDashboardVersionsQuery) ([]*dashver.DashboardVersionDTO, error) {
 	// Get the DashboardUID if not populated
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -34,6 +35,54 @@ func TestDashboardVersionService(t *testing.T) {
 	})
 }
 
+func TestRetentionPolicies(t *testing.T) {
+	now := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	cfg := setting.NewCfg()
+	cfg.DashboardVersionsToKeep = 20
+	cfg.DashboardVersionRetention = setting.DashboardVersionRetention{
+		VersionsToKeep:  20,
+		ProtectedLabels: []string{"release"},
+	}
+	cfg.DashboardVersionFolderRetention = map[string]setting.DashboardVersionRetention{
+		"scratch":  {VersionsToKeep: 0, MaxAge: 24 * time.Hour},
+		"critical": {VersionsToKeep: 100, MaxAge: 365 * 24 * time.Hour, ProtectedLabels: []string{"release", "audit"}},
+	}
+
+	dashboardVersionStore := newDashboardVersionStoreFake()
+	// scratch is a subfolder of critical with its own retention settings
+	dashboardVersionStore.ExpectedSubfolders = map[string][]string{
+		"critical":       {"critical-child", "scratch"},
+		"critical-child": {"critical-grandchild"},
+	}
+	dashboardVersionService := Service{cfg: cfg, store: dashboardVersionStore}
+
+	err := dashboardVersionService.DeleteExpired(context.Background(), &dashver.DeleteExpiredVersionsCommand{})
+	require.NoError(t, err)
+	require.Len(t, dashboardVersionStore.Policies, 3)
+
+	policies, err := dashboardVersionService.retentionPolicies(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, []retentionPolicy{
+		{
+			versionsToKeep:  100,
+			createdBefore:   now.Add(-365 * 24 * time.Hour),
+			protectedLabels: []string{"release", "audit"},
+			folderUIDs:      []string{"critical", "critical-child", "critical-grandchild"},
+		},
+		{
+			versionsToKeep: 1,
+			createdBefore:  now.Add(-24 * time.Hour),
+			folderUIDs:     []string{"scratch"},
+		},
+		{
+			versionsToKeep:  20,
+			protectedLabels: []string{"release"},
+			folderUIDs:      []string{"critical", "critical-child", "critical-grandchild", "scratch"},
+			excludeFolders:  true,
+		},
+	}, policies)
+}
+
 func TestDeleteExpiredVersions(t *testing.T) {
 	versionsToKeep := 5
 	cfg := setting.NewCfg()
@@ -152,6 +201,8 @@ type FakeDashboardVersionStore struct {
 	ExpectedVersions         []any
 	ExpectedListVersions     []*dashver.DashboardVersion
 	ExpectedError            error
+	ExpectedSubfolders       map[string][]string
+	Policies                 []retentionPolicy
 }
 
 func newDashboardVersionStoreFake() *FakeDashboardVersionStore {
@@ -162,7 +213,8 @@ func (f *FakeDashboardVersionStore) Get(ctx context.Context, query *dashver.GetD
 	return f.ExpectedDashboardVersion, f.ExpectedError
 }
 
-func (f *FakeDashboardVersionStore) GetBatch(ctx context.Context, cmd *dashver.DeleteExpiredVersionsCommand, perBatch int, versionsToKeep int) ([]any, error) {
+func (f *FakeDashboardVersionStore) GetBatch(ctx context.Context, cmd *dashver.DeleteExpiredVersionsCommand, perBatch int, policy retentionPolicy) ([]any, error) {
+	f.Policies = append(f.Policies, policy)
 	return f.ExpectedVersions, f.ExpectedError
 }
 
@@ -173,3 +225,15 @@ func (f *FakeDashboardVersionStore) DeleteBatch(ctx context.Context, cmd *dashve
 func (f *FakeDashboardVersionStore) List(ctx context.Context, query *dashver.ListDashboardVersionsQuery) ([]*dashver.DashboardVersion, error) {
 	return f.ExpectedListVersions, f.ExpectedError
 }
+
+func (f *FakeDashboardVersionStore) SetLabel(ctx context.Context, cmd *dashver.SetDashboardVersionLabelCommand) error {
+	return f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) GetSubfolderUIDs(ctx context.Context, parentUIDs []string) ([]string, error) {
+	var uids []string
+	for _, uid := range parentUIDs {
+		uids = append(uids, f.ExpectedSubfolders[uid]...)
+	}
+	return uids, nil
+}
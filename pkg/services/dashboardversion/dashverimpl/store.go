@@ -8,7 +8,9 @@ import (
 
 type store interface {
 	Get(context.Context, *dashver.GetDashboardVersionQuery) (*dashver.DashboardVersion, error)
-	GetBatch(context.Context, *dashver.DeleteExpiredVersionsCommand, int, int) ([]any, error)
+	GetBatch(context.Context, *dashver.DeleteExpiredVersionsCommand, int, retentionPolicy) ([]any, error)
 	DeleteBatch(context.Context, *dashver.DeleteExpiredVersionsCommand, []any) (int64, error)
 	List(context.Context, *dashver.ListDashboardVersionsQuery) ([]*dashver.DashboardVersion, error)
+	SetLabel(context.Context, *dashver.SetDashboardVersionLabelCommand) error
+	GetSubfolderUIDs(context.Context, []string) ([]string, error)
 }
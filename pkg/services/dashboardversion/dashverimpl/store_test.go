@@ -116,6 +116,76 @@ func testIntegrationGetDashboardVersion(t *testing.T, fn getStore) {
 		require.Nil(t, err)
 		assert.Equal(t, 2, len(res))
 	})
+
+	t.Run("Get expired versions according to a retention policy", func(t *testing.T) {
+		dash := insertTestDashboard(t, ss, "test dash retention", 1, "retention-folder", false, "retention")
+		for i := 0; i < 4; i++ {
+			updateTestDashboard(t, ss, dash, map[string]any{
+				"title": "test dash retention",
+				"tags":  "v" + strconv.Itoa(i),
+			})
+		}
+
+		err := dashVerStore.SetLabel(context.Background(), &dashver.SetDashboardVersionLabelCommand{
+			DashboardID: dash.ID, OrgID: 1, Version: 2, Label: "release",
+		})
+		require.NoError(t, err)
+
+		res, err := dashVerStore.List(context.Background(), &dashver.ListDashboardVersionsQuery{DashboardID: dash.ID, OrgID: 1, Limit: 1000})
+		require.NoError(t, err)
+		require.Len(t, res, 5)
+		assert.Equal(t, "release", res[3].Label)
+
+		// versions 4 and 5 are the most recent ones and version 2 is
+		// protected, which leaves versions 1 and 3.
+		policy := retentionPolicy{versionsToKeep: 2, protectedLabels: []string{"release"}, folderUIDs: []string{"retention-folder"}}
+		ids, err := dashVerStore.GetBatch(context.Background(), &dashver.DeleteExpiredVersionsCommand{}, 100, policy)
+		require.NoError(t, err)
+		assert.Len(t, ids, 2)
+
+		// only version 1 has four newer versions
+		ids, err = dashVerStore.GetBatch(context.Background(), &dashver.DeleteExpiredVersionsCommand{}, 100, retentionPolicy{versionsToKeep: 4, folderUIDs: []string{"retention-folder"}})
+		require.NoError(t, err)
+		assert.Len(t, ids, 1)
+
+		policy.createdBefore = time.Now().Add(-time.Hour)
+		ids, err = dashVerStore.GetBatch(context.Background(), &dashver.DeleteExpiredVersionsCommand{}, 100, policy)
+		require.NoError(t, err)
+		assert.Len(t, ids, 0)
+
+		policy = retentionPolicy{versionsToKeep: 2, folderUIDs: []string{"another-folder"}}
+		ids, err = dashVerStore.GetBatch(context.Background(), &dashver.DeleteExpiredVersionsCommand{}, 100, policy)
+		require.NoError(t, err)
+		assert.Len(t, ids, 0)
+	})
+
+	t.Run("Get the subfolders of folders", func(t *testing.T) {
+		err := ss.WithDbSession(context.Background(), func(sess *db.Session) error {
+			for uid, parentUID := range map[string]any{"parent": nil, "child": "parent", "grandchild": "child", "other": nil} {
+				if _, err := sess.Exec("INSERT INTO folder (uid, org_id, title, parent_uid, created, updated) VALUES (?, 1, ?, ?, ?, ?)",
+					uid, uid, parentUID, time.Now(), time.Now()); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+
+		uids, err := dashVerStore.GetSubfolderUIDs(context.Background(), []string{"parent"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"child"}, uids)
+
+		uids, err = dashVerStore.GetSubfolderUIDs(context.Background(), []string{"child", "other"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"grandchild"}, uids)
+	})
+
+	t.Run("Attempt to label a version that doesn't exist", func(t *testing.T) {
+		err := dashVerStore.SetLabel(context.Background(), &dashver.SetDashboardVersionLabelCommand{
+			DashboardID: savedDash.ID, OrgID: 1, Version: 123, Label: "release",
+		})
+		assert.ErrorIs(t, err, dashver.ErrDashboardVersionNotFound)
+	})
 }
 
 func getDashboard(t *testing.T, sqlStore db.DB, dashboard *dashboards.Dashboard) error {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 )
 
@@ -36,25 +37,101 @@ func (ss *sqlStore) Get(ctx context.Context, query *dashver.GetDashboardVersionQ
 	return &version, nil
 }
 
-func (ss *sqlStore) GetBatch(ctx context.Context, cmd *dashver.DeleteExpiredVersionsCommand, perBatch int, versionsToKeep int) ([]any, error) {
+// GetBatch returns the IDs of up to perBatch versions that are expired
+// according to the policy. A version is expired when its dashboard has at
+// least versionsToKeep newer versions, it is older than the policy's cutoff
+// and it does not have a protected label.
+func (ss *sqlStore) GetBatch(ctx context.Context, cmd *dashver.DeleteExpiredVersionsCommand, perBatch int, policy retentionPolicy) ([]any, error) {
 	var versionIds []any
 	err := ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
-		versionIdsToDeleteQuery := `SELECT id
-			FROM dashboard_version, (
-				SELECT dashboard_id, count(version) as count, min(version) as min
-				FROM dashboard_version
-				GROUP BY dashboard_id
-			) AS vtd
-			WHERE dashboard_version.dashboard_id=vtd.dashboard_id
-			AND version < vtd.min + vtd.count - ?
-			LIMIT ?`
-
-		err := sess.SQL(versionIdsToDeleteQuery, versionsToKeep, perBatch).Find(&versionIds)
-		return err
+		// Only dashboards with more versions than the policy keeps can have
+		// expired versions.
+		var sql strings.Builder
+		var args []any
+		sql.WriteString(`SELECT dashboard_version.dashboard_id
+			FROM dashboard_version
+			LEFT JOIN dashboard ON dashboard.id = dashboard_version.dashboard_id`)
+		if len(policy.folderUIDs) > 0 {
+			// dashboards at the root level have no folder UID, they are
+			// matched by the general folder UID.
+			sql.WriteString(` WHERE COALESCE(dashboard.folder_uid, '')`)
+			if policy.excludeFolders {
+				sql.WriteString(` NOT`)
+			}
+			sql.WriteString(` IN (?` + strings.Repeat(",?", len(policy.folderUIDs)-1) + `)`)
+			for _, uid := range policy.folderUIDs {
+				if uid == folder.GeneralFolderUID {
+					uid = ""
+				}
+				args = append(args, uid)
+			}
+		}
+		sql.WriteString(` GROUP BY dashboard_version.dashboard_id HAVING count(*) > ?`)
+		args = append(args, policy.versionsToKeep)
+
+		var dashboardIDs []int64
+		if err := sess.SQL(sql.String(), args...).Find(&dashboardIDs); err != nil {
+			return err
+		}
+
+		for _, dashboardID := range dashboardIDs {
+			// The oldest version to keep is found by skipping the newer
+			// ones, which uses the (dashboard_id, version) index.
+			var oldestKept []int64
+			if err := sess.SQL(`SELECT version FROM dashboard_version WHERE dashboard_id = ? ORDER BY version DESC`+
+				ss.dialect.LimitOffset(1, int64(policy.versionsToKeep-1)), dashboardID).Find(&oldestKept); err != nil {
+				return err
+			}
+			if len(oldestKept) == 0 {
+				continue
+			}
+
+			sql.Reset()
+			sql.WriteString(`SELECT id FROM dashboard_version WHERE dashboard_id = ? AND version < ?`)
+			args = []any{dashboardID, oldestKept[0]}
+			if !policy.createdBefore.IsZero() {
+				sql.WriteString(` AND created < ?`)
+				args = append(args, policy.createdBefore)
+			}
+			if len(policy.protectedLabels) > 0 {
+				sql.WriteString(` AND COALESCE(label, '') NOT IN (?` + strings.Repeat(",?", len(policy.protectedLabels)-1) + `)`)
+				for _, label := range policy.protectedLabels {
+					args = append(args, label)
+				}
+			}
+			sql.WriteString(ss.dialect.Limit(int64(perBatch - len(versionIds))))
+
+			var ids []any
+			if err := sess.SQL(sql.String(), args...).Find(&ids); err != nil {
+				return err
+			}
+			versionIds = append(versionIds, ids...)
+			if len(versionIds) >= perBatch {
+				break
+			}
+		}
+		return nil
 	})
 	return versionIds, err
 }
 
+// GetSubfolderUIDs returns the UIDs of the folders whose parent is one of
+// the given folders.
+func (ss *sqlStore) GetSubfolderUIDs(ctx context.Context, parentUIDs []string) ([]string, error) {
+	var uids []string
+	if len(parentUIDs) == 0 {
+		return uids, nil
+	}
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		args := make([]any, 0, len(parentUIDs))
+		for _, uid := range parentUIDs {
+			args = append(args, uid)
+		}
+		return sess.SQL(`SELECT DISTINCT uid FROM folder WHERE parent_uid IN (?`+strings.Repeat(",?", len(parentUIDs)-1)+`)`, args...).Find(&uids)
+	})
+	return uids, err
+}
+
 func (ss *sqlStore) DeleteBatch(ctx context.Context, cmd *dashver.DeleteExpiredVersionsCommand, versionIdsToDelete []any) (int64, error) {
 	var deleted int64
 	err := ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
@@ -83,7 +160,8 @@ func (ss *sqlStore) List(ctx context.Context, query *dashver.ListDashboardVersio
 				dashboard_version.created,
 				dashboard_version.created_by,
 				dashboard_version.message,
-				dashboard_version.data`).
+				dashboard_version.data,
+				dashboard_version.label`).
 			Join("LEFT", "dashboard", `dashboard.id = dashboard_version.dashboard_id`).
 			Where("dashboard_version.dashboard_id=? AND dashboard.org_id=?", query.DashboardID, query.OrgID).
 			OrderBy("dashboard_version.version DESC").
@@ -103,3 +181,21 @@ func (ss *sqlStore) List(ctx context.Context, query *dashver.ListDashboardVersio
 	}
 	return dashboardVersion, nil
 }
+
+func (ss *sqlStore) SetLabel(ctx context.Context, cmd *dashver.SetDashboardVersionLabelCommand) error {
+	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Table("dashboard_version").
+			Join("LEFT", "dashboard", `dashboard.id = dashboard_version.dashboard_id`).
+			Where("dashboard_version.dashboard_id=? AND dashboard_version.version=? AND dashboard.org_id=?", cmd.DashboardID, cmd.Version, cmd.OrgID).
+			Exist()
+		if err != nil {
+			return err
+		}
+		if !has {
+			return dashver.ErrDashboardVersionNotFound
+		}
+
+		_, err = sess.Exec("UPDATE dashboard_version SET label=? WHERE dashboard_id=? AND version=?", cmd.Label, cmd.DashboardID, cmd.Version)
+		return err
+	})
+}
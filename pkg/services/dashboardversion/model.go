@@ -28,6 +28,10 @@ type DashboardVersion struct {
 
 	Message string           `json:"message" db:"message"`
 	Data    *simplejson.Json `json:"data" db:"data"`
+
+	// Label marks a version, versions with a protected label are never
+	// removed by the retention policy.
+	Label string `json:"label" db:"label"`
 }
 
 // ToDTO converts a DashboardVersion to a DashboardVersionDTO.
@@ -43,6 +47,7 @@ func (v *DashboardVersion) ToDTO(dashUid string) *DashboardVersionDTO {
 		CreatedBy:     v.CreatedBy,
 		Message:       v.Message,
 		Data:          v.Data,
+		Label:         v.Label,
 	}
 }
 
@@ -59,6 +64,16 @@ type DeleteExpiredVersionsCommand struct {
 	DeletedRows int64
 }
 
+// SetDashboardVersionLabelCommand sets the label of a dashboard version. Only
+// one of DashboardID and DashboardUID are required, an empty label removes it.
+type SetDashboardVersionLabelCommand struct {
+	DashboardID  int64
+	DashboardUID string
+	OrgID        int64
+	Version      int
+	Label        string
+}
+
 type ListDashboardVersionsQuery struct {
 	DashboardID  int64
 	DashboardUID string
@@ -77,6 +92,7 @@ type DashboardVersionDTO struct {
 	CreatedBy     int64            `json:"createdBy"`
 	Message       string           `json:"message"`
 	Data          *simplejson.Json `json:"data" db:"data"`
+	Label         string           `json:"label"`
 }
 
 // DashboardVersionMeta extends the DashboardVersionDTO with the names
@@ -93,4 +109,5 @@ type DashboardVersionMeta struct {
 	Message       string           `json:"message"`
 	Data          *simplejson.Json `json:"data"`
 	CreatedBy     string           `json:"createdBy"`
+	Label         string           `json:"label"`
 }
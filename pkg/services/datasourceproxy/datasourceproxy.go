@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/queryquota"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/validations"
 	"github.com/grafana/grafana/pkg/setting"
@@ -27,7 +28,8 @@ import (
 func ProvideService(dataSourceCache datasources.CacheService, plugReqValidator validations.PluginRequestValidator,
 	pluginStore pluginstore.Store, cfg *setting.Cfg, httpClientProvider httpclient.Provider,
 	oauthTokenService *oauthtoken.Service, dsService datasources.DataSourceService,
-	tracer tracing.Tracer, secretsService secrets.Service, features featuremgmt.FeatureToggles) *DataSourceProxyService {
+	tracer tracing.Tracer, secretsService secrets.Service, features featuremgmt.FeatureToggles,
+	queryQuota queryquota.Service) *DataSourceProxyService {
 	return &DataSourceProxyService{
 		DataSourceCache:        dataSourceCache,
 		PluginRequestValidator: plugReqValidator,
@@ -39,6 +41,7 @@ func ProvideService(dataSourceCache datasources.CacheService, plugReqValidator v
 		tracer:                 tracer,
 		secretsService:         secretsService,
 		features:               features,
+		queryQuota:             queryQuota,
 	}
 }
 
@@ -53,6 +56,7 @@ type DataSourceProxyService struct {
 	tracer                 tracing.Tracer
 	secretsService         secrets.Service
 	features               featuremgmt.FeatureToggles
+	queryQuota             queryquota.Service
 }
 
 func (p *DataSourceProxyService) ProxyDataSourceRequest(c *contextmodel.ReqContext) {
@@ -114,6 +118,14 @@ func (p *DataSourceProxyService) proxyDatasourceRequest(c *contextmodel.ReqConte
 		return
 	}
 
+	// every proxied request counts as a query
+	if p.queryQuota != nil {
+		if err := p.queryQuota.CheckQuota(c.Req.Context(), c.SignedInUser, 1); err != nil {
+			c.WriteErrOrFallback(http.StatusInternalServerError, "Failed to check query quota", err)
+			return
+		}
+	}
+
 	// find plugin
 	plugin, exists := p.pluginStore.Plugin(c.Req.Context(), ds.Type)
 	if !exists {
@@ -36,6 +36,15 @@ type DataSourceService interface {
 	// UpdateDataSource updates an existing datasource.
 	UpdateDataSource(ctx context.Context, cmd *UpdateDataSourceCommand) (*DataSource, error)
 
+	// GetDataSourceVersion gets a version of a datasource.
+	GetDataSourceVersion(ctx context.Context, query *GetDataSourceVersionQuery) (*DataSourceVersion, error)
+
+	// ListDataSourceVersions lists the versions of a datasource from the newest to the oldest one.
+	ListDataSourceVersions(ctx context.Context, query *ListDataSourceVersionsQuery) ([]*DataSourceVersion, error)
+
+	// RestoreDataSourceVersion updates a datasource with the configuration of a previous version.
+	RestoreDataSourceVersion(ctx context.Context, cmd *RestoreDataSourceVersionCommand) (*DataSource, error)
+
 	// GetHTTPTransport gets a datasource specific HTTP transport.
 	GetHTTPTransport(ctx context.Context, ds *DataSource, provider httpclient.Provider, customMiddlewares ...sdkhttpclient.Middleware) (http.RoundTripper, error)
 
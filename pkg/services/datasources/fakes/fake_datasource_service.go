@@ -13,6 +13,7 @@ import (
 type FakeDataSourceService struct {
 	lastID                int64
 	DataSources           []*datasources.DataSource
+	DataSourceVersions    []*datasources.DataSourceVersion
 	SimulatePluginFailure bool
 }
 
@@ -112,6 +113,40 @@ func (s *FakeDataSourceService) UpdateDataSource(ctx context.Context, cmd *datas
 	return nil, datasources.ErrDataSourceNotFound
 }
 
+func (s *FakeDataSourceService) GetDataSourceVersion(ctx context.Context, query *datasources.GetDataSourceVersionQuery) (*datasources.DataSourceVersion, error) {
+	for _, version := range s.DataSourceVersions {
+		if version.OrgID == query.OrgID && version.DataSourceUID == query.DataSourceUID && version.Version == query.Version {
+			return version, nil
+		}
+	}
+	return nil, datasources.ErrDataSourceVersionNotFound
+}
+
+func (s *FakeDataSourceService) ListDataSourceVersions(ctx context.Context, query *datasources.ListDataSourceVersionsQuery) ([]*datasources.DataSourceVersion, error) {
+	var versions []*datasources.DataSourceVersion
+	for _, version := range s.DataSourceVersions {
+		if version.OrgID == query.OrgID && version.DataSourceUID == query.DataSourceUID {
+			versions = append(versions, version)
+		}
+	}
+	return versions, nil
+}
+
+func (s *FakeDataSourceService) RestoreDataSourceVersion(ctx context.Context, cmd *datasources.RestoreDataSourceVersionCommand) (*datasources.DataSource, error) {
+	version, err := s.GetDataSourceVersion(ctx, &datasources.GetDataSourceVersionQuery{
+		OrgID:         cmd.OrgID,
+		DataSourceUID: cmd.DataSourceUID,
+		Version:       cmd.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.UpdateDataSource(ctx, &datasources.UpdateDataSourceCommand{
+		UID:  cmd.DataSourceUID,
+		Name: version.Data.Name,
+	})
+}
+
 func (s *FakeDataSourceService) GetHTTPTransport(ctx context.Context, ds *datasources.DataSource, provider httpclient.Provider, customMiddlewares ...sdkhttpclient.Middleware) (http.RoundTripper, error) {
 	rt, err := provider.GetTransport(sdkhttpclient.Options{})
 	if err != nil {
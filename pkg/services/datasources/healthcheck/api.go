@@ -0,0 +1,62 @@
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(router routing.RouteRegister, ac accesscontrol.AccessControl) {
+	authorize := accesscontrol.Middleware(ac)
+	uidScope := datasources.ScopeProvider.GetResourceScopeUID(accesscontrol.Parameter(":uid"))
+
+	router.Get("/api/datasources/uid/:uid/health/history",
+		authorize(accesscontrol.EvalPermission(datasources.ActionRead, uidScope)),
+		routing.Wrap(s.getHistory))
+}
+
+// swagger:route GET /datasources/uid/{uid}/health/history datasources getDataSourceHealthHistory
+//
+// Get the background health check history of a data source.
+//
+// Responses:
+// 200: getDataSourceHealthHistoryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+func (s *Service) getHistory(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if !util.IsValidShortUID(uid) {
+		return response.Error(http.StatusBadRequest, "UID is invalid", nil)
+	}
+
+	history, err := s.GetHistory(c.SignedInUser.GetOrgID(), uid)
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return response.Error(http.StatusNotFound, "No health check history for data source", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get health check history", err)
+	}
+	return response.JSON(http.StatusOK, history)
+}
+
+// swagger:parameters getDataSourceHealthHistory
+type GetDataSourceHealthHistoryParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"uid"`
+}
+
+// swagger:response getDataSourceHealthHistoryResponse
+type GetDataSourceHealthHistoryResponse struct {
+	// in:body
+	Body HistoryDTO `json:"body"`
+}
@@ -0,0 +1,378 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// tickInterval is how often the service looks for data sources due for a check.
+const tickInterval = 10 * time.Second
+
+// unsupportedRecheckInterval is how often data sources whose plugin has no backend
+// health check are checked again, in case the plugin was installed or updated.
+const unsupportedRecheckInterval = time.Hour
+
+// Service periodically runs the plugin health check of every data source and
+// keeps the latest results in memory.
+type Service struct {
+	cfg                   setting.DataSourceHealthCheckSettings
+	dataSourceService     datasources.DataSourceService
+	pluginContextProvider *plugincontext.Provider
+	pluginClient          plugins.Client
+	bus                   bus.Bus
+	metrics               *metrics
+	log                   log.Logger
+
+	mu        sync.RWMutex
+	histories map[historyKey]*history
+}
+
+type historyKey struct {
+	orgID int64
+	uid   string
+}
+
+type history struct {
+	interval  time.Duration
+	nextCheck time.Time
+	// dsType and dsUpdated identify the data source settings the history is for.
+	dsType    string
+	dsUpdated time.Time
+	// unsupportedUntil is set when the plugin of the data source has no backend
+	// health check, the data source isn't checked again until then.
+	unsupportedUntil time.Time
+	// results is a ring buffer, next points to the slot for the next result.
+	results []Result
+	next    int
+	full    bool
+}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	dataSourceService datasources.DataSourceService,
+	pluginContextProvider *plugincontext.Provider,
+	pluginClient plugins.Client,
+	bus bus.Bus,
+	prom prometheus.Registerer,
+	routeRegister routing.RouteRegister,
+	ac accesscontrol.AccessControl,
+) (*Service, error) {
+	s := &Service{
+		cfg:                   cfg.DataSourceHealthCheck,
+		dataSourceService:     dataSourceService,
+		pluginContextProvider: pluginContextProvider,
+		pluginClient:          pluginClient,
+		bus:                   bus,
+		metrics:               newMetrics(),
+		log:                   log.New("datasources.healthcheck"),
+		histories:             map[historyKey]*history{},
+	}
+
+	if s.IsDisabled() {
+		return s, nil
+	}
+
+	if err := s.metrics.register(prom); err != nil {
+		return nil, err
+	}
+	s.registerAPIEndpoints(routeRegister, ac)
+
+	return s, nil
+}
+
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.Enabled
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		s.checkDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkDue runs the health checks of all data sources whose interval elapsed.
+func (s *Service) checkDue(ctx context.Context) {
+	dss, err := s.dataSourceService.GetAllDataSources(ctx, &datasources.GetAllDataSourcesQuery{})
+	if err != nil {
+		s.log.Error("Failed to get data sources", "error", err)
+		return
+	}
+
+	now := time.Now()
+	existing := make(map[historyKey]struct{}, len(dss))
+	var due []*datasources.DataSource
+
+	s.mu.Lock()
+	for _, ds := range dss {
+		key := historyKey{orgID: ds.OrgID, uid: ds.UID}
+		existing[key] = struct{}{}
+
+		h, ok := s.histories[key]
+		if !ok {
+			h = &history{results: make([]Result, s.cfg.HistorySize)}
+			s.histories[key] = h
+		}
+		if h.due(ds, s.interval(ds), now) {
+			due = append(due, ds)
+		}
+	}
+	// Forget data sources which were deleted.
+	for key := range s.histories {
+		if _, ok := existing[key]; !ok {
+			delete(s.histories, key)
+			s.metrics.status.DeletePartialMatch(prometheus.Labels{
+				"org_id":         strconv.FormatInt(key.orgID, 10),
+				"datasource_uid": key.uid,
+			})
+		}
+	}
+	s.mu.Unlock()
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.cfg.MaxConcurrentChecks)
+	for _, ds := range due {
+		ds := ds
+		g.Go(func() error {
+			s.check(ctx, ds)
+			return nil
+		})
+	}
+	_ = g.Wait()
+}
+
+// due reports whether the data source must be checked now, and schedules its next check.
+func (h *history) due(ds *datasources.DataSource, interval time.Duration, now time.Time) bool {
+	if h.dsType != ds.Type || !h.dsUpdated.Equal(ds.Updated) {
+		// The data source was changed, its plugin may support health checks now.
+		h.dsType = ds.Type
+		h.dsUpdated = ds.Updated
+		h.unsupportedUntil = time.Time{}
+		h.nextCheck = now
+	}
+	if now.Before(h.unsupportedUntil) {
+		return false
+	}
+	if h.interval != interval {
+		h.interval = interval
+		h.nextCheck = now
+	}
+	if interval == 0 || now.Before(h.nextCheck) {
+		return false
+	}
+	h.nextCheck = now.Add(interval)
+	return true
+}
+
+// interval returns the health check interval of the data source, 0 means the
+// checks are disabled.
+func (s *Service) interval(ds *datasources.DataSource) time.Duration {
+	if ds.JsonData == nil {
+		return s.cfg.DefaultInterval
+	}
+	raw, err := ds.JsonData.Get(JSONDataIntervalKey).String()
+	if err != nil || raw == "" {
+		return s.cfg.DefaultInterval
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		s.log.Warn("Invalid health check interval, using default", "uid", ds.UID, "interval", raw, "error", err)
+		return s.cfg.DefaultInterval
+	}
+	if interval <= 0 {
+		return 0
+	}
+	if interval < s.cfg.MinInterval {
+		return s.cfg.MinInterval
+	}
+	return interval
+}
+
+func (s *Service) check(ctx context.Context, ds *datasources.DataSource) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := Result{Timestamp: start}
+
+	resp, err := s.checkHealth(ctx, ds)
+	result.Latency = time.Since(start)
+	switch {
+	case errors.Is(err, plugins.ErrMethodNotImplemented), errors.Is(err, plugins.ErrPluginNotRegistered):
+		// Plugin has no backend health check, nothing to record and no need to check
+		// again until the data source changes or the plugin may have been updated.
+		s.mu.Lock()
+		if h, ok := s.histories[historyKey{orgID: ds.OrgID, uid: ds.UID}]; ok {
+			h.unsupportedUntil = time.Now().Add(unsupportedRecheckInterval)
+		}
+		s.mu.Unlock()
+		return
+	case err != nil:
+		result.Status = StatusError
+		result.Message = err.Error()
+	case resp.Status == backend.HealthStatusOk:
+		result.Status = StatusOK
+		result.Message = resp.Message
+	case resp.Status == backend.HealthStatusError:
+		result.Status = StatusError
+		result.Message = resp.Message
+	default:
+		result.Status = StatusUnknown
+		result.Message = resp.Message
+	}
+
+	s.metrics.duration.WithLabelValues(ds.Type, string(result.Status)).Observe(result.Latency.Seconds())
+	s.metrics.checks.WithLabelValues(ds.Type, string(result.Status)).Inc()
+	healthy := 0.0
+	if result.Status == StatusOK {
+		healthy = 1
+	}
+	s.metrics.status.WithLabelValues(strconv.FormatInt(ds.OrgID, 10), ds.UID, ds.Type).Set(healthy)
+
+	previous, ok := s.record(ds, result)
+	if ok && previous != result.Status {
+		s.log.Info("Data source health changed", "uid", ds.UID, "orgId", ds.OrgID, "status", result.Status, "previous", previous)
+		if err := s.bus.Publish(ctx, &events.DataSourceHealthChanged{
+			Timestamp:      result.Timestamp,
+			Name:           ds.Name,
+			UID:            ds.UID,
+			OrgID:          ds.OrgID,
+			Type:           ds.Type,
+			Status:         string(result.Status),
+			PreviousStatus: string(previous),
+			Message:        result.Message,
+		}); err != nil {
+			s.log.Error("Failed to publish data source health changed event", "uid", ds.UID, "error", err)
+		}
+	}
+}
+
+func (s *Service) checkHealth(ctx context.Context, ds *datasources.DataSource) (*backend.CheckHealthResult, error) {
+	user := accesscontrol.BackgroundUser("datasource_health_check", ds.OrgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(ds.UID)},
+	})
+	pCtx, err := s.pluginContextProvider.GetWithDataSource(ctx, ds.Type, user, ds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plugin context: %w", err)
+	}
+	return s.pluginClient.CheckHealth(ctx, &backend.CheckHealthRequest{
+		PluginContext: pCtx,
+		Headers:       map[string]string{},
+	})
+}
+
+// record stores the result and returns the status of the previous check, if any.
+func (s *Service) record(ds *datasources.DataSource, result Result) (Status, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, ok := s.histories[historyKey{orgID: ds.OrgID, uid: ds.UID}]
+	if !ok {
+		// Data source was deleted while being checked.
+		return "", false
+	}
+	previous, hasPrevious := h.latest()
+	h.add(result)
+	return previous.Status, hasPrevious
+}
+
+// GetHistory returns the health check history of the data source.
+func (s *Service) GetHistory(orgID int64, uid string) (*HistoryDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	h, ok := s.histories[historyKey{orgID: orgID, uid: uid}]
+	if !ok {
+		return nil, datasources.ErrDataSourceNotFound
+	}
+	return h.toDTO(uid), nil
+}
+
+// ListHistories returns the health check history of every data source of the
+// organization, sorted by data source UID.
+func (s *Service) ListHistories(orgID int64) []*HistoryDTO {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make([]*HistoryDTO, 0)
+	for key, h := range s.histories {
+		if key.orgID != orgID {
+			continue
+		}
+		res = append(res, h.toDTO(key.uid))
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].UID < res[j].UID
+	})
+	return res
+}
+
+func (h *history) toDTO(uid string) *HistoryDTO {
+	dto := &HistoryDTO{
+		UID:      uid,
+		Status:   StatusUnknown,
+		Interval: h.interval.String(),
+		Results:  h.list(),
+	}
+	if len(dto.Results) > 0 {
+		dto.Status = dto.Results[0].Status
+		dto.LastChecked = &dto.Results[0].Timestamp
+	}
+	return dto
+}
+
+func (h *history) add(r Result) {
+	h.results[h.next] = r
+	h.next = (h.next + 1) % len(h.results)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+func (h *history) latest() (Result, bool) {
+	if !h.full && h.next == 0 {
+		return Result{}, false
+	}
+	return h.results[(h.next-1+len(h.results))%len(h.results)], true
+}
+
+// list returns the results from the newest to the oldest one.
+func (h *history) list() []Result {
+	n := h.next
+	if h.full {
+		n = len(h.results)
+	}
+	res := make([]Result, 0, n)
+	for i := 1; i <= n; i++ {
+		res = append(res, h.results[(h.next-i+len(h.results))%len(h.results)])
+	}
+	return res
+}
@@ -0,0 +1,142 @@
+package healthcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestHistory(t *testing.T) {
+	h := &history{results: make([]Result, 3)}
+
+	_, ok := h.latest()
+	require.False(t, ok)
+	require.Empty(t, h.list())
+
+	for i := 0; i < 4; i++ {
+		h.add(Result{Message: string(rune('a' + i))})
+	}
+
+	latest, ok := h.latest()
+	require.True(t, ok)
+	require.Equal(t, "d", latest.Message)
+
+	list := h.list()
+	require.Len(t, list, 3)
+	require.Equal(t, "d", list[0].Message)
+	require.Equal(t, "c", list[1].Message)
+	require.Equal(t, "b", list[2].Message)
+}
+
+func TestInterval(t *testing.T) {
+	s := &Service{
+		cfg: setting.DataSourceHealthCheckSettings{
+			DefaultInterval: 5 * time.Minute,
+			MinInterval:     30 * time.Second,
+		},
+		log: log.NewNopLogger(),
+	}
+
+	tests := []struct {
+		name     string
+		jsonData *simplejson.Json
+		expected time.Duration
+	}{
+		{name: "no json data", expected: 5 * time.Minute},
+		{name: "no interval", jsonData: simplejson.New(), expected: 5 * time.Minute},
+		{name: "custom interval", jsonData: simplejson.NewFromAny(map[string]any{JSONDataIntervalKey: "1m"}), expected: time.Minute},
+		{name: "below minimum", jsonData: simplejson.NewFromAny(map[string]any{JSONDataIntervalKey: "1s"}), expected: 30 * time.Second},
+		{name: "disabled", jsonData: simplejson.NewFromAny(map[string]any{JSONDataIntervalKey: "0"}), expected: 0},
+		{name: "invalid", jsonData: simplejson.NewFromAny(map[string]any{JSONDataIntervalKey: "often"}), expected: 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, s.interval(&datasources.DataSource{JsonData: tt.jsonData}))
+		})
+	}
+}
+
+func TestListHistories(t *testing.T) {
+	s := &Service{histories: map[historyKey]*history{}}
+	for _, key := range []historyKey{{orgID: 1, uid: "b"}, {orgID: 1, uid: "a"}, {orgID: 2, uid: "c"}} {
+		s.histories[key] = &history{interval: time.Minute, results: make([]Result, 2)}
+	}
+	s.histories[historyKey{orgID: 1, uid: "b"}].add(Result{Status: StatusError})
+
+	list := s.ListHistories(1)
+	require.Len(t, list, 2)
+	require.Equal(t, "a", list[0].UID)
+	require.Equal(t, StatusUnknown, list[0].Status)
+	require.Equal(t, "b", list[1].UID)
+	require.Equal(t, StatusError, list[1].Status)
+	require.NotNil(t, list[1].LastChecked)
+
+	require.Empty(t, s.ListHistories(3))
+}
+
+func TestCheckDueSkipsUnsupported(t *testing.T) {
+	key := historyKey{orgID: 1, uid: "a"}
+	s := &Service{
+		cfg: setting.DataSourceHealthCheckSettings{
+			DefaultInterval:     time.Minute,
+			MaxConcurrentChecks: 1,
+		},
+		dataSourceService: &fakes.FakeDataSourceService{
+			DataSources: []*datasources.DataSource{{OrgID: 1, UID: "a", Type: "frontend-only"}},
+		},
+		log: log.NewNopLogger(),
+		histories: map[historyKey]*history{key: {
+			interval:         time.Minute,
+			dsType:           "frontend-only",
+			unsupportedUntil: time.Now().Add(time.Hour),
+			results:          make([]Result, 2),
+		}},
+	}
+
+	// A check would need a plugin context provider, which the service doesn't have.
+	s.checkDue(context.Background())
+
+	h := s.histories[key]
+	require.False(t, h.unsupportedUntil.IsZero())
+	require.True(t, h.nextCheck.IsZero())
+	require.Empty(t, h.list())
+}
+
+func TestHistoryDue(t *testing.T) {
+	now := time.Now()
+	ds := &datasources.DataSource{OrgID: 1, UID: "a", Type: "frontend-only", Updated: now.Add(-time.Hour)}
+
+	h := &history{results: make([]Result, 2)}
+	require.True(t, h.due(ds, time.Minute, now))
+	require.False(t, h.due(ds, time.Minute, now.Add(30*time.Second)))
+	require.True(t, h.due(ds, time.Minute, now.Add(time.Minute)))
+
+	t.Run("unsupported data sources are checked again after a while", func(t *testing.T) {
+		h := &history{results: make([]Result, 2)}
+		require.True(t, h.due(ds, time.Minute, now))
+		h.unsupportedUntil = now.Add(unsupportedRecheckInterval)
+
+		require.False(t, h.due(ds, time.Minute, now.Add(time.Minute)))
+		require.True(t, h.due(ds, time.Minute, now.Add(unsupportedRecheckInterval)))
+	})
+
+	t.Run("unsupported data sources are checked again when they change", func(t *testing.T) {
+		h := &history{results: make([]Result, 2)}
+		require.True(t, h.due(ds, time.Minute, now))
+		h.unsupportedUntil = now.Add(unsupportedRecheckInterval)
+
+		updated := *ds
+		updated.Type = "prometheus"
+		updated.Updated = now
+		require.True(t, h.due(&updated, time.Minute, now.Add(time.Second)))
+		require.True(t, h.unsupportedUntil.IsZero())
+	})
+}
@@ -0,0 +1,58 @@
+package healthcheck
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "grafana"
+	subsystem = "datasource_health_check"
+)
+
+type metrics struct {
+	status   *prometheus.GaugeVec
+	duration *prometheus.HistogramVec
+	checks   *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		status: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "status",
+			Help:      "Result of the latest data source health check, 1 if healthy and 0 otherwise",
+		}, []string{"org_id", "datasource_uid", "datasource_type"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "duration_seconds",
+			Help:      "Duration of data source health checks",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"datasource_type", "status"}),
+		checks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "total",
+			Help:      "Total number of data source health checks",
+		}, []string{"datasource_type", "status"}),
+	}
+}
+
+func (m *metrics) register(prom prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{m.status, m.duration, m.checks} {
+		err := prom.Register(c)
+		var alreadyRegisterErr prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegisterErr) {
+			if alreadyRegisterErr.ExistingCollector == alreadyRegisterErr.NewCollector {
+				err = nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
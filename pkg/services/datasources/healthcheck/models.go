@@ -0,0 +1,36 @@
+package healthcheck
+
+import (
+	"time"
+)
+
+// JSONDataIntervalKey is the data source JSON data field used to override the
+// default health check interval, ex. "1m". Setting it to "0" disables the checks
+// for the data source.
+const JSONDataIntervalKey = "healthCheckInterval"
+
+type Status string
+
+const (
+	StatusOK      Status = "OK"
+	StatusError   Status = "ERROR"
+	StatusUnknown Status = "UNKNOWN"
+)
+
+// Result is the outcome of a single health check.
+type Result struct {
+	Status    Status        `json:"status"`
+	Message   string        `json:"message,omitempty"`
+	Timestamp time.Time     `json:"timestamp"`
+	Latency   time.Duration `json:"latency"`
+}
+
+// HistoryDTO describes the current state and latest results of a data source.
+type HistoryDTO struct {
+	UID         string     `json:"uid"`
+	Status      Status     `json:"status"`
+	Interval    string     `json:"interval"`
+	LastChecked *time.Time `json:"lastChecked,omitempty"`
+	// Results are ordered from the newest to the oldest one.
+	Results []Result `json:"results"`
+}
@@ -194,10 +194,13 @@ type UpdateDataSourceCommand struct {
 
 	OrgID                   int64             `json:"-"`
 	ID                      int64             `json:"-"`
+	UserID                  int64             `json:"-"`
 	ReadOnly                bool              `json:"-"`
 	EncryptedSecureJsonData map[string][]byte `json:"-"`
 	UpdateSecretFn          UpdateSecretFn    `json:"-"`
 	IgnoreOldSecureJsonData bool              `json:"-"`
+	// RestoredFrom is the version the data source is restored from, if any.
+	RestoredFrom int `json:"-"`
 }
 
 // DeleteDataSourceCommand will delete a DataSource based on OrgID as well as the UID (preferred), ID, or Name.
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,6 +15,7 @@ import (
 	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
 	sdkproxy "github.com/grafana/grafana-plugin-sdk-go/backend/proxy"
 
+	"github.com/grafana/grafana/pkg/api/datasource"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
@@ -235,6 +237,11 @@ func (s *Service) AddDataSource(ctx context.Context, cmd *datasources.AddDataSou
 			return err
 		}
 
+		secureJsonFields := secureJSONFieldNames(cmd.SecureJsonData)
+		if err := s.addVersion(ctx, dataSource, cmd.UserID, 0, secureJsonFields, secureJsonFields); err != nil {
+			return err
+		}
+
 		// This belongs in Data source permissions, and we probably want
 		// to do this with a hook in the store and rollback on fail.
 		// We can't use events, because there's no way to communicate
@@ -318,7 +325,28 @@ func (s *Service) UpdateDataSource(ctx context.Context, cmd *datasources.UpdateD
 			}
 		}
 
-		err = s.fillWithSecureJSONData(ctx, cmd, dataSource)
+		decrypted, err := s.DecryptedValues(ctx, dataSource)
+		if err != nil {
+			return err
+		}
+
+		// Make sure the state before the update is part of the history, data
+		// sources created before versioning was introduced have no versions.
+		if _, err := s.SQLStore.GetDataSourceVersion(ctx, &datasources.GetDataSourceVersionQuery{
+			OrgID:         dataSource.OrgID,
+			DataSourceUID: dataSource.UID,
+			Version:       dataSource.Version,
+		}); err != nil {
+			if !errors.Is(err, datasources.ErrDataSourceVersionNotFound) {
+				return err
+			}
+			if err := s.addVersion(ctx, dataSource, 0, 0, secureJSONFieldNames(decrypted), nil); err != nil {
+				return err
+			}
+		}
+
+		secureJsonChanged := changedSecureJSONFields(decrypted, cmd)
+		err = s.fillWithSecureJSONData(ctx, cmd, decrypted)
 		if err != nil {
 			return err
 		}
@@ -341,11 +369,122 @@ func (s *Service) UpdateDataSource(ctx context.Context, cmd *datasources.UpdateD
 			}
 		}
 
+		previousUID := dataSource.UID
 		dataSource, err = s.SQLStore.UpdateDataSource(ctx, cmd)
-		return err
+		if err != nil {
+			return err
+		}
+
+		// The UID is kept when the command doesn't set it.
+		versioned := *dataSource
+		if versioned.UID == "" {
+			versioned.UID = previousUID
+		}
+		return s.addVersion(ctx, &versioned, cmd.UserID, cmd.RestoredFrom, secureJSONFieldNames(cmd.SecureJsonData), secureJsonChanged)
+	})
+}
+
+func (s *Service) addVersion(ctx context.Context, ds *datasources.DataSource, userID int64, restoredFrom int, secureJsonFields, secureJsonChanged []string) error {
+	sort.Strings(secureJsonChanged)
+	return s.SQLStore.AddDataSourceVersion(ctx, s.cfg.DataSourceVersionsToKeep, &datasources.DataSourceVersion{
+		OrgID:             ds.OrgID,
+		DataSourceID:      ds.ID,
+		DataSourceUID:     ds.UID,
+		Version:           ds.Version,
+		RestoredFrom:      restoredFrom,
+		Created:           time.Now(),
+		CreatedBy:         userID,
+		Data:              datasources.NewDataSourceVersionData(ds, secureJsonFields),
+		SecureJsonChanged: secureJsonChanged,
 	})
 }
 
+func (s *Service) GetDataSourceVersion(ctx context.Context, query *datasources.GetDataSourceVersionQuery) (*datasources.DataSourceVersion, error) {
+	return s.SQLStore.GetDataSourceVersion(ctx, query)
+}
+
+func (s *Service) ListDataSourceVersions(ctx context.Context, query *datasources.ListDataSourceVersionsQuery) ([]*datasources.DataSourceVersion, error) {
+	return s.SQLStore.ListDataSourceVersions(ctx, query)
+}
+
+func (s *Service) RestoreDataSourceVersion(ctx context.Context, cmd *datasources.RestoreDataSourceVersionCommand) (*datasources.DataSource, error) {
+	ds, err := s.SQLStore.GetDataSource(ctx, &datasources.GetDataSourceQuery{OrgID: cmd.OrgID, UID: cmd.DataSourceUID})
+	if err != nil {
+		return nil, err
+	}
+	if ds.ReadOnly {
+		return nil, datasources.ErrDatasourceIsReadOnly
+	}
+
+	version, err := s.SQLStore.GetDataSourceVersion(ctx, &datasources.GetDataSourceVersionQuery{
+		OrgID:         cmd.OrgID,
+		DataSourceUID: cmd.DataSourceUID,
+		Version:       cmd.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := version.Data
+	if data == nil {
+		return nil, datasources.ErrDataSourceVersionNotFound
+	}
+	// the URL was valid when the version was saved, but the validation may have changed since
+	if _, err := datasource.ValidateURL(data.Type, data.URL); err != nil {
+		return nil, datasources.ErrDataSourceURLInvalid.Errorf("%w", err)
+	}
+	return s.UpdateDataSource(ctx, &datasources.UpdateDataSourceCommand{
+		ID:              ds.ID,
+		UID:             ds.UID,
+		OrgID:           ds.OrgID,
+		Version:         ds.Version,
+		UserID:          cmd.UserID,
+		RestoredFrom:    version.Version,
+		Name:            data.Name,
+		Type:            data.Type,
+		Access:          data.Access,
+		URL:             data.URL,
+		User:            data.User,
+		Database:        data.Database,
+		BasicAuth:       data.BasicAuth,
+		BasicAuthUser:   data.BasicAuthUser,
+		WithCredentials: data.WithCredentials,
+		IsDefault:       data.IsDefault,
+		JsonData:        data.JsonData,
+		IsPrunable:      ds.IsPrunable,
+	})
+}
+
+// secureJSONFieldNames returns the names of the secure fields with a value.
+func secureJSONFieldNames(secureJsonData map[string]string) []string {
+	names := make([]string, 0, len(secureJsonData))
+	for k, v := range secureJsonData {
+		if v != "" {
+			names = append(names, k)
+		}
+	}
+	return names
+}
+
+// changedSecureJSONFields returns the names of the secure fields the update command
+// sets to a new value or, when old values are ignored, removes.
+func changedSecureJSONFields(decrypted map[string]string, cmd *datasources.UpdateDataSourceCommand) []string {
+	var changed []string
+	for k, v := range cmd.SecureJsonData {
+		if old, ok := decrypted[k]; !ok || old != v {
+			changed = append(changed, k)
+		}
+	}
+	if cmd.IgnoreOldSecureJsonData {
+		for k := range decrypted {
+			if _, ok := cmd.SecureJsonData[k]; !ok {
+				changed = append(changed, k)
+			}
+		}
+	}
+	return changed
+}
+
 func (s *Service) GetHTTPTransport(ctx context.Context, ds *datasources.DataSource, provider httpclient.Provider,
 	customMiddlewares ...sdkhttpclient.Middleware) (http.RoundTripper, error) {
 	s.ptc.Lock()
@@ -465,9 +604,10 @@ func (s *Service) httpClientOptions(ctx context.Context, ds *datasources.DataSou
 		Timeouts: timeouts,
 		Header:   s.getCustomHeaders(ds.JsonData, decryptedValues),
 		Labels: map[string]string{
-			"datasource_type": ds.Type,
-			"datasource_name": ds.Name,
-			"datasource_uid":  ds.UID,
+			"datasource_type":   ds.Type,
+			"datasource_name":   ds.Name,
+			"datasource_uid":    ds.UID,
+			"datasource_org_id": strconv.FormatInt(ds.OrgID, 10),
 		},
 		TLS: &tlsOptions,
 	}
@@ -688,12 +828,8 @@ func awsServiceNamespace(dsType string, jsonData *simplejson.Json) string {
 	}
 }
 
-func (s *Service) fillWithSecureJSONData(ctx context.Context, cmd *datasources.UpdateDataSourceCommand, ds *datasources.DataSource) error {
-	decrypted, err := s.DecryptedValues(ctx, ds)
-	if err != nil {
-		return err
-	}
-
+func (s *Service) fillWithSecureJSONData(ctx context.Context, cmd *datasources.UpdateDataSourceCommand, decrypted map[string]string) error {
+	var err error
 	if cmd.SecureJsonData == nil {
 		cmd.SecureJsonData = make(map[string]string)
 	}
@@ -362,6 +362,111 @@ func TestService_UpdateDataSource(t *testing.T) {
 		_, ok := secret[notExpectedDbKey]
 		assert.False(t, ok)
 	})
+
+	t.Run("should record versions and restore a previous one", func(t *testing.T) {
+		sqlStore := db.InitTestDB(t)
+		secretsService := secretsmng.SetupTestService(t, fakes.NewFakeSecretsStore())
+		secretsStore := secretskvs.NewSQLSecretsKVStore(sqlStore, secretsService, log.New("test.logger"))
+		quotaService := quotatest.New(false, nil)
+		mockPermission := acmock.NewMockedPermissionsService()
+		dsService, err := ProvideService(sqlStore, secretsService, secretsStore, cfg, featuremgmt.WithFeatures(), actest.FakeAccessControl{}, mockPermission, quotaService, &pluginstore.FakePluginStore{})
+		require.NoError(t, err)
+
+		mockPermission.On("SetPermissions", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]accesscontrol.ResourcePermission{}, nil)
+
+		ds, err := dsService.AddDataSource(context.Background(), &datasources.AddDataSourceCommand{
+			OrgID:          1,
+			Name:           "test-datasource",
+			URL:            "http://localhost:9090",
+			UserID:         2,
+			SecureJsonData: map[string]string{"password": "secret"},
+		})
+		require.NoError(t, err)
+
+		_, err = dsService.UpdateDataSource(context.Background(), &datasources.UpdateDataSourceCommand{
+			ID:             ds.ID,
+			OrgID:          ds.OrgID,
+			Name:           ds.Name,
+			URL:            "http://localhost:9091",
+			UserID:         3,
+			SecureJsonData: map[string]string{"password": "new-secret"},
+		})
+		require.NoError(t, err)
+
+		versions, err := dsService.ListDataSourceVersions(context.Background(), &datasources.ListDataSourceVersionsQuery{
+			OrgID:         ds.OrgID,
+			DataSourceUID: ds.UID,
+		})
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.Equal(t, 2, versions[0].Version)
+		require.Equal(t, int64(3), versions[0].CreatedBy)
+		require.Equal(t, "http://localhost:9091", versions[0].Data.URL)
+		require.Equal(t, []string{"password"}, versions[0].SecureJsonChanged)
+		require.Equal(t, []string{"password"}, versions[0].Data.SecureJsonFields)
+		require.Equal(t, 1, versions[1].Version)
+		require.Equal(t, "http://localhost:9090", versions[1].Data.URL)
+
+		restored, err := dsService.RestoreDataSourceVersion(context.Background(), &datasources.RestoreDataSourceVersionCommand{
+			OrgID:         ds.OrgID,
+			DataSourceUID: ds.UID,
+			Version:       1,
+			UserID:        3,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "http://localhost:9090", restored.URL)
+
+		version, err := dsService.GetDataSourceVersion(context.Background(), &datasources.GetDataSourceVersionQuery{
+			OrgID:         ds.OrgID,
+			DataSourceUID: ds.UID,
+			Version:       3,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, version.RestoredFrom)
+		require.Empty(t, version.SecureJsonChanged)
+
+		secret, err := dsService.DecryptedValues(context.Background(), restored)
+		require.NoError(t, err)
+		require.Equal(t, "new-secret", secret["password"])
+	})
+
+	t.Run("should not restore a version with an invalid URL", func(t *testing.T) {
+		sqlStore := db.InitTestDB(t)
+		secretsService := secretsmng.SetupTestService(t, fakes.NewFakeSecretsStore())
+		secretsStore := secretskvs.NewSQLSecretsKVStore(sqlStore, secretsService, log.New("test.logger"))
+		quotaService := quotatest.New(false, nil)
+		mockPermission := acmock.NewMockedPermissionsService()
+		dsService, err := ProvideService(sqlStore, secretsService, secretsStore, cfg, featuremgmt.WithFeatures(), actest.FakeAccessControl{}, mockPermission, quotaService, &pluginstore.FakePluginStore{})
+		require.NoError(t, err)
+
+		mockPermission.On("SetPermissions", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]accesscontrol.ResourcePermission{}, nil)
+
+		// the service doesn't validate URLs when adding and updating data sources, the API does
+		ds, err := dsService.AddDataSource(context.Background(), &datasources.AddDataSourceCommand{
+			OrgID:  1,
+			Name:   "test-datasource",
+			URL:    "http://localhost:%zz",
+			UserID: 2,
+		})
+		require.NoError(t, err)
+
+		_, err = dsService.UpdateDataSource(context.Background(), &datasources.UpdateDataSourceCommand{
+			ID:     ds.ID,
+			OrgID:  ds.OrgID,
+			Name:   ds.Name,
+			URL:    "http://localhost:9090",
+			UserID: 3,
+		})
+		require.NoError(t, err)
+
+		_, err = dsService.RestoreDataSourceVersion(context.Background(), &datasources.RestoreDataSourceVersionCommand{
+			OrgID:         ds.OrgID,
+			DataSourceUID: ds.UID,
+			Version:       1,
+			UserID:        3,
+		})
+		require.ErrorIs(t, err, datasources.ErrDataSourceURLInvalid)
+	})
 }
 
 func TestService_NameScopeResolver(t *testing.T) {
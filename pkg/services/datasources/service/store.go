@@ -31,6 +31,9 @@ type Store interface {
 	UpdateDataSource(context.Context, *datasources.UpdateDataSourceCommand) (*datasources.DataSource, error)
 	GetAllDataSources(ctx context.Context, query *datasources.GetAllDataSourcesQuery) (res []*datasources.DataSource, err error)
 	GetPrunableProvisionedDataSources(ctx context.Context) (res []*datasources.DataSource, err error)
+	AddDataSourceVersion(ctx context.Context, versionsToKeep int, version *datasources.DataSourceVersion) error
+	GetDataSourceVersion(context.Context, *datasources.GetDataSourceVersionQuery) (*datasources.DataSourceVersion, error)
+	ListDataSourceVersions(context.Context, *datasources.ListDataSourceVersionsQuery) ([]*datasources.DataSourceVersion, error)
 
 	Count(context.Context, *quota.ScopeParameters) (*quota.Map, error)
 }
@@ -155,6 +158,11 @@ func (ss *SqlStore) DeleteDataSource(ctx context.Context, cmd *datasources.Delet
 
 			cmd.DeletedDatasourcesCount, _ = result.RowsAffected()
 
+			// Remove the version history
+			if _, err := sess.Exec("DELETE FROM data_source_version WHERE data_source_id=?", ds.ID); err != nil {
+				return err
+			}
+
 			// Remove associated AccessControl permissions
 			if _, errDeletingPerms := sess.Exec("DELETE FROM permission WHERE scope=?",
 				ac.Scope(datasources.ScopeProvider.GetResourceScope(ds.UID))); errDeletingPerms != nil {
@@ -321,6 +329,17 @@ func (ss *SqlStore) UpdateDataSource(ctx context.Context, cmd *datasources.Updat
 			cmd.JsonData = simplejson.New()
 		}
 
+		// Updates without version are not checked against the stored version, but
+		// still increase it so that every update has its own entry in the history.
+		version := cmd.Version
+		if version == 0 {
+			var current datasources.DataSource
+			if _, err := sess.Where("id=? AND org_id=?", cmd.ID, cmd.OrgID).Cols("version").Get(&current); err != nil {
+				return err
+			}
+			version = current.Version
+		}
+
 		ds = &datasources.DataSource{
 			ID:              cmd.ID,
 			OrgID:           cmd.OrgID,
@@ -338,7 +357,7 @@ func (ss *SqlStore) UpdateDataSource(ctx context.Context, cmd *datasources.Updat
 			SecureJsonData:  cmd.EncryptedSecureJsonData,
 			Updated:         time.Now(),
 			ReadOnly:        cmd.ReadOnly,
-			Version:         cmd.Version + 1,
+			Version:         version + 1,
 			UID:             cmd.UID,
 			IsPrunable:      cmd.IsPrunable,
 		}
@@ -397,6 +416,70 @@ func (ss *SqlStore) UpdateDataSource(ctx context.Context, cmd *datasources.Updat
 	})
 }
 
+// AddDataSourceVersion stores the version and deletes the oldest versions of the data
+// source beyond versionsToKeep, a value below 1 keeps all of them.
+func (ss *SqlStore) AddDataSourceVersion(ctx context.Context, versionsToKeep int, version *datasources.DataSourceVersion) error {
+	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(version); err != nil {
+			return err
+		}
+		if versionsToKeep < 1 {
+			return nil
+		}
+
+		var ids []int64
+		if err := sess.Table("data_source_version").Cols("id").
+			Where("data_source_id=?", version.DataSourceID).
+			Desc("version").
+			Find(&ids); err != nil {
+			return err
+		}
+		if len(ids) <= versionsToKeep {
+			return nil
+		}
+
+		expired := ids[versionsToKeep:]
+		args := make([]any, 0, len(expired)+1)
+		args = append(args, "DELETE FROM data_source_version WHERE id IN (?"+strings.Repeat(",?", len(expired)-1)+")")
+		for _, id := range expired {
+			args = append(args, id)
+		}
+		_, err := sess.Exec(args...)
+		return err
+	})
+}
+
+func (ss *SqlStore) GetDataSourceVersion(ctx context.Context, query *datasources.GetDataSourceVersionQuery) (*datasources.DataSourceVersion, error) {
+	var version datasources.DataSourceVersion
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id=? AND data_source_uid=? AND version=?", query.OrgID, query.DataSourceUID, query.Version).Get(&version)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return datasources.ErrDataSourceVersionNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// ListDataSourceVersions returns the versions of a data source ordered from the newest to the oldest one.
+func (ss *SqlStore) ListDataSourceVersions(ctx context.Context, query *datasources.ListDataSourceVersionsQuery) ([]*datasources.DataSourceVersion, error) {
+	versions := make([]*datasources.DataSourceVersion, 0)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("org_id=? AND data_source_uid=?", query.OrgID, query.DataSourceUID).Desc("version")
+		if query.Limit > 0 {
+			sess.Limit(query.Limit, query.Start)
+		}
+		return sess.Find(&versions)
+	})
+	return versions, err
+}
+
 func generateNewDatasourceUid(sess *db.Session, orgId int64) (string, error) {
 	for i := 0; i < 3; i++ {
 		uid := generateNewUid()
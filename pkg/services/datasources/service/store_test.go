@@ -195,6 +195,27 @@ func TestIntegrationDataAccess(t *testing.T) {
 			require.NoError(t, err)
 		})
 
+		t.Run("increases the version of updates without version", func(t *testing.T) {
+			db := db.InitTestDB(t)
+			ds := initDatasource(db)
+			ss := SqlStore{db: db}
+
+			for i := 0; i < 2; i++ {
+				cmd := defaultUpdateDatasourceCommand
+				cmd.ID = ds.ID
+				updated, err := ss.UpdateDataSource(context.Background(), &cmd)
+				require.NoError(t, err)
+				require.Equal(t, ds.Version+i+1, updated.Version)
+			}
+
+			// The version check still applies to updates with version
+			cmd := defaultUpdateDatasourceCommand
+			cmd.ID = ds.ID
+			cmd.Version = ds.Version + 1
+			_, err := ss.UpdateDataSource(context.Background(), &cmd)
+			require.ErrorIs(t, err, datasources.ErrDataSourceUpdatingOldVersion)
+		})
+
 		t.Run("updates ds without higher version", func(t *testing.T) {
 			db := db.InitTestDB(t)
 			ds := initDatasource(db)
@@ -215,6 +236,35 @@ func TestIntegrationDataAccess(t *testing.T) {
 		})
 	})
 
+	t.Run("AddDataSourceVersion", func(t *testing.T) {
+		t.Run("deletes the oldest versions", func(t *testing.T) {
+			db := db.InitTestDB(t)
+			ds := initDatasource(db)
+			ss := SqlStore{db: db}
+
+			for v := 1; v <= 4; v++ {
+				err := ss.AddDataSourceVersion(context.Background(), 2, &datasources.DataSourceVersion{
+					OrgID:         ds.OrgID,
+					DataSourceID:  ds.ID,
+					DataSourceUID: ds.UID,
+					Version:       v,
+					Created:       time.Now(),
+					Data:          datasources.NewDataSourceVersionData(ds, nil),
+				})
+				require.NoError(t, err)
+			}
+
+			versions, err := ss.ListDataSourceVersions(context.Background(), &datasources.ListDataSourceVersionsQuery{
+				OrgID:         ds.OrgID,
+				DataSourceUID: ds.UID,
+			})
+			require.NoError(t, err)
+			require.Len(t, versions, 2)
+			require.Equal(t, 4, versions[0].Version)
+			require.Equal(t, 3, versions[1].Version)
+		})
+	})
+
 	t.Run("DeleteDataSourceById", func(t *testing.T) {
 		t.Run("can delete datasource", func(t *testing.T) {
 			db := db.InitTestDB(t)
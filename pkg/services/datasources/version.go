@@ -0,0 +1,198 @@
+package datasources
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// DataSourceVersion is a snapshot of a data source configuration stored every
+// time the data source is created or updated. Secure JSON data is never stored,
+// only the names of the configured secure fields and the ones that changed.
+type DataSourceVersion struct {
+	ID            int64  `json:"id" xorm:"pk autoincr 'id'"`
+	OrgID         int64  `json:"orgId" xorm:"org_id"`
+	DataSourceID  int64  `json:"dataSourceId" xorm:"data_source_id"`
+	DataSourceUID string `json:"dataSourceUid" xorm:"data_source_uid"`
+	Version       int    `json:"version"`
+	RestoredFrom  int    `json:"restoredFrom"`
+
+	Created   time.Time `json:"created"`
+	CreatedBy int64     `json:"createdBy"`
+
+	Data              *DataSourceVersionData `json:"data" xorm:"data"`
+	SecureJsonChanged []string               `json:"secureJsonChanged" xorm:"secure_json_changed"`
+}
+
+// DataSourceVersionData is the part of the data source configuration tracked by versions.
+type DataSourceVersionData struct {
+	Name             string           `json:"name"`
+	Type             string           `json:"type"`
+	Access           DsAccess         `json:"access"`
+	URL              string           `json:"url"`
+	User             string           `json:"user"`
+	Database         string           `json:"database"`
+	BasicAuth        bool             `json:"basicAuth"`
+	BasicAuthUser    string           `json:"basicAuthUser"`
+	WithCredentials  bool             `json:"withCredentials"`
+	IsDefault        bool             `json:"isDefault"`
+	JsonData         *simplejson.Json `json:"jsonData"`
+	SecureJsonFields []string         `json:"secureJsonFields"`
+	ReadOnly         bool             `json:"readOnly"`
+}
+
+func (d *DataSourceVersionData) FromDB(data []byte) error {
+	return json.Unmarshal(data, d)
+}
+
+func (d *DataSourceVersionData) ToDB() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// NewDataSourceVersionData creates a snapshot of the data source configuration.
+// secureJsonFields are the names of the secure fields set for the data source.
+func NewDataSourceVersionData(ds *DataSource, secureJsonFields []string) *DataSourceVersionData {
+	fields := make([]string, len(secureJsonFields))
+	copy(fields, secureJsonFields)
+	sort.Strings(fields)
+
+	return &DataSourceVersionData{
+		Name:             ds.Name,
+		Type:             ds.Type,
+		Access:           ds.Access,
+		URL:              ds.URL,
+		User:             ds.User,
+		Database:         ds.Database,
+		BasicAuth:        ds.BasicAuth,
+		BasicAuthUser:    ds.BasicAuthUser,
+		WithCredentials:  ds.WithCredentials,
+		IsDefault:        ds.IsDefault,
+		JsonData:         ds.JsonData,
+		SecureJsonFields: fields,
+		ReadOnly:         ds.ReadOnly,
+	}
+}
+
+// DataSourceVersionMeta extends the DataSourceVersion with the login of the user
+// who created it, overriding the field with the same name.
+type DataSourceVersionMeta struct {
+	ID                int64                  `json:"id"`
+	DataSourceUID     string                 `json:"uid"`
+	Version           int                    `json:"version"`
+	RestoredFrom      int                    `json:"restoredFrom"`
+	Created           time.Time              `json:"created"`
+	CreatedBy         string                 `json:"createdBy"`
+	Message           string                 `json:"message"`
+	Data              *DataSourceVersionData `json:"data,omitempty"`
+	SecureJsonChanged []string               `json:"secureJsonChanged"`
+}
+
+// GetDataSourceVersionQuery is used to get a data source version.
+type GetDataSourceVersionQuery struct {
+	OrgID         int64
+	DataSourceUID string
+	Version       int
+}
+
+type ListDataSourceVersionsQuery struct {
+	OrgID         int64
+	DataSourceUID string
+	Limit         int
+	Start         int
+}
+
+// RestoreDataSourceVersionCommand restores the configuration of a previous data source
+// version. Secure JSON data is not versioned, so the current secrets are kept.
+type RestoreDataSourceVersionCommand struct {
+	OrgID         int64
+	DataSourceUID string
+	Version       int
+	UserID        int64
+}
+
+// DataSourceVersionChange describes a difference between two data source versions.
+// Path is a dot separated path of the changed field, ex. "jsonData.timeout".
+type DataSourceVersionChange struct {
+	Path     string `json:"path"`
+	OldValue any    `json:"oldValue,omitempty"`
+	NewValue any    `json:"newValue,omitempty"`
+}
+
+// DiffDataSourceVersions returns the changes between the base and the new version.
+// Secure values are not stored, so secureJsonChanged are the secure fields changed
+// by the versions between the two. They are only reported as changed, never with
+// their values.
+func DiffDataSourceVersions(base, new *DataSourceVersion, secureJsonChanged []string) ([]DataSourceVersionChange, error) {
+	baseFields, err := flattenVersionData(base.Data)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenVersionData(new.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]struct{}, len(baseFields)+len(newFields))
+	for p := range baseFields {
+		paths[p] = struct{}{}
+	}
+	for p := range newFields {
+		paths[p] = struct{}{}
+	}
+
+	changes := []DataSourceVersionChange{}
+	for p := range paths {
+		oldValue, newValue := baseFields[p], newFields[p]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, DataSourceVersionChange{Path: p, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+
+	for _, field := range secureJsonChanged {
+		p := "secureJsonData." + field
+		if _, ok := paths[p]; !ok {
+			paths[p] = struct{}{}
+			changes = append(changes, DataSourceVersionChange{Path: p})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+func flattenVersionData(data *DataSourceVersionData) (map[string]any, error) {
+	res := map[string]any{}
+	if data == nil {
+		return res, nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	flatten("", m, res)
+	return res, nil
+}
+
+func flatten(prefix string, value any, res map[string]any) {
+	m, ok := value.(map[string]any)
+	if !ok {
+		res[prefix] = value
+		return
+	}
+	for k, v := range m {
+		p := k
+		if prefix != "" {
+			p = prefix + "." + k
+		}
+		flatten(p, v, res)
+	}
+}
@@ -0,0 +1,36 @@
+package datasources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestDiffDataSourceVersions(t *testing.T) {
+	base := &DataSourceVersion{
+		Version: 1,
+		Data: NewDataSourceVersionData(&DataSource{
+			Name:     "prometheus",
+			URL:      "http://localhost:9090",
+			JsonData: simplejson.NewFromAny(map[string]any{"timeout": "30", "httpMethod": "POST"}),
+		}, []string{"password"}),
+	}
+	target := &DataSourceVersion{
+		Version: 2,
+		Data: NewDataSourceVersionData(&DataSource{
+			Name:     "prometheus",
+			URL:      "http://localhost:9091",
+			JsonData: simplejson.NewFromAny(map[string]any{"timeout": "60", "httpMethod": "POST"}),
+		}, []string{"password"}),
+	}
+
+	changes, err := DiffDataSourceVersions(base, target, []string{"password"})
+	require.NoError(t, err)
+	require.Equal(t, []DataSourceVersionChange{
+		{Path: "jsonData.timeout", OldValue: "30", NewValue: "60"},
+		{Path: "secureJsonData.password"},
+		{Path: "url", OldValue: "http://localhost:9090", NewValue: "http://localhost:9091"},
+	}, changes)
+}
@@ -50,11 +50,47 @@ type KeepFieldsFrameProcessorConfig struct {
 	FieldNames []string `json:"fieldNames"`
 }
 
+type RenameFieldsFrameProcessorConfig struct {
+	// Names maps current field names to new field names.
+	Names map[string]string `json:"names"`
+}
+
+type ComputedFieldFrameProcessorConfig struct {
+	FieldName  string `json:"fieldName"`
+	Expression string `json:"expression"`
+	Unit       string `json:"unit,omitempty"`
+}
+
+type ConvertUnitFrameProcessorConfig struct {
+	FieldNames []string `json:"fieldNames"`
+	// Factor to multiply values by, 1 if not set.
+	Factor float64 `json:"factor,omitempty"`
+	// Offset added to values after multiplying by Factor.
+	Offset float64 `json:"offset,omitempty"`
+	// Unit to set in the field config of converted fields.
+	Unit string `json:"unit,omitempty"`
+}
+
+type WindowAggregateFrameProcessorConfig struct {
+	// IntervalMs is a tumbling window size in milliseconds.
+	IntervalMs int64 `json:"intervalMs"`
+	// TimeFieldName is used to find the time field, first time field is used if not set.
+	TimeFieldName string `json:"timeFieldName,omitempty"`
+	// FieldNames to aggregate, all numeric fields are aggregated if empty.
+	FieldNames []string `json:"fieldNames,omitempty"`
+	// Aggregations to calculate for each field: avg, min, max, sum, count, last. Defaults to avg.
+	Aggregations []string `json:"aggregations,omitempty"`
+}
+
 type FrameProcessorConfig struct {
-	Type                      string                          `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
-	DropFieldsProcessorConfig *DropFieldsFrameProcessorConfig `json:"dropFields,omitempty"`
-	KeepFieldsProcessorConfig *KeepFieldsFrameProcessorConfig `json:"keepFields,omitempty"`
-	MultipleProcessorConfig   *MultipleFrameProcessorConfig   `json:"multiple,omitempty"`
+	Type                           string                               `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
+	DropFieldsProcessorConfig      *DropFieldsFrameProcessorConfig      `json:"dropFields,omitempty"`
+	KeepFieldsProcessorConfig      *KeepFieldsFrameProcessorConfig      `json:"keepFields,omitempty"`
+	RenameFieldsProcessorConfig    *RenameFieldsFrameProcessorConfig    `json:"renameFields,omitempty"`
+	ComputedFieldProcessorConfig   *ComputedFieldFrameProcessorConfig   `json:"computedField,omitempty"`
+	ConvertUnitProcessorConfig     *ConvertUnitFrameProcessorConfig     `json:"convertUnit,omitempty"`
+	WindowAggregateProcessorConfig *WindowAggregateFrameProcessorConfig `json:"windowAggregate,omitempty"`
+	MultipleProcessorConfig        *MultipleFrameProcessorConfig        `json:"multiple,omitempty"`
 }
 
 type MultipleFrameProcessorConfig struct {
@@ -0,0 +1,295 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ComputedFieldFrameProcessor can append a new numeric field to a data.Frame. Field
+// values are calculated row by row from an arithmetic expression which can reference
+// other numeric fields of the frame as $name or ${name with spaces}. Supported
+// operators are +, -, *, / and parentheses.
+type ComputedFieldFrameProcessor struct {
+	config ComputedFieldFrameProcessorConfig
+	expr   computedExpr
+}
+
+func NewComputedFieldFrameProcessor(config ComputedFieldFrameProcessorConfig) (*ComputedFieldFrameProcessor, error) {
+	expr, err := parseComputedExpr(config.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing expression for field %s: %w", config.FieldName, err)
+	}
+	return &ComputedFieldFrameProcessor{config: config, expr: expr}, nil
+}
+
+const FrameProcessorTypeComputedField = "computedField"
+
+func (p *ComputedFieldFrameProcessor) Type() string {
+	return FrameProcessorTypeComputedField
+}
+
+func (p *ComputedFieldFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	fieldsByName := make(map[string]*data.Field, len(frame.Fields))
+	for _, field := range frame.Fields {
+		fieldsByName[field.Name] = field
+	}
+
+	rowLen, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+
+	computed := data.NewFieldFromFieldType(data.FieldTypeNullableFloat64, rowLen)
+	computed.Name = p.config.FieldName
+	if p.config.Unit != "" {
+		computed.Config = &data.FieldConfig{Unit: p.config.Unit}
+	}
+
+	for i := 0; i < rowLen; i++ {
+		v, err := p.expr.eval(func(name string) (*float64, error) {
+			field, ok := fieldsByName[name]
+			if !ok {
+				return nil, fmt.Errorf("field %s not found", name)
+			}
+			if !field.Type().Numeric() {
+				return nil, fmt.Errorf("field %s is not numeric", name)
+			}
+			return field.NullableFloatAt(i)
+		})
+		if err != nil {
+			return nil, err
+		}
+		computed.Set(i, v)
+	}
+
+	for i, field := range frame.Fields {
+		if field.Name == computed.Name {
+			frame.Fields[i] = computed
+			return frame, nil
+		}
+	}
+	frame.Fields = append(frame.Fields, computed)
+	return frame, nil
+}
+
+type computedExpr interface {
+	// eval returns nil value when some of the referenced values is nil or
+	// when result can't be calculated (ex. division by zero).
+	eval(lookup func(name string) (*float64, error)) (*float64, error)
+}
+
+type computedNumber float64
+
+func (n computedNumber) eval(_ func(string) (*float64, error)) (*float64, error) {
+	v := float64(n)
+	return &v, nil
+}
+
+type computedFieldRef string
+
+func (r computedFieldRef) eval(lookup func(string) (*float64, error)) (*float64, error) {
+	return lookup(string(r))
+}
+
+type computedUnary struct {
+	arg computedExpr
+}
+
+func (u computedUnary) eval(lookup func(string) (*float64, error)) (*float64, error) {
+	v, err := u.arg.eval(lookup)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	res := -*v
+	return &res, nil
+}
+
+type computedBinary struct {
+	op          byte
+	left, right computedExpr
+}
+
+func (b computedBinary) eval(lookup func(string) (*float64, error)) (*float64, error) {
+	l, err := b.left.eval(lookup)
+	if err != nil {
+		return nil, err
+	}
+	r, err := b.right.eval(lookup)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil || r == nil {
+		return nil, nil
+	}
+	var res float64
+	switch b.op {
+	case '+':
+		res = *l + *r
+	case '-':
+		res = *l - *r
+	case '*':
+		res = *l * *r
+	case '/':
+		if *r == 0 {
+			return nil, nil
+		}
+		res = *l / *r
+	default:
+		return nil, fmt.Errorf("unknown operator %q", b.op)
+	}
+	return &res, nil
+}
+
+// computedExprParser is a simple recursive descent parser for arithmetic expressions:
+//
+//	expr   = term { ("+" | "-") term }
+//	term   = factor { ("*" | "/") factor }
+//	factor = number | "$" name | "${" name "}" | "-" factor | "(" expr ")"
+type computedExprParser struct {
+	input string
+	pos   int
+}
+
+func parseComputedExpr(input string) (computedExpr, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+	p := &computedExprParser{input: input}
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected character %q at position %d", p.input[p.pos], p.pos)
+	}
+	return expr, nil
+}
+
+func (p *computedExprParser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *computedExprParser) peek() byte {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *computedExprParser) parseExpr() (computedExpr, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = computedBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *computedExprParser) parseTerm() (computedExpr, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = computedBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *computedExprParser) parseFactor() (computedExpr, error) {
+	switch c := p.peek(); {
+	case c == 0:
+		return nil, fmt.Errorf("unexpected end of expression")
+	case c == '-':
+		p.pos++
+		arg, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return computedUnary{arg: arg}, nil
+	case c == '(':
+		p.pos++
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing closing parenthesis at position %d", p.pos)
+		}
+		p.pos++
+		return expr, nil
+	case c == '$':
+		p.pos++
+		return p.parseFieldRef()
+	case c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	default:
+		return nil, fmt.Errorf("unexpected character %q at position %d", c, p.pos)
+	}
+}
+
+func (p *computedExprParser) parseFieldRef() (computedExpr, error) {
+	if p.pos < len(p.input) && p.input[p.pos] == '{' {
+		end := strings.IndexByte(p.input[p.pos:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("missing closing brace at position %d", p.pos)
+		}
+		name := p.input[p.pos+1 : p.pos+end]
+		p.pos += end + 1
+		if name == "" {
+			return nil, fmt.Errorf("empty field name at position %d", p.pos)
+		}
+		return computedFieldRef(name), nil
+	}
+	start := p.pos
+	for p.pos < len(p.input) {
+		r := rune(p.input[p.pos])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' {
+			break
+		}
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, fmt.Errorf("empty field name at position %d", p.pos)
+	}
+	return computedFieldRef(p.input[start:p.pos]), nil
+}
+
+func (p *computedExprParser) parseNumber() (computedExpr, error) {
+	start := p.pos
+	for p.pos < len(p.input) && (p.input[p.pos] == '.' || (p.input[p.pos] >= '0' && p.input[p.pos] <= '9')) {
+		p.pos++
+	}
+	v, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number at position %d: %w", start, err)
+	}
+	return computedNumber(v), nil
+}
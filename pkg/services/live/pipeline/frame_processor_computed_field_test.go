@@ -0,0 +1,41 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputedFieldFrameProcessor(t *testing.T) {
+	p, err := NewComputedFieldFrameProcessor(ComputedFieldFrameProcessorConfig{
+		FieldName:  "power",
+		Expression: "${voltage V} * $current / 1000 + -(1 - 1)",
+		Unit:       "kwatt",
+	})
+	require.NoError(t, err)
+
+	frame := data.NewFrame("test",
+		data.NewField("voltage V", nil, []float64{220, 230}),
+		data.NewField("current", nil, []*float64{nil, pointer(10.0)}),
+	)
+
+	frame, err = p.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Len(t, frame.Fields, 3)
+	require.Nil(t, frame.Fields[2].At(0))
+	require.InDelta(t, 2.3, *frame.Fields[2].At(1).(*float64), 0.0001)
+	require.Equal(t, "kwatt", frame.Fields[2].Config.Unit)
+}
+
+func TestComputedFieldFrameProcessor_InvalidExpression(t *testing.T) {
+	for _, expr := range []string{"", "$a +", "($a * 2", "${a", "2 ^ 3"} {
+		_, err := NewComputedFieldFrameProcessor(ComputedFieldFrameProcessorConfig{FieldName: "x", Expression: expr})
+		require.Error(t, err, expr)
+	}
+}
+
+func pointer[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ConvertUnitFrameProcessor can convert values of numeric fields using a linear
+// transformation (value * factor + offset) and optionally set a new unit on them.
+type ConvertUnitFrameProcessor struct {
+	config ConvertUnitFrameProcessorConfig
+}
+
+func NewConvertUnitFrameProcessor(config ConvertUnitFrameProcessorConfig) *ConvertUnitFrameProcessor {
+	return &ConvertUnitFrameProcessor{config: config}
+}
+
+const FrameProcessorTypeConvertUnit = "convertUnit"
+
+func (p *ConvertUnitFrameProcessor) Type() string {
+	return FrameProcessorTypeConvertUnit
+}
+
+func (p *ConvertUnitFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	factor := p.config.Factor
+	if factor == 0 {
+		factor = 1
+	}
+	for i, field := range frame.Fields {
+		if !stringInSlice(field.Name, p.config.FieldNames) {
+			continue
+		}
+		if !field.Type().Numeric() {
+			return nil, fmt.Errorf("can't convert unit of non-numeric field %s", field.Name)
+		}
+		converted := data.NewFieldFromFieldType(data.FieldTypeNullableFloat64, field.Len())
+		converted.Name = field.Name
+		converted.Labels = field.Labels
+		converted.Config = field.Config
+		for j := 0; j < field.Len(); j++ {
+			v, err := field.NullableFloatAt(j)
+			if err != nil {
+				return nil, err
+			}
+			if v == nil {
+				continue
+			}
+			value := *v*factor + p.config.Offset
+			converted.Set(j, &value)
+		}
+		if p.config.Unit != "" {
+			if converted.Config == nil {
+				converted.Config = &data.FieldConfig{}
+			}
+			converted.Config.Unit = p.config.Unit
+		}
+		frame.Fields[i] = converted
+	}
+	return frame, nil
+}
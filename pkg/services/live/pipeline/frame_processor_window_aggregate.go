@@ -0,0 +1,384 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	WindowAggregationAvg   = "avg"
+	WindowAggregationMin   = "min"
+	WindowAggregationMax   = "max"
+	WindowAggregationSum   = "sum"
+	WindowAggregationCount = "count"
+	WindowAggregationLast  = "last"
+)
+
+var windowAggregations = []string{
+	WindowAggregationAvg,
+	WindowAggregationMin,
+	WindowAggregationMax,
+	WindowAggregationSum,
+	WindowAggregationCount,
+	WindowAggregationLast,
+}
+
+// windowStateTTL is how long the state of a processor no longer part of channel rules,
+// e.g. after its rule was changed or removed, is kept.
+const windowStateTTL = 5 * time.Minute
+
+// WindowAggregateFrameProcessor can downsample high-frequency streams using tumbling
+// windows. It accumulates numeric values of incoming frames until a value belonging to
+// the next window arrives or the window end passes, then outputs a single row per
+// closed window with the configured aggregations. While a window is still open the
+// processor returns nil frame, so no further processing or outputting happens for the
+// channel. Windows whose end passed are flushed by Pipeline.Run.
+type WindowAggregateFrameProcessor struct {
+	config WindowAggregateFrameProcessorConfig
+	state  *windowAggregateState
+}
+
+type windowAggregateKey struct {
+	orgID   int64
+	channel string
+}
+
+// windowAggregateState holds the open windows of a processor. It lives outside the
+// processor so that the open windows are not lost when rules are rebuilt.
+type windowAggregateState struct {
+	mu      sync.Mutex
+	windows map[windowAggregateKey]*aggregationWindow
+	// built is when a processor was last built with the state.
+	built time.Time
+}
+
+func newWindowAggregateState() *windowAggregateState {
+	return &windowAggregateState{windows: map[windowAggregateKey]*aggregationWindow{}}
+}
+
+// windowAggregateStates keeps the state of window aggregate processors by processor
+// location in channel rules and processor config, see StorageRuleBuilder.
+type windowAggregateStates struct {
+	mu     sync.Mutex
+	states map[string]*windowAggregateState
+}
+
+func newWindowAggregateStates() *windowAggregateStates {
+	return &windowAggregateStates{states: map[string]*windowAggregateState{}}
+}
+
+func (s *windowAggregateStates) get(key string) *windowAggregateState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[key]
+	if !ok {
+		state = newWindowAggregateState()
+		s.states[key] = state
+	}
+	state.mu.Lock()
+	state.built = time.Now()
+	state.mu.Unlock()
+	return state
+}
+
+// expired returns the channels with an open window whose deadline passed, each window
+// is returned once. States of processors which are not built anymore are removed.
+func (s *windowAggregateStates) expired(now time.Time) []windowAggregateKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []windowAggregateKey
+	seen := map[windowAggregateKey]struct{}{}
+	for stateKey, state := range s.states {
+		state.mu.Lock()
+		if now.Sub(state.built) > windowStateTTL {
+			state.mu.Unlock()
+			delete(s.states, stateKey)
+			continue
+		}
+		for key, window := range state.windows {
+			if window.flushing || now.Before(window.deadline) {
+				continue
+			}
+			window.flushing = true
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+		state.mu.Unlock()
+	}
+	return keys
+}
+
+// windowAggregateStatesGetter is implemented by rule getters and builders keeping the
+// state of window aggregate processors.
+type windowAggregateStatesGetter interface {
+	getWindowStates() *windowAggregateStates
+}
+
+type windowFlushContextKey struct{}
+
+// withWindowFlush marks frames processed with the context as flushing the windows
+// whose deadline passed at the time.
+func withWindowFlush(ctx context.Context, now time.Time) context.Context {
+	return context.WithValue(ctx, windowFlushContextKey{}, now)
+}
+
+func windowFlushFromContext(ctx context.Context) (time.Time, bool) {
+	now, ok := ctx.Value(windowFlushContextKey{}).(time.Time)
+	return now, ok
+}
+
+func NewWindowAggregateFrameProcessor(config WindowAggregateFrameProcessorConfig) (*WindowAggregateFrameProcessor, error) {
+	return newWindowAggregateFrameProcessor(config, newWindowAggregateState())
+}
+
+func newWindowAggregateFrameProcessor(config WindowAggregateFrameProcessorConfig, state *windowAggregateState) (*WindowAggregateFrameProcessor, error) {
+	if config.IntervalMs <= 0 {
+		return nil, fmt.Errorf("window interval must be positive, got %d", config.IntervalMs)
+	}
+	if len(config.Aggregations) == 0 {
+		config.Aggregations = []string{WindowAggregationAvg}
+	}
+	for _, agg := range config.Aggregations {
+		if !stringInSlice(agg, windowAggregations) {
+			return nil, fmt.Errorf("unknown window aggregation: %s", agg)
+		}
+	}
+	return &WindowAggregateFrameProcessor{
+		config: config,
+		state:  state,
+	}, nil
+}
+
+const FrameProcessorTypeWindowAggregate = "windowAggregate"
+
+func (p *WindowAggregateFrameProcessor) Type() string {
+	return FrameProcessorTypeWindowAggregate
+}
+
+func (p *WindowAggregateFrameProcessor) ProcessFrame(ctx context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	rowLen, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+
+	timeField := p.timeField(frame)
+	interval := time.Duration(p.config.IntervalMs) * time.Millisecond
+	key := windowAggregateKey{orgID: vars.OrgID, channel: vars.Channel}
+
+	p.state.mu.Lock()
+	defer p.state.mu.Unlock()
+
+	var closed []*aggregationWindow
+	if now, ok := windowFlushFromContext(ctx); ok {
+		if window, ok := p.state.windows[key]; ok && !now.Before(window.deadline) {
+			closed = append(closed, window)
+			delete(p.state.windows, key)
+		}
+	}
+
+	for i := 0; i < rowLen; i++ {
+		now := time.Now()
+		ts := now
+		if timeField != nil {
+			t, ok := timeAt(timeField, i)
+			if !ok {
+				continue
+			}
+			ts = t
+		}
+		start := ts.Truncate(interval)
+
+		window, ok := p.state.windows[key]
+		if ok && !start.Equal(window.start) {
+			if start.Before(window.start) {
+				// Late value for already closed window, skip it.
+				continue
+			}
+			closed = append(closed, window)
+			ok = false
+		}
+		if !ok {
+			// The deadline is the window end in wall clock time, so that windows of
+			// streams with delayed or replayed timestamps are flushed as well.
+			window = newAggregationWindow(frame.Name, start, now.Add(start.Add(interval).Sub(ts)))
+			p.state.windows[key] = window
+		}
+
+		for _, field := range frame.Fields {
+			if field == timeField || !field.Type().Numeric() {
+				continue
+			}
+			if len(p.config.FieldNames) > 0 && !stringInSlice(field.Name, p.config.FieldNames) {
+				continue
+			}
+			v, err := field.NullableFloatAt(i)
+			if err != nil {
+				return nil, err
+			}
+			window.add(field, v)
+		}
+	}
+
+	if len(closed) == 0 {
+		return nil, nil
+	}
+	return p.buildFrame(closed[0].name, closed), nil
+}
+
+func (p *WindowAggregateFrameProcessor) timeField(frame *data.Frame) *data.Field {
+	for _, field := range frame.Fields {
+		if p.config.TimeFieldName != "" {
+			if field.Name == p.config.TimeFieldName {
+				return field
+			}
+			continue
+		}
+		if field.Type() == data.FieldTypeTime || field.Type() == data.FieldTypeNullableTime {
+			return field
+		}
+	}
+	return nil
+}
+
+func timeAt(field *data.Field, idx int) (time.Time, bool) {
+	switch v := field.At(idx).(type) {
+	case time.Time:
+		return v, true
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return *v, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func (p *WindowAggregateFrameProcessor) buildFrame(name string, windows []*aggregationWindow) *data.Frame {
+	timeFieldName := p.config.TimeFieldName
+	if timeFieldName == "" {
+		timeFieldName = "time"
+	}
+	timeField := data.NewField(timeFieldName, nil, make([]time.Time, len(windows)))
+
+	// Windows may have seen different sets of fields, so collect all of them in
+	// order of appearance.
+	var seriesKeys []string
+	series := map[string]*aggregationSeries{}
+	for _, w := range windows {
+		for _, k := range w.order {
+			if _, ok := series[k]; !ok {
+				seriesKeys = append(seriesKeys, k)
+				series[k] = w.series[k]
+			}
+		}
+	}
+
+	fields := []*data.Field{timeField}
+	valueFields := map[string]map[string]*data.Field{}
+	for _, k := range seriesKeys {
+		s := series[k]
+		valueFields[k] = map[string]*data.Field{}
+		for _, agg := range p.config.Aggregations {
+			f := data.NewField(s.name+"_"+agg, s.labels, make([]*float64, len(windows)))
+			f.Config = s.config
+			valueFields[k][agg] = f
+			fields = append(fields, f)
+		}
+	}
+
+	for i, w := range windows {
+		timeField.Set(i, w.start)
+		for k, s := range w.series {
+			for _, agg := range p.config.Aggregations {
+				valueFields[k][agg].Set(i, s.value(agg))
+			}
+		}
+	}
+	return data.NewFrame(name, fields...)
+}
+
+type aggregationWindow struct {
+	name     string
+	start    time.Time
+	deadline time.Time
+	// flushing is set once the window was returned by windowAggregateStates.expired.
+	flushing bool
+	order    []string
+	series   map[string]*aggregationSeries
+}
+
+func newAggregationWindow(name string, start time.Time, deadline time.Time) *aggregationWindow {
+	return &aggregationWindow{name: name, start: start, deadline: deadline, series: map[string]*aggregationSeries{}}
+}
+
+func (w *aggregationWindow) add(field *data.Field, v *float64) {
+	k := field.Name + field.Labels.String()
+	s, ok := w.series[k]
+	if !ok {
+		s = &aggregationSeries{
+			name:   field.Name,
+			labels: field.Labels,
+			config: field.Config,
+			min:    math.Inf(1),
+			max:    math.Inf(-1),
+		}
+		w.series[k] = s
+		w.order = append(w.order, k)
+	}
+	if v == nil || math.IsNaN(*v) {
+		return
+	}
+	s.count++
+	s.sum += *v
+	s.min = math.Min(s.min, *v)
+	s.max = math.Max(s.max, *v)
+	s.last = *v
+}
+
+type aggregationSeries struct {
+	name   string
+	labels data.Labels
+	config *data.FieldConfig
+
+	count int
+	sum   float64
+	min   float64
+	max   float64
+	last  float64
+}
+
+func (s *aggregationSeries) value(agg string) *float64 {
+	var v float64
+	switch agg {
+	case WindowAggregationCount:
+		v = float64(s.count)
+		return &v
+	}
+	if s.count == 0 {
+		return nil
+	}
+	switch agg {
+	case WindowAggregationAvg:
+		v = s.sum / float64(s.count)
+	case WindowAggregationMin:
+		v = s.min
+	case WindowAggregationMax:
+		v = s.max
+	case WindowAggregationSum:
+		v = s.sum
+	case WindowAggregationLast:
+		v = s.last
+	default:
+		return nil
+	}
+	return &v
+}
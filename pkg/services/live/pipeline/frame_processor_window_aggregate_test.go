@@ -0,0 +1,242 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func windowTestFrame(t time.Time, value float64) *data.Frame {
+	return data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{t}),
+		data.NewField("value", nil, []float64{value}),
+	)
+}
+
+func TestWindowAggregateFrameProcessor(t *testing.T) {
+	p, err := NewWindowAggregateFrameProcessor(WindowAggregateFrameProcessorConfig{
+		IntervalMs:   1000,
+		Aggregations: []string{WindowAggregationAvg, WindowAggregationMin, WindowAggregationMax},
+	})
+	require.NoError(t, err)
+
+	vars := Vars{OrgID: 1, Channel: "stream/test/window"}
+	start := time.Unix(100, 0)
+
+	for i, v := range []float64{1, 5, 3} {
+		frame, err := p.ProcessFrame(context.Background(), vars, windowTestFrame(start.Add(time.Duration(i)*100*time.Millisecond), v))
+		require.NoError(t, err)
+		require.Nil(t, frame)
+	}
+
+	frame, err := p.ProcessFrame(context.Background(), vars, windowTestFrame(start.Add(time.Second), 10))
+	require.NoError(t, err)
+	require.NotNil(t, frame)
+	require.Len(t, frame.Fields, 4)
+	require.Equal(t, start, frame.Fields[0].At(0))
+	require.Equal(t, "value_avg", frame.Fields[1].Name)
+	require.Equal(t, 3.0, *frame.Fields[1].At(0).(*float64))
+	require.Equal(t, 1.0, *frame.Fields[2].At(0).(*float64))
+	require.Equal(t, 5.0, *frame.Fields[3].At(0).(*float64))
+}
+
+func TestWindowAggregateFrameProcessor_UnknownAggregation(t *testing.T) {
+	_, err := NewWindowAggregateFrameProcessor(WindowAggregateFrameProcessorConfig{
+		IntervalMs:   1000,
+		Aggregations: []string{"median"},
+	})
+	require.Error(t, err)
+}
+
+func TestWindowAggregateFrameProcessor_Chained(t *testing.T) {
+	window, err := NewWindowAggregateFrameProcessor(WindowAggregateFrameProcessorConfig{
+		IntervalMs:   1000,
+		Aggregations: []string{WindowAggregationMax},
+	})
+	require.NoError(t, err)
+	p := NewMultipleFrameProcessor(window, NewRenameFieldsFrameProcessor(RenameFieldsFrameProcessorConfig{
+		Names: map[string]string{"value_max": "max"},
+	}))
+
+	vars := Vars{OrgID: 1, Channel: "stream/test/window"}
+	start := time.Unix(100, 0)
+
+	frame, err := p.ProcessFrame(context.Background(), vars, windowTestFrame(start, 1))
+	require.NoError(t, err)
+	require.Nil(t, frame)
+
+	frame, err = p.ProcessFrame(context.Background(), vars, windowTestFrame(start.Add(time.Second), 2))
+	require.NoError(t, err)
+	require.NotNil(t, frame)
+	require.Equal(t, "max", frame.Fields[1].Name)
+	require.Equal(t, 1.0, *frame.Fields[1].At(0).(*float64))
+}
+
+type windowTestRuleStorage struct {
+	Storage
+	rules []ChannelRule
+}
+
+func (s windowTestRuleStorage) ListChannelRules(_ context.Context, _ int64) ([]ChannelRule, error) {
+	return s.rules, nil
+}
+
+func (s windowTestRuleStorage) ListWriteConfigs(_ context.Context, _ int64) ([]WriteConfig, error) {
+	return nil, nil
+}
+
+func TestWindowAggregateFrameProcessor_KeepsWindowsAcrossRebuilds(t *testing.T) {
+	builder := &StorageRuleBuilder{
+		Storage: windowTestRuleStorage{rules: []ChannelRule{{
+			Pattern: "stream/test/window",
+			Settings: ChannelRuleSettings{
+				FrameProcessors: []*FrameProcessorConfig{{
+					Type: FrameProcessorTypeWindowAggregate,
+					WindowAggregateProcessorConfig: &WindowAggregateFrameProcessorConfig{
+						IntervalMs:   1000,
+						Aggregations: []string{WindowAggregationSum},
+					},
+				}},
+			},
+		}}},
+	}
+
+	vars := Vars{OrgID: 1, Channel: "stream/test/window"}
+	start := time.Unix(100, 0)
+
+	rules, err := builder.BuildRules(context.Background(), 1)
+	require.NoError(t, err)
+	frame, err := rules[0].FrameProcessors[0].ProcessFrame(context.Background(), vars, windowTestFrame(start, 1))
+	require.NoError(t, err)
+	require.Nil(t, frame)
+
+	rules, err = builder.BuildRules(context.Background(), 1)
+	require.NoError(t, err)
+	frame, err = rules[0].FrameProcessors[0].ProcessFrame(context.Background(), vars, windowTestFrame(start.Add(500*time.Millisecond), 2))
+	require.NoError(t, err)
+	require.Nil(t, frame)
+
+	frame, err = rules[0].FrameProcessors[0].ProcessFrame(context.Background(), vars, windowTestFrame(start.Add(time.Second), 4))
+	require.NoError(t, err)
+	require.NotNil(t, frame)
+	require.Equal(t, 3.0, *frame.Fields[1].At(0).(*float64))
+}
+
+func TestWindowAggregateFrameProcessor_ChangedConfig(t *testing.T) {
+	config := &WindowAggregateFrameProcessorConfig{IntervalMs: 1000, Aggregations: []string{WindowAggregationSum}}
+	builder := &StorageRuleBuilder{
+		Storage: windowTestRuleStorage{rules: []ChannelRule{{
+			Pattern: "stream/test/window",
+			Settings: ChannelRuleSettings{
+				FrameProcessors: []*FrameProcessorConfig{{Type: FrameProcessorTypeWindowAggregate, WindowAggregateProcessorConfig: config}},
+			},
+		}}},
+	}
+
+	vars := Vars{OrgID: 1, Channel: "stream/test/window"}
+	start := time.Unix(100, 0)
+
+	rules, err := builder.BuildRules(context.Background(), 1)
+	require.NoError(t, err)
+	frame, err := rules[0].FrameProcessors[0].ProcessFrame(context.Background(), vars, windowTestFrame(start, 1))
+	require.NoError(t, err)
+	require.Nil(t, frame)
+
+	// the window opened with the previous interval is not continued
+	config.IntervalMs = 2000
+	rules, err = builder.BuildRules(context.Background(), 1)
+	require.NoError(t, err)
+	frame, err = rules[0].FrameProcessors[0].ProcessFrame(context.Background(), vars, windowTestFrame(start.Add(500*time.Millisecond), 2))
+	require.NoError(t, err)
+	require.Nil(t, frame)
+
+	frame, err = rules[0].FrameProcessors[0].ProcessFrame(context.Background(), vars, windowTestFrame(start.Add(2*time.Second), 4))
+	require.NoError(t, err)
+	require.NotNil(t, frame)
+	require.Equal(t, 2.0, *frame.Fields[1].At(0).(*float64))
+}
+
+func TestWindowAggregateFrameProcessor_Flush(t *testing.T) {
+	states := newWindowAggregateStates()
+	p, err := newWindowAggregateFrameProcessor(WindowAggregateFrameProcessorConfig{
+		IntervalMs:   1000,
+		Aggregations: []string{WindowAggregationSum},
+	}, states.get("1/stream/test/window/0"))
+	require.NoError(t, err)
+
+	vars := Vars{OrgID: 1, Channel: "stream/test/window"}
+	now := time.Now()
+
+	frame, err := p.ProcessFrame(context.Background(), vars, windowTestFrame(now, 1))
+	require.NoError(t, err)
+	require.Nil(t, frame)
+	require.Empty(t, states.expired(now))
+
+	// the deadline is a bit after the window end, the value was processed after it was created
+	end := now.Truncate(time.Second).Add(time.Second + 100*time.Millisecond)
+	require.Equal(t, []windowAggregateKey{{orgID: 1, channel: "stream/test/window"}}, states.expired(end))
+	require.Empty(t, states.expired(end), "windows are returned once")
+
+	frame, err = p.ProcessFrame(withWindowFlush(context.Background(), end), vars, data.NewFrame(""))
+	require.NoError(t, err)
+	require.NotNil(t, frame)
+	require.Equal(t, "test", frame.Name)
+	require.Equal(t, 1.0, *frame.Fields[1].At(0).(*float64))
+	require.Empty(t, p.state.windows)
+}
+
+func TestWindowAggregateStates_RemovesUnusedStates(t *testing.T) {
+	states := newWindowAggregateStates()
+	states.get("1/stream/test/window/0")
+
+	states.expired(time.Now())
+	require.Len(t, states.states, 1)
+
+	states.expired(time.Now().Add(windowStateTTL + time.Second))
+	require.Empty(t, states.states)
+}
+
+type windowTestRuleGetter struct {
+	testRuleGetter
+	states *windowAggregateStates
+}
+
+func (g *windowTestRuleGetter) getWindowStates() *windowAggregateStates {
+	return g.states
+}
+
+func TestPipeline_FlushWindows(t *testing.T) {
+	states := newWindowAggregateStates()
+	window, err := newWindowAggregateFrameProcessor(WindowAggregateFrameProcessorConfig{
+		IntervalMs:   1000,
+		Aggregations: []string{WindowAggregationMax},
+	}, states.get("1/stream/test/window/0"))
+	require.NoError(t, err)
+
+	outputter := &testOutputter{}
+	p, err := New(&windowTestRuleGetter{
+		testRuleGetter: testRuleGetter{rules: map[string]*LiveChannelRule{
+			"stream/test/window": {
+				FrameProcessors: []FrameProcessor{window},
+				FrameOutputters: []FrameOutputter{outputter},
+			},
+		}},
+		states: states,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	err = p.processChannelFrames(context.Background(), 1, "stream/test/window", []*ChannelFrame{{Frame: windowTestFrame(now, 3)}}, nil)
+	require.NoError(t, err)
+	require.Nil(t, outputter.frame)
+
+	p.flushWindows(context.Background(), states, now)
+	require.Nil(t, outputter.frame)
+
+	p.flushWindows(context.Background(), states, now.Add(2*time.Second))
+	require.NotNil(t, outputter.frame)
+	require.Equal(t, 3.0, *outputter.frame.Fields[1].At(0).(*float64))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -210,6 +211,44 @@ func New(ruleGetter ChannelRuleGetter) (*Pipeline, error) {
 	return p, nil
 }
 
+// windowFlushInterval is how often windows of window aggregate processors are checked
+// for their end.
+const windowFlushInterval = time.Second
+
+// Run flushes the windows of window aggregate processors whose end passed without a
+// value of the next window closing them, until the context is canceled.
+func (p *Pipeline) Run(ctx context.Context) error {
+	getter, ok := p.ruleGetter.(windowAggregateStatesGetter)
+	if !ok {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ticker := time.NewTicker(windowFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			if states := getter.getWindowStates(); states != nil {
+				p.flushWindows(ctx, states, now)
+			}
+		}
+	}
+}
+
+// flushWindows runs the rules of the channels with expired windows on an empty frame,
+// window aggregate processors then output the expired windows.
+func (p *Pipeline) flushWindows(ctx context.Context, states *windowAggregateStates, now time.Time) {
+	for _, key := range states.expired(now) {
+		channelFrames := []*ChannelFrame{{Channel: key.channel, Frame: data.NewFrame("")}}
+		if err := p.processChannelFrames(withWindowFlush(ctx, now), key.orgID, key.channel, channelFrames, nil); err != nil {
+			logger.Error("Error flushing window", "error", err, "orgId", key.orgID, "channel", key.channel)
+		}
+	}
+}
+
 func (p *Pipeline) Get(orgID int64, channel string) (*LiveChannelRule, bool, error) {
 	return p.ruleGetter.Get(orgID, channel)
 }
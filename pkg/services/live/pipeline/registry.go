@@ -79,6 +79,39 @@ var FrameProcessorsRegistry = []EntityInfo{
 		Description: "list the fields that should be removed",
 		Example:     DropFieldsFrameProcessorConfig{},
 	},
+	{
+		Type:        FrameProcessorTypeRenameFields,
+		Description: "rename fields",
+		Example: RenameFieldsFrameProcessorConfig{
+			Names: map[string]string{"temp": "temperature"},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeComputedField,
+		Description: "add a field calculated from an arithmetic expression over other fields",
+		Example: ComputedFieldFrameProcessorConfig{
+			FieldName:  "power",
+			Expression: "$voltage * $current",
+		},
+	},
+	{
+		Type:        FrameProcessorTypeConvertUnit,
+		Description: "convert numeric field values with a factor and offset",
+		Example: ConvertUnitFrameProcessorConfig{
+			FieldNames: []string{"temperature"},
+			Factor:     1.8,
+			Offset:     32,
+			Unit:       "fahrenheit",
+		},
+	},
+	{
+		Type:        FrameProcessorTypeWindowAggregate,
+		Description: "aggregate frames in tumbling time windows to reduce the output rate",
+		Example: WindowAggregateFrameProcessorConfig{
+			IntervalMs:   1000,
+			Aggregations: []string{WindowAggregationAvg, WindowAggregationMin, WindowAggregationMax},
+		},
+	},
 }
 
 var DataOutputsRegistry = []EntityInfo{
@@ -2,7 +2,11 @@ package pipeline
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"sync"
 
 	"github.com/centrifugal/centrifuge"
 
@@ -17,6 +21,18 @@ type StorageRuleBuilder struct {
 	Storage              Storage
 	ChannelHandlerGetter ChannelHandlerGetter
 	SecretsService       secrets.Service
+
+	// windowStates keeps the open windows of window aggregate processors
+	// between rule rebuilds.
+	windowStatesOnce sync.Once
+	windowStates     *windowAggregateStates
+}
+
+func (f *StorageRuleBuilder) getWindowStates() *windowAggregateStates {
+	f.windowStatesOnce.Do(func() {
+		f.windowStates = newWindowAggregateStates()
+	})
+	return f.windowStates
 }
 
 func (f *StorageRuleBuilder) extractSubscriber(config *SubscriberConfig) (Subscriber, error) {
@@ -74,7 +90,10 @@ func (f *StorageRuleBuilder) extractConverter(config *ConverterConfig) (Converte
 	}
 }
 
-func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig) (FrameProcessor, error) {
+// extractFrameProcessor builds the processor of the config. The key identifies the
+// location of the processor in channel rules, processors keeping state use it to
+// find their state after rules are rebuilt.
+func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig, key string) (FrameProcessor, error) {
 	if config == nil {
 		return nil, nil
 	}
@@ -90,14 +109,40 @@ func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig)
 			return nil, missingConfiguration
 		}
 		return NewKeepFieldsFrameProcessor(*config.KeepFieldsProcessorConfig), nil
+	case FrameProcessorTypeRenameFields:
+		if config.RenameFieldsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewRenameFieldsFrameProcessor(*config.RenameFieldsProcessorConfig), nil
+	case FrameProcessorTypeComputedField:
+		if config.ComputedFieldProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewComputedFieldFrameProcessor(*config.ComputedFieldProcessorConfig)
+	case FrameProcessorTypeConvertUnit:
+		if config.ConvertUnitProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewConvertUnitFrameProcessor(*config.ConvertUnitProcessorConfig), nil
+	case FrameProcessorTypeWindowAggregate:
+		if config.WindowAggregateProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		// Windows opened with another config are not continued, e.g. after the interval changed.
+		configJSON, err := json.Marshal(config.WindowAggregateProcessorConfig)
+		if err != nil {
+			return nil, err
+		}
+		state := f.getWindowStates().get(fmt.Sprintf("%s/%x", key, sha256.Sum256(configJSON)))
+		return newWindowAggregateFrameProcessor(*config.WindowAggregateProcessorConfig, state)
 	case FrameProcessorTypeMultiple:
 		if config.MultipleProcessorConfig == nil {
 			return nil, missingConfiguration
 		}
 		var processors []FrameProcessor
-		for _, outConf := range config.MultipleProcessorConfig.Processors {
+		for i, outConf := range config.MultipleProcessorConfig.Processors {
 			out := outConf
-			proc, err := f.extractFrameProcessor(&out)
+			proc, err := f.extractFrameProcessor(&out, key+"/"+strconv.Itoa(i))
 			if err != nil {
 				return nil, err
 			}
@@ -334,8 +379,9 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 		}
 
 		var processors []FrameProcessor
-		for _, procConfig := range ruleConfig.Settings.FrameProcessors {
-			proc, err := f.extractFrameProcessor(procConfig)
+		for i, procConfig := range ruleConfig.Settings.FrameProcessors {
+			key := fmt.Sprintf("%d/%s/%d", orgID, rule.Pattern, i)
+			proc, err := f.extractFrameProcessor(procConfig, key)
 			if err != nil {
 				return nil, fmt.Errorf("error building processor for %s: %w", rule.Pattern, err)
 			}
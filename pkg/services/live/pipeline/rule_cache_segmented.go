@@ -59,6 +59,13 @@ func (s *CacheSegmentedTree) fillOrg(orgID int64) error {
 	return nil
 }
 
+func (s *CacheSegmentedTree) getWindowStates() *windowAggregateStates {
+	if getter, ok := s.ruleBuilder.(windowAggregateStatesGetter); ok {
+		return getter.getWindowStates()
+	}
+	return nil
+}
+
 func (s *CacheSegmentedTree) Get(orgID int64, channel string) (*LiveChannelRule, bool, error) {
 	s.radixMu.RLock()
 	_, ok := s.radix[orgID]
@@ -8,6 +8,7 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -434,3 +435,28 @@ func NotificationSettingsFromAlertRuleNotificationSettings(ns *definitions.Alert
 		},
 	}
 }
+
+func backtestSummaryToApi(s backtesting.Summary) definitions.BacktestSummary {
+	return definitions.BacktestSummary{
+		Evaluations:    s.Evaluations,
+		Transitions:    s.Transitions,
+		FiringDuration: model.Duration(s.FiringDuration),
+		Notifications:  s.Notifications,
+	}
+}
+
+func backtestComparisonToApi(c *backtesting.Comparison) definitions.BacktestComparisonResult {
+	result := definitions.BacktestComparisonResult{
+		Current:  backtestSummaryToApi(c.Current),
+		Proposed: backtestSummaryToApi(c.Proposed),
+		Series:   make([]definitions.BacktestSeriesComparison, 0, len(c.Series)),
+	}
+	for _, s := range c.Series {
+		result.Series = append(result.Series, definitions.BacktestSeriesComparison{
+			Labels:   s.Labels,
+			Current:  backtestSummaryToApi(s.Current),
+			Proposed: backtestSummaryToApi(s.Proposed),
+		})
+	}
+	return result
+}
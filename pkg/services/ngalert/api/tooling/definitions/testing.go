@@ -68,6 +68,19 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /v1/rule/backtest/compare testing BacktestCompareConfig
+//
+// Compare the backtesting results of two versions of a rule
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestComparisonResult
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -217,8 +230,14 @@ type BacktestConfigRequest struct {
 
 // swagger:model
 type BacktestConfig struct {
-	From     time.Time      `json:"from"`
-	To       time.Time      `json:"to"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	BacktestRule
+}
+
+// BacktestRule is the definition of the rule to backtest
+type BacktestRule struct {
 	Interval model.Duration `json:"interval,omitempty"`
 
 	Condition string         `json:"condition"`
@@ -234,3 +253,45 @@ type BacktestConfig struct {
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:parameters BacktestCompareConfig
+type BacktestCompareConfigRequest struct {
+	// in:body
+	Body BacktestCompareConfig
+}
+
+// swagger:model
+type BacktestCompareConfig struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// Current is the rule as it is today
+	Current BacktestRule `json:"current"`
+	// Proposed is the modified rule
+	Proposed BacktestRule `json:"proposed"`
+}
+
+// swagger:model
+type BacktestComparisonResult struct {
+	Current  BacktestSummary `json:"current"`
+	Proposed BacktestSummary `json:"proposed"`
+	// Series compares the series of the rules, identified by the labels of the query results
+	Series []BacktestSeriesComparison `json:"series"`
+}
+
+// BacktestSummary summarizes the behavior of a rule, or one of its series, over the backtesting time range
+type BacktestSummary struct {
+	Evaluations int `json:"evaluations"`
+	// Transitions is the number of state changes
+	Transitions int `json:"transitions"`
+	// FiringDuration is the time spent in the Alerting state
+	FiringDuration model.Duration `json:"firing_duration"`
+	// Notifications is the number of firing and resolved notifications, repeated notifications are not counted
+	Notifications int `json:"notifications"`
+}
+
+type BacktestSeriesComparison struct {
+	Labels   map[string]string `json:"labels"`
+	Current  BacktestSummary   `json:"current"`
+	Proposed BacktestSummary   `json:"proposed"`
+}
@@ -0,0 +1,52 @@
+package backtesting
+
+import (
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// Comparison is the result of the backtesting of two versions of a rule over the same time range
+type Comparison struct {
+	Current  Summary
+	Proposed Summary
+	// Series compares the series of both versions, identified by the labels of the query results
+	Series []SeriesComparison
+}
+
+type SeriesComparison struct {
+	Labels   data.Labels
+	Current  Summary
+	Proposed Summary
+}
+
+// Summary summarizes the behavior of a rule, or one of its series, over the backtesting time range
+type Summary struct {
+	Evaluations int
+	// Transitions is the number of state changes
+	Transitions int
+	// FiringDuration is the time spent in the Alerting state
+	FiringDuration time.Duration
+	// Notifications is the number of firing and resolved notifications. Repeated notifications depend on the
+	// notification policies and are not counted.
+	Notifications int
+}
+
+// add accounts for the state of a series after an evaluation, the state lasts until the next evaluation
+func (s *Summary) add(t state.StateTransition, interval time.Duration) {
+	if t.State.State != t.PreviousState {
+		s.Transitions++
+	}
+	if t.State.State == eval.Alerting {
+		s.FiringDuration += interval
+		if t.PreviousState != eval.Alerting {
+			s.Notifications++
+		}
+	}
+	if t.Resolved {
+		s.Notifications++
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"sort"
 	"time"
 
 	"github.com/benbjohnson/clock"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	alertingModels "github.com/grafana/alerting/models"
+
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
@@ -64,40 +67,15 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 }
 
 func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
-	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
-	logger := logger.FromContext(ctx)
-
-	if !from.Before(to) {
-		return nil, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
-	}
-	if to.Sub(from).Seconds() < float64(rule.IntervalSeconds) {
-		return nil, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
-	}
-	length := int(to.Sub(from).Seconds()) / int(rule.IntervalSeconds)
-
-	stateManager := e.createStateManager()
-
-	evaluator, err := backtestingEvaluatorFactory(ruleCtx, e.evalFactory, user, rule.GetEvalCondition(), &schedule.AlertingResultsFromRuleState{
-		Manager: stateManager,
-		Rule:    rule,
-	})
+	length, err := evaluationsCount(rule, from, to)
 	if err != nil {
-		return nil, errors.Join(ErrInvalidInputData, err)
+		return nil, err
 	}
 
-	logger.Info("Start testing alert rule", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluations", length)
-
-	start := time.Now()
-
 	tsField := data.NewField("Time", nil, make([]time.Time, length))
 	valueFields := make(map[string]*data.Field)
 
-	err = evaluator.Eval(ruleCtx, from, time.Duration(rule.IntervalSeconds)*time.Second, length, func(idx int, currentTime time.Time, results eval.Results) error {
-		if idx >= length {
-			logger.Info("Unexpected evaluation. Skipping", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluationTime", currentTime, "evaluationIndex", idx, "expectedEvaluations", length)
-			return nil
-		}
-		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil)
+	err = e.evaluate(ctx, user, rule, from, length, func(idx int, currentTime time.Time, states []state.StateTransition) {
 		tsField.Set(idx, currentTime)
 		for _, s := range states {
 			field, ok := valueFields[s.CacheID]
@@ -114,7 +92,6 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 				continue
 			}
 		}
-		return nil
 	})
 	fields := make([]*data.Field, 0, len(valueFields)+1)
 	fields = append(fields, tsField)
@@ -126,10 +103,119 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	if err != nil {
 		return nil, err
 	}
-	logger.Info("Rule testing finished successfully", "duration", time.Since(start))
 	return result, nil
 }
 
+// Compare evaluates the current and the proposed versions of a rule over the same time range and summarizes how
+// their states differ.
+func (e *Engine) Compare(ctx context.Context, user identity.Requester, current, proposed *models.AlertRule, from, to time.Time) (*Comparison, error) {
+	currentLength, err := evaluationsCount(current, from, to)
+	if err != nil {
+		return nil, err
+	}
+	proposedLength, err := evaluationsCount(proposed, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Comparison{}
+	series := make(map[data.Fingerprint]*SeriesComparison)
+	getSeries := func(s state.StateTransition) *SeriesComparison {
+		c, ok := series[s.ResultFingerprint]
+		if !ok {
+			labels := s.Labels.Copy()
+			delete(labels, alertingModels.RuleUIDLabel)
+			c = &SeriesComparison{Labels: labels}
+			series[s.ResultFingerprint] = c
+		}
+		return c
+	}
+
+	err = e.evaluate(ctx, user, current, from, currentLength, func(_ int, _ time.Time, states []state.StateTransition) {
+		result.Current.Evaluations++
+		interval := time.Duration(current.IntervalSeconds) * time.Second
+		for _, s := range states {
+			result.Current.add(s, interval)
+			c := getSeries(s)
+			c.Current.Evaluations++
+			c.Current.add(s, interval)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = e.evaluate(ctx, user, proposed, from, proposedLength, func(_ int, _ time.Time, states []state.StateTransition) {
+		result.Proposed.Evaluations++
+		interval := time.Duration(proposed.IntervalSeconds) * time.Second
+		for _, s := range states {
+			result.Proposed.add(s, interval)
+			c := getSeries(s)
+			c.Proposed.Evaluations++
+			c.Proposed.add(s, interval)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Series = make([]SeriesComparison, 0, len(series))
+	for _, c := range series {
+		result.Series = append(result.Series, *c)
+	}
+	sort.Slice(result.Series, func(i, j int) bool {
+		return result.Series[i].Labels.String() < result.Series[j].Labels.String()
+	})
+	return result, nil
+}
+
+// evaluationsCount returns the number of evaluations of the rule over the time range
+func evaluationsCount(rule *models.AlertRule, from, to time.Time) (int, error) {
+	if !from.Before(to) {
+		return 0, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
+	}
+	if to.Sub(from).Seconds() < float64(rule.IntervalSeconds) {
+		return 0, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
+	}
+	return int(to.Sub(from).Seconds()) / int(rule.IntervalSeconds), nil
+}
+
+// evaluate evaluates the rule length times starting at from, and calls observe with the state transitions of every
+// evaluation
+func (e *Engine) evaluate(ctx context.Context, user identity.Requester, rule *models.AlertRule, from time.Time, length int, observe func(idx int, now time.Time, states []state.StateTransition)) error {
+	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+	logger := logger.FromContext(ctx)
+
+	stateManager := e.createStateManager()
+
+	evaluator, err := backtestingEvaluatorFactory(ruleCtx, e.evalFactory, user, rule.GetEvalCondition(), &schedule.AlertingResultsFromRuleState{
+		Manager: stateManager,
+		Rule:    rule,
+	})
+	if err != nil {
+		return errors.Join(ErrInvalidInputData, err)
+	}
+
+	logger.Info("Start testing alert rule", "from", from, "interval", rule.IntervalSeconds, "evaluations", length)
+
+	start := time.Now()
+
+	err = evaluator.Eval(ruleCtx, from, time.Duration(rule.IntervalSeconds)*time.Second, length, func(idx int, currentTime time.Time, results eval.Results) error {
+		if idx >= length {
+			logger.Info("Unexpected evaluation. Skipping", "from", from, "interval", rule.IntervalSeconds, "evaluationTime", currentTime, "evaluationIndex", idx, "expectedEvaluations", length)
+			return nil
+		}
+		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil)
+		observe(idx, currentTime, states)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info("Rule testing finished successfully", "duration", time.Since(start))
+	return nil
+}
+
 func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, reader eval.AlertingResultsReader) (backtestingEvaluator, error) {
 	for _, q := range condition.Data {
 		if q.DatasourceUID == "__data__" || q.QueryType == "__data__" {
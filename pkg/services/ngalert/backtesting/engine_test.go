@@ -378,6 +378,71 @@ func TestEvaluatorTest(t *testing.T) {
 	})
 }
 
+func TestEngineCompare(t *testing.T) {
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, r eval.AlertingResultsReader) (backtestingEvaluator, error) {
+		return &fakeBacktestingEvaluator{evalCallback: func(now time.Time) (eval.Results, error) { return nil, nil }}, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+
+	// transitions returns a state manager returning the given states of a single series, one per evaluation
+	transitions := func(from time.Time, interval time.Duration, states ...eval.State) *fakeStateManager {
+		return &fakeStateManager{stateCallback: func(now time.Time) []state.StateTransition {
+			idx := int(now.Sub(from) / interval)
+			previous := eval.Normal
+			if idx > 0 {
+				previous = states[idx-1]
+			}
+			return []state.StateTransition{{
+				State: &state.State{
+					CacheID:           "series",
+					ResultFingerprint: data.Fingerprint(1),
+					Labels:            data.Labels{"instance": "a", "__alert_rule_uid__": "uid"},
+					State:             states[idx],
+					Resolved:          previous == eval.Alerting && states[idx] == eval.Normal,
+				},
+				PreviousState: previous,
+			}}
+		}}
+	}
+
+	from := time.Unix(0, 0)
+	to := from.Add(5 * time.Second)
+	current := models.AlertRuleGen(models.WithInterval(time.Second))()
+	proposed := models.AlertRuleGen(models.WithInterval(time.Second))()
+
+	managers := []*fakeStateManager{
+		transitions(from, time.Second, eval.Normal, eval.Alerting, eval.Alerting, eval.Normal, eval.Alerting),
+		transitions(from, time.Second, eval.Normal, eval.Pending, eval.Alerting, eval.Normal, eval.Normal),
+	}
+	engine := &Engine{
+		createStateManager: func() stateManager {
+			m := managers[0]
+			managers = managers[1:]
+			return m
+		},
+	}
+
+	result, err := engine.Compare(context.Background(), nil, current, proposed, from, to)
+	require.NoError(t, err)
+
+	expectedCurrent := Summary{Evaluations: 5, Transitions: 3, FiringDuration: 3 * time.Second, Notifications: 3}
+	expectedProposed := Summary{Evaluations: 5, Transitions: 3, FiringDuration: time.Second, Notifications: 2}
+	require.Equal(t, expectedCurrent, result.Current)
+	require.Equal(t, expectedProposed, result.Proposed)
+	require.Equal(t, []SeriesComparison{{
+		Labels:   data.Labels{"instance": "a"},
+		Current:  expectedCurrent,
+		Proposed: expectedProposed,
+	}}, result.Series)
+
+	t.Run("should fail if the time range is shorter than an interval", func(t *testing.T) {
+		_, err := engine.Compare(context.Background(), nil, current, proposed, from, from.Add(500*time.Millisecond))
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+}
+
 type fakeStateManager struct {
 	stateCallback func(now time.Time) []state.StateTransition
 }
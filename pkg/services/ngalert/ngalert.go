@@ -131,6 +131,7 @@ type AlertNG struct {
 	ImageService        image.ImageService
 	schedule            schedule.ScheduleService
 	stateManager        *state.Manager
+	historian           Historian
 	folderService       folder.Service
 	dashboardService    dashboards.DashboardService
 	api                 *api.API
@@ -306,6 +307,7 @@ func (ng *AlertNG) init() error {
 	}
 
 	ng.stateManager = stateManager
+	ng.historian = history
 	ng.schedule = scheduler
 
 	receiverService := notifier.NewReceiverService(ng.accesscontrol, ng.store, ng.store, ng.SecretsService, ng.store, ng.Log)
@@ -431,6 +433,48 @@ func (ng *AlertNG) GetHooks() *api.Hooks {
 	return ng.api.Hooks
 }
 
+// GetStateManager returns the manager of the alert instance state cache, nil if
+// unified alerting is disabled.
+func (ng *AlertNG) GetStateManager() *state.Manager {
+	return ng.stateManager
+}
+
+// GetHistorian returns the backend of the alert state history, nil if unified
+// alerting is disabled.
+func (ng *AlertNG) GetHistorian() api.Historian {
+	if ng.historian == nil {
+		return nil
+	}
+	return ng.historian
+}
+
+// GetAlertRuleService returns the provisioning service of alert rules, nil if
+// unified alerting is disabled.
+func (ng *AlertNG) GetAlertRuleService() *provisioning.AlertRuleService {
+	if ng.api == nil {
+		return nil
+	}
+	return ng.api.AlertRules
+}
+
+// GetContactPointService returns the provisioning service of contact points,
+// nil if unified alerting is disabled.
+func (ng *AlertNG) GetContactPointService() *provisioning.ContactPointService {
+	if ng.api == nil {
+		return nil
+	}
+	return ng.api.ContactPointService
+}
+
+// GetNotificationPolicyService returns the provisioning service of the
+// notification policy tree, nil if unified alerting is disabled.
+func (ng *AlertNG) GetNotificationPolicyService() *provisioning.NotificationPolicyService {
+	if ng.api == nil {
+		return nil
+	}
+	return ng.api.Policies
+}
+
 type Historian interface {
 	api.Historian
 	state.Historian
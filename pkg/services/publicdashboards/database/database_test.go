@@ -238,6 +238,38 @@ func TestIntegrationExistsEnabledByAccessToken(t *testing.T) {
 		require.NoError(t, err)
 		require.False(t, res)
 	})
+
+	t.Run("ExistsEnabledByAccessToken will compare the expiration time with now", func(t *testing.T) {
+		setup()
+
+		expired := time.Now().Add(-time.Minute)
+		notExpired := time.Now().Add(time.Hour)
+		for _, pd := range []PublicDashboard{
+			{IsEnabled: true, Uid: "expired", AccessToken: "expiredAccessToken", ExpiresAt: &expired},
+			{IsEnabled: true, Uid: "notExpired", AccessToken: "notExpiredAccessToken", ExpiresAt: &notExpired},
+		} {
+			pd.DashboardUid = savedDashboard.UID
+			pd.OrgId = savedDashboard.OrgID
+			pd.CreatedAt = time.Now()
+			pd.CreatedBy = 7
+			_, err := publicdashboardStore.Create(context.Background(), SavePublicDashboardCommand{PublicDashboard: pd})
+			require.NoError(t, err)
+		}
+
+		res, err := publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), "expiredAccessToken")
+		require.NoError(t, err)
+		require.False(t, res)
+
+		res, err = publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), "notExpiredAccessToken")
+		require.NoError(t, err)
+		require.True(t, res)
+
+		found, err := publicdashboardStore.FindExpired(context.Background(), time.Now())
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		require.Equal(t, "expired", found[0].Uid)
+		require.Equal(t, expired.Unix(), found[0].ExpiresAt.Unix())
+	})
 }
 
 func TestIntegrationExistsEnabledByDashboardUid(t *testing.T) {
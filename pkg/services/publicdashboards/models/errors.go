@@ -23,6 +23,10 @@ var (
 	ErrDashboardIsPublic                   = errutil.BadRequest("publicdashboards.dashboardIsPublic", errutil.WithPublicMessage("Dashboard is already public"))
 	ErrPublicDashboardUidExists            = errutil.BadRequest("publicdashboards.uidExists", errutil.WithPublicMessage("Public Dashboard Uid already exists"))
 	ErrPublicDashboardAccessTokenExists    = errutil.BadRequest("publicdashboards.accessTokenExists", errutil.WithPublicMessage("Public Dashboard Access Token already exists"))
+	ErrInvalidExpiration                   = errutil.BadRequest("publicdashboards.invalidExpiration", errutil.WithPublicMessage("Expiration time must be in the future"))
+	ErrInvalidVariables                    = errutil.BadRequest("publicdashboards.invalidVariables", errutil.WithPublicMessage("Invalid template variables"))
+	ErrInvalidVariableValue                = errutil.BadRequest("publicdashboards.invalidVariableValue", errutil.WithPublicMessage("Template variable value not allowed"))
 
 	ErrPublicDashboardNotEnabled = errutil.Forbidden("publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
+	ErrPublicDashboardExpired    = errutil.Forbidden("publicdashboards.expired", errutil.WithPublicMessage("Public dashboard expired"))
 )
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"slices"
 	"time"
 
 	"github.com/grafana/grafana/pkg/kinds/dashboard"
@@ -54,6 +55,29 @@ type PublicDashboard struct {
 	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
 	Share                ShareType     `json:"share" xorm:"share"`
 	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
+	// ExpiresAt is the time after which the public dashboard can't be viewed anymore, nil means it never expires.
+	// It is stored as seconds since epoch so that it compares the same way on every database.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" xorm:"'expires_at' bigint"`
+	// Variables lists the template variables viewers may change and the values they may pick
+	Variables *AllowedVariables `json:"variables,omitempty" xorm:"variables"`
+}
+
+// IsExpired returns true if the public dashboard has an expiration time before now
+func (pd PublicDashboard) IsExpired(now time.Time) bool {
+	return pd.ExpiresAt != nil && !pd.ExpiresAt.After(now)
+}
+
+// AllowedVariable returns the allow-list entry of a template variable or nil if viewers can't change it
+func (pd PublicDashboard) AllowedVariable(name string) *AllowedVariable {
+	if pd.Variables == nil {
+		return nil
+	}
+	for i, v := range *pd.Variables {
+		if v.Name == name {
+			return &(*pd.Variables)[i]
+		}
+	}
+	return nil
 }
 
 type PublicDashboardDTO struct {
@@ -63,6 +87,32 @@ type PublicDashboardDTO struct {
 	IsEnabled            *bool     `json:"isEnabled"`
 	AnnotationsEnabled   *bool     `json:"annotationsEnabled"`
 	Share                ShareType `json:"share"`
+	// ExpiresAt sets the expiration time, a zero time removes it
+	ExpiresAt *time.Time `json:"expiresAt"`
+	// Variables replaces the template variables allow-list, an empty list removes it
+	Variables *AllowedVariables `json:"variables"`
+}
+
+// AllowedVariable is a template variable that public dashboard viewers may change
+type AllowedVariable struct {
+	Name string `json:"name"`
+	// Values the variable may be set to
+	Values []string `json:"values"`
+}
+
+// IsAllowedValue returns true if value is one of the permitted values
+func (v AllowedVariable) IsAllowedValue(value string) bool {
+	return slices.Contains(v.Values, value)
+}
+
+type AllowedVariables []AllowedVariable
+
+func (v *AllowedVariables) FromDB(data []byte) error {
+	return json.Unmarshal(data, v)
+}
+
+func (v *AllowedVariables) ToDB() ([]byte, error) {
+	return json.Marshal(v)
 }
 
 type EmailDTO struct {
@@ -154,6 +204,8 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	// Variables holds the values selected by the viewer, by template variable name
+	Variables map[string][]string
 }
 
 type AnnotationsQueryDTO struct {
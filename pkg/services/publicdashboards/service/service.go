@@ -101,9 +101,15 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 		FolderUid:              dash.FolderUID,
 		PublicDashboardEnabled: pubdash.IsEnabled,
 	}
+	if pubdash.Variables != nil {
+		for _, v := range *pubdash.Variables {
+			meta.PublicDashboardVariables = append(meta.PublicDashboardVariables, v.Name)
+		}
+	}
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
 
 	sanitizeData(dash.Data)
+	restrictAllowedVariables(dash.Data, pubdash)
 
 	return &dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}, nil
 }
@@ -162,6 +168,10 @@ func (pd *PublicDashboardServiceImpl) FindEnabledPublicDashboardAndDashboardByAc
 		return nil, nil, ErrPublicDashboardNotEnabled.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard is not enabled accessToken: %s", accessToken)
 	}
 
+	if pubdash.IsExpired(time.Now()) {
+		return nil, nil, ErrPublicDashboardExpired.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard expired accessToken: %s", accessToken)
+	}
+
 	if !pd.license.FeatureEnabled(FeaturePublicDashboardsEmailSharing) && pubdash.Share == EmailShareType {
 		return nil, nil, ErrPublicDashboardNotFound.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Dashboard not found accessToken: %s", accessToken)
 	}
@@ -197,11 +207,17 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 	}
 
 	// ensure dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dash, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
 	if err != nil {
 		return nil, err
 	}
 
+	if dto.PublicDashboard.Variables != nil {
+		if err := validateAllowedVariablesExist(dash.Data, dto.PublicDashboard.Variables); err != nil {
+			return nil, err
+		}
+	}
+
 	// validate the dashboard does not already have a public dashboard
 	existingPubdash, err := pd.FindByDashboardUid(ctx, u.OrgID, dto.DashboardUid)
 	if err != nil && !errors.Is(err, ErrPublicDashboardNotFound) {
@@ -254,11 +270,17 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 	}
 
 	// validate dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dash, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
 	if err != nil {
 		return nil, err
 	}
 
+	if dto.PublicDashboard.Variables != nil {
+		if err := validateAllowedVariablesExist(dash.Data, dto.PublicDashboard.Variables); err != nil {
+			return nil, err
+		}
+	}
+
 	// get existing public dashboard if exists
 	existingPubdash, err := pd.store.Find(ctx, dto.Uid)
 	if err != nil {
@@ -376,6 +398,23 @@ func (pd *PublicDashboardServiceImpl) Delete(ctx context.Context, uid string, da
 	return pd.serviceWrapper.Delete(ctx, uid)
 }
 
+// DeleteExpired deletes the public dashboards whose expiration time has passed
+func (pd *PublicDashboardServiceImpl) DeleteExpired(ctx context.Context) (int64, error) {
+	pubdashes, err := pd.store.FindExpired(ctx, time.Now())
+	if err != nil {
+		return 0, ErrInternalServerError.Errorf("DeleteExpired: failed to find expired public dashboards: %w", err)
+	}
+
+	var deleted int64
+	for _, pubdash := range pubdashes {
+		if err := pd.serviceWrapper.Delete(ctx, pubdash.Uid); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
 func (pd *PublicDashboardServiceImpl) DeleteByDashboard(ctx context.Context, dashboard *dashboards.Dashboard) error {
 	if dashboard.IsFolder {
 		// get all pubdashes for the folder
@@ -519,6 +558,8 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            now,
 		AccessToken:          accessToken,
+		ExpiresAt:            expiresAtOrDefault(dto.PublicDashboard.ExpiresAt, nil),
+		Variables:            variablesOrDefault(dto.PublicDashboard.Variables, nil),
 	}, nil
 }
 
@@ -542,6 +583,8 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		Share:                share,
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            time.Now(),
+		ExpiresAt:            expiresAtOrDefault(pubdashDTO.ExpiresAt, pd.ExpiresAt),
+		Variables:            variablesOrDefault(pubdashDTO.Variables, pd.Variables),
 	}
 }
 
@@ -552,3 +595,25 @@ func returnValueOrDefault(value *bool, defaultValue bool) bool {
 
 	return defaultValue
 }
+
+// expiresAtOrDefault returns the expiration time of the DTO, a zero time removes the expiration
+func expiresAtOrDefault(value *time.Time, defaultValue *time.Time) *time.Time {
+	if value == nil {
+		return defaultValue
+	}
+	if value.IsZero() {
+		return nil
+	}
+	return value
+}
+
+// variablesOrDefault returns the allow-list of the DTO, an empty list removes it
+func variablesOrDefault(value *AllowedVariables, defaultValue *AllowedVariables) *AllowedVariables {
+	if value == nil {
+		return defaultValue
+	}
+	if len(*value) == 0 {
+		return nil
+	}
+	return value
+}
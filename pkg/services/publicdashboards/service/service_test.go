@@ -379,6 +379,29 @@ func TestGetPublicDashboardForView(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:        "returns the variables viewers may change",
+			AccessToken: accessToken,
+			StoreResp: &storeResp{
+				pd:  &PublicDashboard{AccessToken: accessToken, IsEnabled: true, Variables: &AllowedVariables{{Name: "job", Values: []string{"grafana"}}}},
+				d:   d,
+				err: nil,
+			},
+			ErrResp: nil,
+			DashResp: &dtos.DashboardFullWithMeta{
+				Dashboard: data,
+				Meta: dtos.DashboardMeta{
+					Slug:                     d.Slug,
+					Type:                     dashboards.DashTypeDB,
+					Created:                  d.Created,
+					Updated:                  d.Updated,
+					Version:                  d.Version,
+					FolderUid:                d.FolderUID,
+					PublicDashboardEnabled:   true,
+					PublicDashboardVariables: []string{"job"},
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -412,6 +435,7 @@ func TestGetPublicDashboardForView(t *testing.T) {
 				assert.Equal(t, false, dashboardFullWithMeta.Meta.IsFolder)
 				assert.Equal(t, test.DashResp.Meta.FolderUid, dashboardFullWithMeta.Meta.FolderUid)
 				assert.Equal(t, test.DashResp.Meta.PublicDashboardEnabled, dashboardFullWithMeta.Meta.PublicDashboardEnabled)
+				assert.Equal(t, test.DashResp.Meta.PublicDashboardVariables, dashboardFullWithMeta.Meta.PublicDashboardVariables)
 
 				// hide the timepicker if the time selection is disabled
 				assert.Equal(t, test.StoreResp.pd.TimeSelectionEnabled, !dashboardFullWithMeta.Dashboard.Get("timepicker").Get("hidden").MustBool())
@@ -551,6 +575,17 @@ func TestGetEnabledPublicDashboard(t *testing.T) {
 			ErrResp:  ErrPublicDashboardNotFound,
 			DashResp: nil,
 		},
+		{
+			Name:        "returns ErrPublicDashboardExpired when expiration time has passed",
+			AccessToken: "abc123",
+			StoreResp: &storeResp{
+				pd:  &PublicDashboard{AccessToken: "abcdToken", IsEnabled: true, ExpiresAt: util.Pointer(time.Now().Add(-time.Minute))},
+				d:   &dashboards.Dashboard{UID: "mydashboard"},
+				err: nil,
+			},
+			ErrResp:  ErrPublicDashboardExpired,
+			DashResp: nil,
+		},
 	}
 
 	for _, test := range testCases {
@@ -1666,6 +1701,20 @@ func TestDeleteByDashboard(t *testing.T) {
 	})
 }
 
+func TestDeleteExpired(t *testing.T) {
+	store := NewFakePublicDashboardStore(t)
+	pd := &PublicDashboardServiceImpl{store: store, serviceWrapper: ProvideServiceWrapper(store)}
+	pubdash1 := &PublicDashboard{Uid: "2", OrgId: 1, DashboardUid: "1"}
+	pubdash2 := &PublicDashboard{Uid: "3", OrgId: 1, DashboardUid: "4"}
+	store.On("FindExpired", mock.Anything, mock.Anything).Return([]*PublicDashboard{pubdash1, pubdash2}, nil)
+	store.On("Delete", mock.Anything, mock.Anything).Return(int64(1), nil)
+
+	deleted, err := pd.DeleteExpired(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	store.AssertNumberOfCalls(t, "Delete", 2)
+}
+
 func TestGenerateAccessToken(t *testing.T) {
 	accessToken, err := GenerateAccessToken()
 
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+// variableRegex matches $name, [[name]], [[name:format]], ${name} and ${name:format}
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?::(\w+))?\}`)
+
+type dashboardVariable struct {
+	multi   bool
+	current []string
+}
+
+// getDashboardVariables returns the template variables of a dashboard by name
+func getDashboardVariables(dashboard *simplejson.Json) map[string]dashboardVariable {
+	variables := make(map[string]dashboardVariable)
+	for _, variableObj := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		name := variable.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+
+		var current []string
+		value := variable.GetPath("current", "value")
+		if values, err := value.StringArray(); err == nil {
+			current = values
+		} else if s, err := value.String(); err == nil {
+			current = []string{s}
+		}
+
+		variables[name] = dashboardVariable{
+			multi:   variable.Get("multi").MustBool(),
+			current: current,
+		}
+	}
+	return variables
+}
+
+// validateAllowedVariablesExist checks that every variable of the allow-list is a template variable of the dashboard
+func validateAllowedVariablesExist(dashboard *simplejson.Json, allowed *models.AllowedVariables) error {
+	variables := getDashboardVariables(dashboard)
+	for _, v := range *allowed {
+		if _, ok := variables[v.Name]; !ok {
+			return models.ErrInvalidVariables.Errorf("validateAllowedVariablesExist: dashboard has no variable %s", v.Name)
+		}
+	}
+	return nil
+}
+
+// resolveVariables returns the values of the allow-listed template variables. Values selected by the viewer are
+// validated against the allow-list, other variables keep the dashboard value when it's allowed or fall back to the
+// first allowed value.
+func resolveVariables(dashboard *simplejson.Json, publicDashboard *models.PublicDashboard, selected map[string][]string) (map[string][]string, error) {
+	for name, values := range selected {
+		allowed := publicDashboard.AllowedVariable(name)
+		if allowed == nil {
+			return nil, models.ErrInvalidVariableValue.Errorf("resolveVariables: variable %s can't be changed", name)
+		}
+		for _, value := range values {
+			if !allowed.IsAllowedValue(value) {
+				return nil, models.ErrInvalidVariableValue.Errorf("resolveVariables: value %q is not allowed for variable %s", value, name)
+			}
+		}
+	}
+
+	if publicDashboard.Variables == nil {
+		return nil, nil
+	}
+
+	variables := getDashboardVariables(dashboard)
+	resolved := make(map[string][]string, len(*publicDashboard.Variables))
+	for _, allowed := range *publicDashboard.Variables {
+		variable := variables[allowed.Name]
+
+		values, ok := selected[allowed.Name]
+		if ok && len(values) > 1 && !variable.multi {
+			return nil, models.ErrInvalidVariableValue.Errorf("resolveVariables: variable %s accepts a single value", allowed.Name)
+		}
+
+		if len(values) == 0 {
+			for _, value := range variable.current {
+				if allowed.IsAllowedValue(value) {
+					values = append(values, value)
+				}
+			}
+		}
+		if len(values) == 0 {
+			values = allowed.Values[:1]
+		}
+		resolved[allowed.Name] = values
+	}
+	return resolved, nil
+}
+
+// interpolateVariables replaces the variables in every string of a query, except its datasource and refId
+func interpolateVariables(query *simplejson.Json, values map[string][]string) {
+	if len(values) == 0 {
+		return
+	}
+	for key, value := range query.MustMap() {
+		if key == "datasource" || key == "refId" {
+			continue
+		}
+		query.Set(key, interpolateValue(value, values))
+	}
+}
+
+func interpolateValue(value any, values map[string][]string) any {
+	switch v := value.(type) {
+	case string:
+		return interpolateString(v, values)
+	case []any:
+		for i := range v {
+			v[i] = interpolateValue(v[i], values)
+		}
+		return v
+	case map[string]any:
+		for k := range v {
+			v[k] = interpolateValue(v[k], values)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func interpolateString(s string, values map[string][]string) string {
+	return variableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		name, format := groups[1], ""
+		switch {
+		case groups[2] != "":
+			name, format = groups[2], groups[3]
+		case groups[4] != "":
+			name, format = groups[4], groups[5]
+		}
+
+		value, ok := values[name]
+		if !ok {
+			return match
+		}
+		return formatVariableValue(value, format)
+	})
+}
+
+// formatVariableValue supports a subset of the formats available in the frontend, unknown formats use the default one
+func formatVariableValue(values []string, format string) string {
+	if len(values) == 1 && format != "json" && format != "singlequote" && format != "doublequote" && format != "sqlstring" {
+		if format == "regex" {
+			return regexp.QuoteMeta(values[0])
+		}
+		return values[0]
+	}
+
+	switch format {
+	case "csv", "raw":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "regex":
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			quoted[i] = regexp.QuoteMeta(v)
+		}
+		return "(" + strings.Join(quoted, "|") + ")"
+	case "singlequote":
+		return joinQuoted(values, "'", `\'`)
+	case "doublequote":
+		return joinQuoted(values, `"`, `\"`)
+	case "sqlstring":
+		return joinQuoted(values, "'", "''")
+	case "json":
+		b, _ := json.Marshal(values)
+		return string(b)
+	default:
+		return "{" + strings.Join(values, ",") + "}"
+	}
+}
+
+func joinQuoted(values []string, quote string, escaped string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%s%s%s", quote, strings.ReplaceAll(v, quote, escaped), quote)
+	}
+	return strings.Join(quoted, ",")
+}
+
+// restrictAllowedVariables turns the allow-listed variables of the dashboard into custom variables offering only the
+// allowed values, so viewers can't see or run the original variable queries.
+func restrictAllowedVariables(dashboard *simplejson.Json, publicDashboard *models.PublicDashboard) {
+	if publicDashboard.Variables == nil {
+		return
+	}
+	variables := getDashboardVariables(dashboard)
+	for _, variableObj := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		allowed := publicDashboard.AllowedVariable(variable.Get("name").MustString())
+		if allowed == nil {
+			continue
+		}
+
+		resolved, _ := resolveVariables(dashboard, &models.PublicDashboard{Variables: &models.AllowedVariables{*allowed}}, nil)
+		current := resolved[allowed.Name]
+		options := make([]any, 0, len(allowed.Values))
+		for _, value := range allowed.Values {
+			options = append(options, map[string]any{
+				"text":     value,
+				"value":    value,
+				"selected": slices.Contains(current, value),
+			})
+		}
+
+		var currentValue any = toAnySlice(current)
+		if !variables[allowed.Name].multi {
+			currentValue = current[0]
+		}
+
+		variable.Set("type", "custom")
+		variable.Set("query", strings.Join(allowed.Values, ","))
+		variable.Set("options", options)
+		variable.Set("current", map[string]any{"text": currentValue, "value": currentValue})
+		variable.Set("includeAll", false)
+		variable.Del("definition")
+		variable.Del("datasource")
+		variable.Del("regex")
+	}
+}
+
+func toAnySlice(values []string) []any {
+	result := make([]any, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+func variablesDashboard() *simplejson.Json {
+	return simplejson.NewFromAny(map[string]any{
+		"templating": map[string]any{
+			"list": []any{
+				map[string]any{
+					"name":    "customer",
+					"type":    "query",
+					"query":   "label_values(customer)",
+					"current": map[string]any{"text": "acme", "value": "acme"},
+				},
+				map[string]any{
+					"name":    "region",
+					"type":    "custom",
+					"multi":   true,
+					"current": map[string]any{"text": []any{"eu", "us"}, "value": []any{"eu", "us"}},
+				},
+				map[string]any{
+					"name":    "env",
+					"type":    "constant",
+					"current": map[string]any{"text": "prod", "value": "prod"},
+				},
+			},
+		},
+	})
+}
+
+func variablesPublicDashboard() *PublicDashboard {
+	return &PublicDashboard{Variables: &AllowedVariables{
+		{Name: "customer", Values: []string{"acme", "globex"}},
+		{Name: "region", Values: []string{"eu", "us", "apac"}},
+	}}
+}
+
+func TestResolveVariables(t *testing.T) {
+	t.Run("uses the dashboard values when nothing is selected", func(t *testing.T) {
+		values, err := resolveVariables(variablesDashboard(), variablesPublicDashboard(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{"customer": {"acme"}, "region": {"eu", "us"}}, values)
+	})
+
+	t.Run("falls back to the first allowed value", func(t *testing.T) {
+		pubdash := &PublicDashboard{Variables: &AllowedVariables{{Name: "customer", Values: []string{"globex", "initech"}}}}
+		values, err := resolveVariables(variablesDashboard(), pubdash, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{"customer": {"globex"}}, values)
+	})
+
+	t.Run("uses the selected values", func(t *testing.T) {
+		values, err := resolveVariables(variablesDashboard(), variablesPublicDashboard(), map[string][]string{
+			"customer": {"globex"},
+			"region":   {"apac", "eu"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{"customer": {"globex"}, "region": {"apac", "eu"}}, values)
+	})
+
+	t.Run("rejects variables that are not allowed", func(t *testing.T) {
+		_, err := resolveVariables(variablesDashboard(), variablesPublicDashboard(), map[string][]string{"env": {"dev"}})
+		require.ErrorIs(t, err, ErrInvalidVariableValue)
+	})
+
+	t.Run("rejects values that are not allowed", func(t *testing.T) {
+		_, err := resolveVariables(variablesDashboard(), variablesPublicDashboard(), map[string][]string{"customer": {"initech"}})
+		require.ErrorIs(t, err, ErrInvalidVariableValue)
+	})
+
+	t.Run("rejects several values for a single value variable", func(t *testing.T) {
+		_, err := resolveVariables(variablesDashboard(), variablesPublicDashboard(), map[string][]string{"customer": {"acme", "globex"}})
+		require.ErrorIs(t, err, ErrInvalidVariableValue)
+	})
+
+	t.Run("rejects any variable when there is no allow-list", func(t *testing.T) {
+		_, err := resolveVariables(variablesDashboard(), &PublicDashboard{}, map[string][]string{"customer": {"acme"}})
+		require.ErrorIs(t, err, ErrInvalidVariableValue)
+	})
+}
+
+func TestInterpolateVariables(t *testing.T) {
+	query := simplejson.NewFromAny(map[string]any{
+		"refId":      "A",
+		"datasource": map[string]any{"uid": "$customer"},
+		"expr":       `up{customer="$customer", region=~"${region:regex}", env="$env"}[$__interval]`,
+		"rawSql":     "SELECT * FROM t WHERE region IN (${region:sqlstring}) AND customer = '[[customer]]'",
+		"nested":     map[string]any{"values": []any{"${region:csv}", 1}},
+	})
+
+	interpolateVariables(query, map[string][]string{"customer": {"acme"}, "region": {"eu", "us"}})
+
+	assert.Equal(t, "A", query.Get("refId").MustString())
+	assert.Equal(t, "$customer", query.GetPath("datasource", "uid").MustString())
+	assert.Equal(t, `up{customer="acme", region=~"(eu|us)", env="$env"}[$__interval]`, query.Get("expr").MustString())
+	assert.Equal(t, "SELECT * FROM t WHERE region IN ('eu','us') AND customer = 'acme'", query.Get("rawSql").MustString())
+	assert.Equal(t, "eu,us", query.GetPath("nested", "values").GetIndex(0).MustString())
+}
+
+func TestValidateAllowedVariablesExist(t *testing.T) {
+	require.NoError(t, validateAllowedVariablesExist(variablesDashboard(), variablesPublicDashboard().Variables))
+
+	err := validateAllowedVariablesExist(variablesDashboard(), &AllowedVariables{{Name: "missing", Values: []string{"a"}}})
+	require.ErrorIs(t, err, ErrInvalidVariables)
+}
+
+func TestRestrictAllowedVariables(t *testing.T) {
+	dashboard := variablesDashboard()
+	restrictAllowedVariables(dashboard, variablesPublicDashboard())
+
+	customer := dashboard.GetPath("templating", "list").GetIndex(0)
+	assert.Equal(t, "custom", customer.Get("type").MustString())
+	assert.Equal(t, "acme,globex", customer.Get("query").MustString())
+	assert.Equal(t, "acme", customer.GetPath("current", "value").MustString())
+	assert.Len(t, customer.Get("options").MustArray(), 2)
+
+	region := dashboard.GetPath("templating", "list").GetIndex(1)
+	assert.Equal(t, []string{"eu", "us"}, region.GetPath("current", "value").MustStringArray())
+
+	env := dashboard.GetPath("templating", "list").GetIndex(2)
+	assert.Equal(t, "constant", env.Get("type").MustString())
+}
@@ -1,6 +1,8 @@
 package validation
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
@@ -13,6 +15,36 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 		return ErrInvalidShareType.Errorf("ValidateSavePublicDashboard: invalid share type")
 	}
 
+	// a zero time removes the expiration
+	if expiresAt := dto.PublicDashboard.ExpiresAt; expiresAt != nil && !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return ErrInvalidExpiration.Errorf("ValidateSavePublicDashboard: expiration time is in the past")
+	}
+
+	if dto.PublicDashboard.Variables != nil {
+		if err := ValidateAllowedVariables(*dto.PublicDashboard.Variables); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateAllowedVariables checks that every variable of the allow-list has a unique name and at least one value
+func ValidateAllowedVariables(variables AllowedVariables) error {
+	names := make(map[string]bool, len(variables))
+	for _, v := range variables {
+		if v.Name == "" {
+			return ErrInvalidVariables.Errorf("ValidateAllowedVariables: variable name is empty")
+		}
+		if names[v.Name] {
+			return ErrInvalidVariables.Errorf("ValidateAllowedVariables: variable %s is listed more than once", v.Name)
+		}
+		names[v.Name] = true
+
+		if len(v.Values) == 0 {
+			return ErrInvalidVariables.Errorf("ValidateAllowedVariables: variable %s has no allowed values", v.Name)
+		}
+	}
 	return nil
 }
 
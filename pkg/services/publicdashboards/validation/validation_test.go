@@ -2,6 +2,7 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/stretchr/testify/assert"
@@ -22,6 +23,34 @@ func TestValidatePublicDashboard(t *testing.T) {
 		err := ValidatePublicDashboard(dto)
 		require.Error(t, err)
 	})
+
+	t.Run("Returns error when expiration time is in the past", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Hour)
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{ExpiresAt: &expiresAt}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidExpiration)
+	})
+
+	t.Run("Returns no error when expiration time is zero", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{ExpiresAt: &time.Time{}}}
+
+		err := ValidatePublicDashboard(dto)
+		require.NoError(t, err)
+	})
+
+	t.Run("Returns error when allowed variables are invalid", func(t *testing.T) {
+		for _, variables := range []AllowedVariables{
+			{{Name: "", Values: []string{"a"}}},
+			{{Name: "host", Values: []string{}}},
+			{{Name: "host", Values: []string{"a"}}, {Name: "host", Values: []string{"b"}}},
+		} {
+			dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{Variables: &variables}}
+
+			err := ValidatePublicDashboard(dto)
+			require.ErrorIs(t, err, ErrInvalidVariables)
+		}
+	})
 }
 
 func TestValidateQueryPublicDashboardRequest(t *testing.T) {
@@ -0,0 +1,78 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+func writeCSV(w io.Writer, frame *data.Frame) error {
+	cw := csv.NewWriter(w)
+
+	record := make([]string, len(frame.Fields))
+	for i, field := range frame.Fields {
+		record[i] = fieldName(field)
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	rows, err := frame.RowLen()
+	if err != nil {
+		return err
+	}
+	for row := 0; row < rows; row++ {
+		for i, field := range frame.Fields {
+			record[i] = formatValue(field, row)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatValue returns the text representation of a value, null values are
+// returned as an empty string.
+func formatValue(field *data.Field, row int) string {
+	v, ok := field.ConcreteAt(row)
+	if !ok {
+		return ""
+	}
+	switch v := v.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case float64:
+		return formatFloat(v, 64)
+	case float32:
+		return formatFloat(float64(v), 32)
+	case bool:
+		return strconv.FormatBool(v)
+	case json.RawMessage:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func formatFloat(v float64, bitSize int) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'f', -1, bitSize)
+}
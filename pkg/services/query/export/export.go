@@ -0,0 +1,233 @@
+// Package export writes data frames returned by the query service to files
+// that can be downloaded from the /api/dashboards/uid/:uid/panels/:panelId/query/export endpoint.
+package export
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatParquet Format = "parquet"
+	FormatXLSX    Format = "xlsx"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown export format")
+	ErrTooManyRows   = errors.New("too many rows to export")
+	ErrTooLarge      = errors.New("export exceeds the maximum size")
+)
+
+// ParseFormat returns the export format matching s. An empty string
+// defaults to CSV.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatParquet:
+		return FormatParquet, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+}
+
+// ContentType returns the content type of an export of frameCount frames.
+func (f Format) ContentType(frameCount int) string {
+	if f.isArchive(frameCount) {
+		return "application/zip"
+	}
+	switch f {
+	case FormatParquet:
+		return "application/vnd.apache.parquet"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// FileName returns the file name of an export of frameCount frames.
+func (f Format) FileName(base string, frameCount int) string {
+	if f.isArchive(frameCount) {
+		return base + ".zip"
+	}
+	return base + "." + string(f)
+}
+
+// CSV and Parquet files hold a single frame, several frames are bundled in
+// a zip archive with one file per frame. XLSX uses one sheet per frame.
+func (f Format) isArchive(frameCount int) bool {
+	return f != FormatXLSX && frameCount > 1
+}
+
+// Frames returns the frames of a query response in the order of refIDs,
+// followed by the frames of any other response sorted by refID. It fails
+// if one of the responses contains an error.
+func Frames(resp *backend.QueryDataResponse, refIDs []string) (data.Frames, error) {
+	ordered := make([]string, 0, len(resp.Responses))
+	seen := make(map[string]bool, len(resp.Responses))
+	for _, refID := range refIDs {
+		if _, ok := resp.Responses[refID]; ok && !seen[refID] {
+			ordered = append(ordered, refID)
+			seen[refID] = true
+		}
+	}
+	rest := make([]string, 0)
+	for refID := range resp.Responses {
+		if !seen[refID] {
+			rest = append(rest, refID)
+		}
+	}
+	sort.Strings(rest)
+	ordered = append(ordered, rest...)
+
+	frames := data.Frames{}
+	for _, refID := range ordered {
+		res := resp.Responses[refID]
+		if res.Error != nil {
+			return nil, fmt.Errorf("query %s failed: %w", refID, res.Error)
+		}
+		for _, frame := range res.Frames {
+			if frame == nil || len(frame.Fields) == 0 {
+				continue
+			}
+			if frame.RefID == "" {
+				frame.RefID = refID
+			}
+			frames = append(frames, frame)
+		}
+	}
+	return frames, nil
+}
+
+// CheckRows returns ErrTooManyRows if the frames hold more than maxRows rows.
+// A maxRows lower than 1 disables the check.
+func CheckRows(frames data.Frames, maxRows int64) error {
+	if maxRows < 1 {
+		return nil
+	}
+	var rows int64
+	for _, frame := range frames {
+		rows += int64(frame.Rows())
+		if rows > maxRows {
+			return fmt.Errorf("%w: the limit is %d", ErrTooManyRows, maxRows)
+		}
+	}
+	return nil
+}
+
+// Write writes frames to w in the given format. It returns ErrTooLarge once
+// more than maxBytes bytes have been written. A maxBytes lower than 1
+// disables the limit.
+func Write(w io.Writer, format Format, frames data.Frames, maxBytes int64) error {
+	if maxBytes > 0 {
+		w = &limitedWriter{w: w, remaining: maxBytes}
+	}
+
+	switch format {
+	case FormatXLSX:
+		return writeXLSX(w, frames)
+	case FormatCSV, FormatParquet:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+
+	writeFrame := writeCSV
+	if format == FormatParquet {
+		writeFrame = writeParquet
+	}
+	if !format.isArchive(len(frames)) {
+		if len(frames) == 0 {
+			return writeFrame(w, data.NewFrame(""))
+		}
+		return writeFrame(w, frames[0])
+	}
+
+	zw := zip.NewWriter(w)
+	names := newUniqueNames()
+	for i, frame := range frames {
+		fw, err := zw.Create(names.get(frameName(frame, i)) + "." + string(format))
+		if err != nil {
+			return err
+		}
+		if err := writeFrame(fw, frame); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// frameName returns a name usable in a file or sheet name.
+func frameName(frame *data.Frame, idx int) string {
+	name := frame.Name
+	if name == "" {
+		name = frame.RefID
+	}
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', '[', ']':
+			return '_'
+		}
+		if r < 0x20 {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = fmt.Sprintf("frame_%d", idx+1)
+	}
+	return name
+}
+
+// fieldName returns the column header of a field, including its labels.
+func fieldName(field *data.Field) string {
+	if field.Config != nil && field.Config.DisplayNameFromDS != "" {
+		return field.Config.DisplayNameFromDS
+	}
+	if len(field.Labels) == 0 {
+		return field.Name
+	}
+	return field.Name + " " + field.Labels.String()
+}
+
+type uniqueNames struct {
+	used map[string]bool
+}
+
+func newUniqueNames() *uniqueNames {
+	return &uniqueNames{used: map[string]bool{}}
+}
+
+func (u *uniqueNames) get(name string) string {
+	candidate := name
+	for i := 2; u.used[strings.ToLower(candidate)]; i++ {
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	u.used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+type limitedWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		return 0, ErrTooLarge
+	}
+	n, err := l.w.Write(p)
+	l.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func testFrame(name string) *data.Frame {
+	return data.NewFrame(name,
+		data.NewField("time", nil, []time.Time{time.Unix(0, 0), time.Unix(60, 0)}),
+		data.NewField("value", data.Labels{"host": "a"}, []*float64{pointer(1.5), nil}),
+		data.NewField("text", nil, []string{"a,b", "<c>"}),
+	)
+}
+
+func pointer[T any](v T) *T {
+	return &v
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, expected := range map[string]Format{"": FormatCSV, "CSV": FormatCSV, "parquet": FormatParquet, "xlsx": FormatXLSX} {
+		f, err := ParseFormat(in)
+		require.NoError(t, err)
+		require.Equal(t, expected, f)
+	}
+
+	_, err := ParseFormat("pdf")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestFrames(t *testing.T) {
+	resp := &backend.QueryDataResponse{Responses: backend.Responses{
+		"C": {Frames: data.Frames{data.NewFrame("c", data.NewField("v", nil, []int64{1}))}},
+		"A": {Frames: data.Frames{data.NewFrame("a", data.NewField("v", nil, []int64{1})), data.NewFrame("empty")}},
+		"B": {Frames: data.Frames{data.NewFrame("b", data.NewField("v", nil, []int64{1}))}},
+	}}
+
+	frames, err := Frames(resp, []string{"B", "A"})
+	require.NoError(t, err)
+	require.Len(t, frames, 3)
+	require.Equal(t, "b", frames[0].Name)
+	require.Equal(t, "a", frames[1].Name)
+	require.Equal(t, "A", frames[1].RefID)
+	require.Equal(t, "c", frames[2].Name)
+
+	resp.Responses["B"] = backend.DataResponse{Error: errors.New("boom")}
+	_, err = Frames(resp, []string{"B", "A"})
+	require.ErrorContains(t, err, "query B failed")
+}
+
+func TestCheckRows(t *testing.T) {
+	frames := data.Frames{testFrame("a"), testFrame("b")}
+	require.NoError(t, CheckRows(frames, 4))
+	require.ErrorIs(t, CheckRows(frames, 3), ErrTooManyRows)
+	require.NoError(t, CheckRows(frames, 0))
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatCSV, data.Frames{testFrame("a")}, 0))
+	require.Equal(t, "time,value {host=a},text\n"+
+		"1970-01-01T00:00:00Z,1.5,\"a,b\"\n"+
+		"1970-01-01T00:01:00Z,,<c>\n", buf.String())
+}
+
+func TestWriteArchive(t *testing.T) {
+	for _, format := range []Format{FormatCSV, FormatParquet} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Write(&buf, format, data.Frames{testFrame("a"), testFrame("a")}, 0))
+
+			zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			require.NoError(t, err)
+			require.Len(t, zr.File, 2)
+			require.Equal(t, "a."+string(format), zr.File[0].Name)
+			require.Equal(t, "a_2."+string(format), zr.File[1].Name)
+		})
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatParquet, data.Frames{testFrame("a")}, 0))
+	require.Equal(t, "PAR1", buf.String()[:4])
+	require.Equal(t, "PAR1", buf.String()[buf.Len()-4:])
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatXLSX, data.Frames{testFrame("a"), testFrame("b")}, 0))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		files[f.Name] = string(b)
+	}
+	require.Contains(t, files, "[Content_Types].xml")
+	require.Contains(t, files["xl/workbook.xml"], `<sheet name="a" sheetId="1" r:id="rId1"/>`)
+	require.Contains(t, files["xl/workbook.xml"], `<sheet name="b" sheetId="2" r:id="rId2"/>`)
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	require.Contains(t, sheet, `<c r="B2"><v>1.5</v></c>`)
+	require.Contains(t, sheet, `<c r="A3" s="1"><v>25569.00069444444`)
+	require.Contains(t, sheet, `<t xml:space="preserve">&lt;c&gt;</t>`)
+	require.NotContains(t, sheet, `r="B3"`)
+}
+
+func TestWriteMaxBytes(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, FormatCSV, data.Frames{testFrame("a")}, 10)
+	require.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestCellRef(t *testing.T) {
+	require.Equal(t, "A1", cellRef(0, 1))
+	require.Equal(t, "Z2", cellRef(25, 2))
+	require.Equal(t, "AA3", cellRef(26, 3))
+	require.Equal(t, "XFD4", cellRef(16383, 4))
+}
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+var (
+	ErrPanelNotFound   = errors.New("panel not found")
+	ErrQueryNotInPanel = errors.New("query is not part of the panel")
+)
+
+// FindPanel returns the panel with the given id, looking into collapsed rows as well.
+func FindPanel(dashboard *simplejson.Json, panelID int64) (*simplejson.Json, error) {
+	panel, ok := findPanel(dashboard.Get("panels").MustArray(), panelID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrPanelNotFound, panelID)
+	}
+	return panel, nil
+}
+
+// LibraryPanelUID returns the uid of the library panel a dashboard panel refers to,
+// an empty string when the panel is not a library panel. The queries of a library
+// panel are stored with the library panel, not in the dashboard.
+func LibraryPanelUID(panel *simplejson.Json) string {
+	return panel.Get("libraryPanel").Get("uid").MustString()
+}
+
+// PanelQueries returns the queries of a panel. Queries without data source get the
+// one of the panel.
+func PanelQueries(panel *simplejson.Json) []*simplejson.Json {
+	targets := panel.Get("targets").MustArray()
+	queries := make([]*simplejson.Json, 0, len(targets))
+	for _, target := range targets {
+		query := simplejson.NewFromAny(target)
+		if _, ok := query.CheckGet("datasource"); !ok {
+			query.Set("datasource", panel.Get("datasource").Interface())
+		}
+		queries = append(queries, query)
+	}
+	return queries
+}
+
+func findPanel(panels []any, panelID int64) (*simplejson.Json, bool) {
+	for _, p := range panels {
+		panel := simplejson.NewFromAny(p)
+		if panel.Get("id").MustInt64() == panelID && panel.Get("type").MustString() != "row" {
+			return panel, true
+		}
+		if panel.Get("type").MustString() == "row" {
+			if found, ok := findPanel(panel.Get("panels").MustArray(), panelID); ok {
+				return found, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// SelectQueries returns the queries to run for the export of a panel. Without
+// requested queries, the queries of the panel are used as they are stored, hidden
+// queries are skipped unless the panel has expressions which may rely on them.
+// Requested queries, such as queries with interpolated variables, must match a
+// query of the panel by refId and data source.
+func SelectQueries(panelQueries, requested []*simplejson.Json) ([]*simplejson.Json, error) {
+	if len(requested) == 0 {
+		hasExpression := false
+		for _, q := range panelQueries {
+			if expr.NodeTypeFromDatasourceUID(datasourceUID(q)) == expr.TypeCMDNode {
+				hasExpression = true
+			}
+		}
+		queries := make([]*simplejson.Json, 0, len(panelQueries))
+		for _, q := range panelQueries {
+			if !hasExpression && q.Get("hide").MustBool() {
+				continue
+			}
+			queries = append(queries, q)
+		}
+		return queries, nil
+	}
+
+	byRefID := make(map[string]*simplejson.Json, len(panelQueries))
+	for _, q := range panelQueries {
+		byRefID[q.Get("refId").MustString("A")] = q
+	}
+	for _, q := range requested {
+		refID := q.Get("refId").MustString("A")
+		panelQuery, ok := byRefID[refID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrQueryNotInPanel, refID)
+		}
+		// Data sources selected with a variable are interpolated by the client
+		panelUID := datasourceUID(panelQuery)
+		if uid := datasourceUID(q); uid != "" && uid != panelUID && !strings.HasPrefix(panelUID, "$") {
+			return nil, fmt.Errorf("%w: %s uses another data source", ErrQueryNotInPanel, refID)
+		}
+		if _, ok := q.CheckGet("datasource"); !ok {
+			q.Set("datasource", panelQuery.Get("datasource").Interface())
+		}
+	}
+	return requested, nil
+}
+
+func datasourceUID(query *simplejson.Json) string {
+	uid := query.Get("datasource").Get("uid").MustString()
+	// before 8.3 special types could be sent as datasource (expr)
+	if uid == "" {
+		uid = query.Get("datasource").MustString()
+	}
+	return uid
+}
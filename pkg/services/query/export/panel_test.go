@@ -0,0 +1,118 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const testDashboard = `{
+	"panels": [
+		{
+			"id": 1,
+			"datasource": { "type": "prometheus", "uid": "prom" },
+			"targets": [
+				{ "refId": "A", "expr": "up" },
+				{ "refId": "B", "expr": "down", "hide": true }
+			]
+		},
+		{
+			"id": 2,
+			"type": "row",
+			"collapsed": true,
+			"panels": [
+				{
+					"id": 3,
+					"datasource": { "type": "prometheus", "uid": "$ds" },
+					"targets": [
+						{ "refId": "A", "expr": "up" },
+						{ "refId": "B", "hide": true, "datasource": { "type": "__expr__", "uid": "__expr__" }, "type": "math", "expression": "$A * 2" }
+					]
+				}
+			]
+		},
+		{
+			"id": 4,
+			"libraryPanel": { "uid": "lib", "name": "Library panel" }
+		}
+	]
+}`
+
+func TestPanelQueries(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(testDashboard))
+	require.NoError(t, err)
+
+	panel, err := FindPanel(dashboard, 3)
+	require.NoError(t, err)
+	queries := PanelQueries(panel)
+	require.Len(t, queries, 2)
+	require.Equal(t, "$ds", queries[0].Get("datasource").Get("uid").MustString())
+	require.Equal(t, "__expr__", queries[1].Get("datasource").Get("uid").MustString())
+	require.Empty(t, LibraryPanelUID(panel))
+
+	_, err = FindPanel(dashboard, 2)
+	require.ErrorIs(t, err, ErrPanelNotFound)
+	_, err = FindPanel(dashboard, 5)
+	require.ErrorIs(t, err, ErrPanelNotFound)
+
+	libraryPanel, err := FindPanel(dashboard, 4)
+	require.NoError(t, err)
+	require.Equal(t, "lib", LibraryPanelUID(libraryPanel))
+	require.Empty(t, PanelQueries(libraryPanel))
+}
+
+func TestSelectQueries(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(testDashboard))
+	require.NoError(t, err)
+	panel := PanelQueries(mustFindPanel(t, dashboard, 1))
+
+	t.Run("uses the visible queries of the panel", func(t *testing.T) {
+		queries, err := SelectQueries(panel, nil)
+		require.NoError(t, err)
+		require.Len(t, queries, 1)
+		require.Equal(t, "A", queries[0].Get("refId").MustString())
+	})
+
+	t.Run("keeps hidden queries of panels with expressions", func(t *testing.T) {
+		withExpression := PanelQueries(mustFindPanel(t, dashboard, 3))
+		queries, err := SelectQueries(withExpression, nil)
+		require.NoError(t, err)
+		require.Len(t, queries, 2)
+	})
+
+	t.Run("accepts queries of the panel", func(t *testing.T) {
+		requested := []*simplejson.Json{simplejson.NewFromAny(map[string]any{"refId": "A", "expr": "up{job=\"grafana\"}"})}
+		queries, err := SelectQueries(panel, requested)
+		require.NoError(t, err)
+		require.Equal(t, "prom", queries[0].Get("datasource").Get("uid").MustString())
+	})
+
+	t.Run("rejects queries which are not part of the panel", func(t *testing.T) {
+		_, err := SelectQueries(panel, []*simplejson.Json{simplejson.NewFromAny(map[string]any{"refId": "C"})})
+		require.ErrorIs(t, err, ErrQueryNotInPanel)
+
+		_, err = SelectQueries(panel, []*simplejson.Json{simplejson.NewFromAny(map[string]any{
+			"refId":      "A",
+			"datasource": map[string]any{"uid": "other"},
+		})})
+		require.ErrorIs(t, err, ErrQueryNotInPanel)
+	})
+
+	t.Run("accepts interpolated data source variables", func(t *testing.T) {
+		withVariable := PanelQueries(mustFindPanel(t, dashboard, 3))
+		_, err := SelectQueries(withVariable, []*simplejson.Json{simplejson.NewFromAny(map[string]any{
+			"refId":      "A",
+			"datasource": map[string]any{"uid": "prom"},
+		})})
+		require.NoError(t, err)
+	})
+}
+
+func mustFindPanel(t *testing.T, dashboard *simplejson.Json, panelID int64) *simplejson.Json {
+	t.Helper()
+	panel, err := FindPanel(dashboard, panelID)
+	require.NoError(t, err)
+	return panel
+}
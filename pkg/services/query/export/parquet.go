@@ -0,0 +1,45 @@
+package export
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/apache/arrow/go/v15/arrow/ipc"
+	"github.com/apache/arrow/go/v15/parquet"
+	"github.com/apache/arrow/go/v15/parquet/compress"
+	"github.com/apache/arrow/go/v15/parquet/pqarrow"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// writeParquet converts the frame to its Arrow representation, the same one
+// used to send frames to the frontend, and writes it as a Parquet file.
+func writeParquet(w io.Writer, frame *data.Frame) error {
+	b, err := frame.MarshalArrow()
+	if err != nil {
+		return err
+	}
+
+	reader, err := ipc.NewFileReader(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	fw, err := pqarrow.NewFileWriter(reader.Schema(), w, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return err
+	}
+	for i := 0; i < reader.NumRecords(); i++ {
+		record, err := reader.Record(i)
+		if err != nil {
+			_ = fw.Close()
+			return err
+		}
+		if err := fw.Write(record); err != nil {
+			_ = fw.Close()
+			return err
+		}
+	}
+	return fw.Close()
+}
@@ -0,0 +1,212 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	// Limits of a single Excel worksheet, including the header row.
+	xlsxMaxRows    = 1048576
+	xlsxMaxColumns = 16384
+
+	// Sheet names are limited to 31 characters.
+	xlsxMaxSheetName = 31
+
+	// Style index of the date time cell format declared in xlsxStyles.
+	xlsxDateTimeStyle = 1
+)
+
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// writeXLSX writes a minimal Office Open XML workbook with one sheet per
+// frame. Strings are stored inline so no shared string table is needed.
+func writeXLSX(w io.Writer, frames data.Frames) error {
+	if len(frames) == 0 {
+		frames = data.Frames{data.NewFrame("")}
+	}
+
+	names := newUniqueNames()
+	sheetNames := make([]string, len(frames))
+	for i, frame := range frames {
+		if frame.Rows()+1 > xlsxMaxRows || len(frame.Fields) > xlsxMaxColumns {
+			return fmt.Errorf("%w: frame %d does not fit in a worksheet", ErrTooManyRows, i+1)
+		}
+		name := frameName(frame, i)
+		if utf8.RuneCountInString(name) > xlsxMaxSheetName-4 {
+			name = string([]rune(name)[:xlsxMaxSheetName-4])
+		}
+		sheetNames[i] = names.get(name)
+	}
+
+	zw := zip.NewWriter(w)
+	if err := writeZipFile(zw, "[Content_Types].xml", xlsxContentTypes(len(frames))); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "_rels/.rels", xlsxRootRels); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "xl/workbook.xml", xlsxWorkbook(sheetNames)); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "xl/_rels/workbook.xml.rels", xlsxWorkbookRels(len(frames))); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "xl/styles.xml", xlsxStyles); err != nil {
+		return err
+	}
+	for i, frame := range frames {
+		fw, err := zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if err := writeXLSXSheet(fw, frame); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeXLSXSheet(w io.Writer, frame *data.Frame) error {
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString(xml.Header)
+	_, _ = bw.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	_, _ = bw.WriteString(`<row r="1">`)
+	for col, field := range frame.Fields {
+		writeXLSXString(bw, cellRef(col, 1), fieldName(field))
+	}
+	_, _ = bw.WriteString(`</row>`)
+
+	rows, err := frame.RowLen()
+	if err != nil {
+		return err
+	}
+	for row := 0; row < rows; row++ {
+		r := row + 2
+		_, _ = fmt.Fprintf(bw, `<row r="%d">`, r)
+		for col, field := range frame.Fields {
+			v, ok := field.ConcreteAt(row)
+			if !ok {
+				continue
+			}
+			ref := cellRef(col, r)
+			switch v := v.(type) {
+			case time.Time:
+				days := v.UTC().Sub(excelEpoch).Hours() / 24
+				_, _ = fmt.Fprintf(bw, `<c r="%s" s="%d"><v>%s</v></c>`, ref, xlsxDateTimeStyle, strconv.FormatFloat(days, 'f', -1, 64))
+			case bool:
+				b := 0
+				if v {
+					b = 1
+				}
+				_, _ = fmt.Fprintf(bw, `<c r="%s" t="b"><v>%d</v></c>`, ref, b)
+			case float64:
+				writeXLSXNumber(bw, ref, v, 64)
+			case float32:
+				writeXLSXNumber(bw, ref, float64(v), 32)
+			case int8, int16, int32, int64, uint8, uint16, uint32, uint64:
+				_, _ = fmt.Fprintf(bw, `<c r="%s"><v>%d</v></c>`, ref, v)
+			default:
+				writeXLSXString(bw, ref, formatValue(field, row))
+			}
+		}
+		_, _ = bw.WriteString(`</row>`)
+	}
+
+	_, _ = bw.WriteString(`</sheetData></worksheet>`)
+	return bw.Flush()
+}
+
+func writeXLSXNumber(w *bufio.Writer, ref string, v float64, bitSize int) {
+	// NaN and infinity have no representation in a numeric cell.
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		writeXLSXString(w, ref, formatFloat(v, bitSize))
+		return
+	}
+	_, _ = fmt.Fprintf(w, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'g', -1, bitSize))
+}
+
+func writeXLSXString(w *bufio.Writer, ref string, s string) {
+	_, _ = fmt.Fprintf(w, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+	_ = xml.EscapeText(w, []byte(s))
+	_, _ = w.WriteString(`</t></is></c>`)
+}
+
+// cellRef returns the A1 reference of a cell, col is zero based and row one based.
+func cellRef(col int, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name + strconv.Itoa(row)
+}
+
+func writeZipFile(zw *zip.Writer, name string, content string) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(fw, content)
+	return err
+}
+
+func xlsxContentTypes(sheets int) string {
+	s := xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`
+	for i := 1; i <= sheets; i++ {
+		s += fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	return s + `</Types>`
+}
+
+func xlsxWorkbook(sheetNames []string) string {
+	s := xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`
+	for i, name := range sheetNames {
+		s += fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXMLAttr(name), i+1, i+1)
+	}
+	return s + `</sheets></workbook>`
+}
+
+func xlsxWorkbookRels(sheets int) string {
+	s := xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`
+	for i := 1; i <= sheets; i++ {
+		s += fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	s += fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheets+1)
+	return s + `</Relationships>`
+}
+
+func escapeXMLAttr(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss.000"/></numFmts>` +
+	`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+	`</styleSheet>`
@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/contexthandler"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/queryquota"
 	"github.com/grafana/grafana/pkg/services/validations"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/grafanads"
@@ -44,6 +45,7 @@ func ProvideService(
 	pluginRequestValidator validations.PluginRequestValidator,
 	pluginClient plugins.Client,
 	pCtxProvider *plugincontext.Provider,
+	queryQuota queryquota.Service,
 ) *ServiceImpl {
 	g := &ServiceImpl{
 		cfg:                    cfg,
@@ -52,6 +54,7 @@ func ProvideService(
 		pluginRequestValidator: pluginRequestValidator,
 		pluginClient:           pluginClient,
 		pCtxProvider:           pCtxProvider,
+		queryQuota:             queryQuota,
 		log:                    log.New("query_data"),
 		concurrentQueryLimit:   cfg.SectionWithEnvOverrides("query").Key("concurrent_query_limit").MustInt(runtime.NumCPU()),
 	}
@@ -75,6 +78,7 @@ type ServiceImpl struct {
 	pluginRequestValidator validations.PluginRequestValidator
 	pluginClient           plugins.Client
 	pCtxProvider           *plugincontext.Provider
+	queryQuota             queryquota.Service
 	log                    log.Logger
 	concurrentQueryLimit   int
 }
@@ -93,6 +97,19 @@ func (s *ServiceImpl) QueryData(ctx context.Context, user identity.Requester, sk
 		return nil, err
 	}
 
+	if err := s.queryQuota.CheckQuota(ctx, user, len(reqDTO.Queries)); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.queryData(ctx, user, skipDSCache, reqDTO, parsedReq)
+	if err != nil {
+		return nil, err
+	}
+	s.queryQuota.RecordResponse(ctx, user, resp)
+	return resp, nil
+}
+
+func (s *ServiceImpl) queryData(ctx context.Context, user identity.Requester, skipDSCache bool, reqDTO dtos.MetricRequest, parsedReq *parsedRequest) (*backend.QueryDataResponse, error) {
 	// If there are expressions, handle them and return
 	if parsedReq.hasExpression {
 		return s.handleExpressions(ctx, user, parsedReq)
@@ -0,0 +1,260 @@
+package queryquota
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	QuotaTargetSrv quota.TargetSrv = "query"
+
+	// QueriesTarget is the number of queries sent to data sources in the current minute
+	QueriesTarget quota.Target = "queries_per_minute"
+	// DataPointsTarget is the number of data points returned by data sources in the current hour
+	DataPointsTarget quota.Target = "data_points_per_hour"
+)
+
+var ErrQuotaReached = errutil.TooManyRequests("query.quota-reached", errutil.WithPublicMessage("Query quota reached, try again later"))
+
+// Service enforces the query traffic quotas. Usage is counted in memory by every Grafana instance.
+type Service interface {
+	// CheckQuota returns ErrQuotaReached when the instance, the org, one of the teams or the user reached one of
+	// the query quotas, otherwise it counts the queries.
+	CheckQuota(ctx context.Context, user identity.Requester, queries int) error
+	// RecordResponse counts the data points returned by the data sources.
+	RecordResponse(ctx context.Context, user identity.Requester, resp *backend.QueryDataResponse)
+}
+
+type scopeKey struct {
+	scope quota.Scope
+	id    int64
+}
+
+type QueryQuotaService struct {
+	quotaService quota.Service
+	teamService  team.Service
+	log          log.Logger
+	enabled      bool
+	now          func() time.Time
+
+	queries    *windowCounters
+	dataPoints *windowCounters
+}
+
+func ProvideService(cfg *setting.Cfg, quotaService quota.Service, teamService team.Service) (*QueryQuotaService, error) {
+	s := &QueryQuotaService{
+		quotaService: quotaService,
+		teamService:  teamService,
+		log:          log.New("queryquota"),
+		enabled:      cfg.Quota.Enabled,
+		now:          time.Now,
+		queries:      newWindowCounters(time.Minute),
+		dataPoints:   newWindowCounters(time.Hour),
+	}
+
+	defaultLimits, err := readQuotaConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := quotaService.RegisterQuotaReporter(&quota.NewUsageReporter{
+		TargetSrv:     QuotaTargetSrv,
+		DefaultLimits: defaultLimits,
+		Reporter:      s.Usage,
+	}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *QueryQuotaService) CheckQuota(ctx context.Context, user identity.Requester, queries int) error {
+	if !s.enabled || user == nil {
+		return nil
+	}
+
+	keys, err := s.scopeKeys(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	// the org and user quotas are checked together, then every team separately
+	params := []*quota.ScopeParameters{{OrgID: user.GetOrgID(), UserID: userID(user)}}
+	for _, key := range keys {
+		if key.scope == quota.TeamScope {
+			params = append(params, &quota.ScopeParameters{TeamID: key.id})
+		}
+	}
+	for _, p := range params {
+		reached, err := s.quotaService.CheckQuotaReached(ctx, QuotaTargetSrv, p)
+		if err != nil {
+			return err
+		}
+		if reached {
+			return ErrQuotaReached.Errorf("query quota reached")
+		}
+	}
+
+	s.queries.add(keys, int64(queries), s.now())
+	return nil
+}
+
+func (s *QueryQuotaService) RecordResponse(ctx context.Context, user identity.Requester, resp *backend.QueryDataResponse) {
+	if !s.enabled || user == nil || resp == nil {
+		return
+	}
+
+	points := countDataPoints(resp)
+	if points == 0 {
+		return
+	}
+
+	keys, err := s.scopeKeys(ctx, user)
+	if err != nil {
+		s.log.Warn("Failed to record data points", "error", err)
+		return
+	}
+	s.dataPoints.add(keys, points, s.now())
+}
+
+// Usage reports the usage of the current windows for the scopes of the parameters
+func (s *QueryQuotaService) Usage(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	u := &quota.Map{}
+	now := s.now()
+
+	keys := []scopeKey{{scope: quota.GlobalScope}}
+	if scopeParams != nil {
+		if scopeParams.OrgID != 0 {
+			keys = append(keys, scopeKey{scope: quota.OrgScope, id: scopeParams.OrgID})
+		}
+		if scopeParams.UserID != 0 {
+			keys = append(keys, scopeKey{scope: quota.UserScope, id: scopeParams.UserID})
+		}
+		if scopeParams.TeamID != 0 {
+			keys = append(keys, scopeKey{scope: quota.TeamScope, id: scopeParams.TeamID})
+		}
+	}
+
+	for _, key := range keys {
+		for target, counters := range map[quota.Target]*windowCounters{QueriesTarget: s.queries, DataPointsTarget: s.dataPoints} {
+			tag, err := quota.NewTag(QuotaTargetSrv, target, key.scope)
+			if err != nil {
+				return nil, err
+			}
+			u.Set(tag, counters.get(key, now))
+		}
+	}
+	return u, nil
+}
+
+func (s *QueryQuotaService) scopeKeys(ctx context.Context, user identity.Requester) ([]scopeKey, error) {
+	keys := []scopeKey{{scope: quota.GlobalScope}}
+	if orgID := user.GetOrgID(); orgID != 0 {
+		keys = append(keys, scopeKey{scope: quota.OrgScope, id: orgID})
+	}
+
+	id := userID(user)
+	if id == 0 {
+		return keys, nil
+	}
+	keys = append(keys, scopeKey{scope: quota.UserScope, id: id})
+
+	teamIDs, err := s.teamService.GetTeamIDsByUser(ctx, &team.GetTeamIDsByUserQuery{OrgID: user.GetOrgID(), UserID: id})
+	if err != nil {
+		return nil, fmt.Errorf("getting teams of user: %w", err)
+	}
+	for _, teamID := range teamIDs {
+		keys = append(keys, scopeKey{scope: quota.TeamScope, id: teamID})
+	}
+	return keys, nil
+}
+
+func userID(user identity.Requester) int64 {
+	id, _ := identity.UserIdentifier(user.GetNamespacedID())
+	return id
+}
+
+// countDataPoints counts the values of the non time fields of the response frames
+func countDataPoints(resp *backend.QueryDataResponse) int64 {
+	var points int64
+	for _, res := range resp.Responses {
+		for _, frame := range res.Frames {
+			for _, field := range frame.Fields {
+				if field.Type().Time() {
+					continue
+				}
+				points += int64(field.Len())
+			}
+		}
+	}
+	return points
+}
+
+func readQuotaConfig(cfg *setting.Cfg) (*quota.Map, error) {
+	limits := &quota.Map{}
+	for scope, values := range map[quota.Scope][2]int64{
+		quota.GlobalScope: {cfg.Quota.Global.QueriesPerMinute, cfg.Quota.Global.DataPointsPerHour},
+		quota.OrgScope:    {cfg.Quota.Org.QueriesPerMinute, cfg.Quota.Org.DataPointsPerHour},
+		quota.TeamScope:   {cfg.Quota.Team.QueriesPerMinute, cfg.Quota.Team.DataPointsPerHour},
+		quota.UserScope:   {cfg.Quota.User.QueriesPerMinute, cfg.Quota.User.DataPointsPerHour},
+	} {
+		queriesTag, err := quota.NewTag(QuotaTargetSrv, QueriesTarget, scope)
+		if err != nil {
+			return nil, err
+		}
+		dataPointsTag, err := quota.NewTag(QuotaTargetSrv, DataPointsTarget, scope)
+		if err != nil {
+			return nil, err
+		}
+		limits.Set(queriesTag, values[0])
+		limits.Set(dataPointsTag, values[1])
+	}
+	return limits, nil
+}
+
+// windowCounters counts by scope over fixed time windows
+type windowCounters struct {
+	mu       sync.Mutex
+	duration time.Duration
+	window   time.Time
+	counts   map[scopeKey]int64
+}
+
+func newWindowCounters(duration time.Duration) *windowCounters {
+	return &windowCounters{duration: duration, counts: map[scopeKey]int64{}}
+}
+
+// rotate starts a new window when the current one is over
+func (w *windowCounters) rotate(now time.Time) {
+	if window := now.Truncate(w.duration); !window.Equal(w.window) {
+		w.window = window
+		w.counts = map[scopeKey]int64{}
+	}
+}
+
+func (w *windowCounters) add(keys []scopeKey, value int64, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.rotate(now)
+	for _, key := range keys {
+		w.counts[key] += value
+	}
+}
+
+func (w *windowCounters) get(key scopeKey, now time.Time) int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.rotate(now)
+	return w.counts[key]
+}
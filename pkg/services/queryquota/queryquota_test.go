@@ -0,0 +1,127 @@
+package queryquota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fakeQuotaService reports the quota reached for the teams in reachedTeams
+type fakeQuotaService struct {
+	*quotatest.FakeQuotaService
+	reachedTeams map[int64]bool
+	checked      []quota.ScopeParameters
+}
+
+func (f *fakeQuotaService) CheckQuotaReached(_ context.Context, _ quota.TargetSrv, params *quota.ScopeParameters) (bool, error) {
+	f.checked = append(f.checked, *params)
+	return f.reachedTeams[params.TeamID], nil
+}
+
+func newTestService(t *testing.T, quotaService quota.Service, teams ...int64) *QueryQuotaService {
+	t.Helper()
+
+	teamService := teamtest.NewFakeService()
+	for _, id := range teams {
+		teamService.ExpectedTeamsByUser = append(teamService.ExpectedTeamsByUser, &team.TeamDTO{ID: id})
+	}
+	cfg := setting.NewCfg()
+	cfg.Quota.Enabled = true
+	s, err := ProvideService(cfg, quotaService, teamService)
+	require.NoError(t, err)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestCheckQuota(t *testing.T) {
+	signedInUser := &user.SignedInUser{UserID: 3, OrgID: 1}
+
+	t.Run("counts the queries of every scope when the quota isn't reached", func(t *testing.T) {
+		quotaService := &fakeQuotaService{FakeQuotaService: quotatest.New(false, nil)}
+		s := newTestService(t, quotaService, 10, 11)
+
+		require.NoError(t, s.CheckQuota(context.Background(), signedInUser, 2))
+		assert.Equal(t, []quota.ScopeParameters{{OrgID: 1, UserID: 3}, {TeamID: 10}, {TeamID: 11}}, quotaService.checked)
+
+		usage, err := s.Usage(context.Background(), &quota.ScopeParameters{OrgID: 1, UserID: 3, TeamID: 11})
+		require.NoError(t, err)
+		for _, scope := range []quota.Scope{quota.GlobalScope, quota.OrgScope, quota.UserScope, quota.TeamScope} {
+			tag, err := quota.NewTag(QuotaTargetSrv, QueriesTarget, scope)
+			require.NoError(t, err)
+			used, ok := usage.Get(tag)
+			require.True(t, ok)
+			assert.Equal(t, int64(2), used, scope)
+		}
+	})
+
+	t.Run("returns ErrQuotaReached when a team reached its quota", func(t *testing.T) {
+		quotaService := &fakeQuotaService{FakeQuotaService: quotatest.New(false, nil), reachedTeams: map[int64]bool{11: true}}
+		s := newTestService(t, quotaService, 10, 11)
+
+		err := s.CheckQuota(context.Background(), signedInUser, 1)
+		require.ErrorIs(t, err, ErrQuotaReached)
+
+		usage, err := s.Usage(context.Background(), nil)
+		require.NoError(t, err)
+		tag, err := quota.NewTag(QuotaTargetSrv, QueriesTarget, quota.GlobalScope)
+		require.NoError(t, err)
+		used, _ := usage.Get(tag)
+		assert.Equal(t, int64(0), used)
+	})
+
+	t.Run("does nothing when quotas are disabled", func(t *testing.T) {
+		quotaService := &fakeQuotaService{FakeQuotaService: quotatest.New(true, nil)}
+		s := newTestService(t, quotaService)
+		s.enabled = false
+
+		require.NoError(t, s.CheckQuota(context.Background(), signedInUser, 1))
+		assert.Empty(t, quotaService.checked)
+	})
+}
+
+func TestRecordResponse(t *testing.T) {
+	s := newTestService(t, quotatest.New(false, nil))
+	resp := &backend.QueryDataResponse{Responses: backend.Responses{
+		"A": {Frames: data.Frames{data.NewFrame("",
+			data.NewField("time", nil, []time.Time{time.Now(), time.Now()}),
+			data.NewField("a", nil, []float64{1, 2}),
+			data.NewField("b", nil, []float64{3, 4}),
+		)}},
+		"B": {Frames: data.Frames{data.NewFrame("", data.NewField("value", nil, []int64{1}))}},
+	}}
+	assert.Equal(t, int64(5), countDataPoints(resp))
+
+	s.RecordResponse(context.Background(), &user.SignedInUser{UserID: 3, OrgID: 1}, resp)
+	usage, err := s.Usage(context.Background(), &quota.ScopeParameters{OrgID: 1})
+	require.NoError(t, err)
+	tag, err := quota.NewTag(QuotaTargetSrv, DataPointsTarget, quota.OrgScope)
+	require.NoError(t, err)
+	used, _ := usage.Get(tag)
+	assert.Equal(t, int64(5), used)
+}
+
+func TestWindowCounters(t *testing.T) {
+	w := newWindowCounters(time.Minute)
+	key := scopeKey{scope: quota.OrgScope, id: 1}
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	w.add([]scopeKey{key}, 3, start)
+	w.add([]scopeKey{key}, 2, start.Add(59*time.Second))
+	assert.Equal(t, int64(5), w.get(key, start.Add(59*time.Second)))
+	assert.Equal(t, int64(0), w.get(scopeKey{scope: quota.OrgScope, id: 2}, start))
+
+	assert.Equal(t, int64(0), w.get(key, start.Add(time.Minute)))
+}
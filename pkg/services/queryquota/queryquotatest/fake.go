@@ -0,0 +1,32 @@
+package queryquotatest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+)
+
+type FakeQueryQuotaService struct {
+	err error
+
+	CheckedQueries  int
+	RecordedResults int
+}
+
+func New(err error) *FakeQueryQuotaService {
+	return &FakeQueryQuotaService{err: err}
+}
+
+func (f *FakeQueryQuotaService) CheckQuota(ctx context.Context, user identity.Requester, queries int) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.CheckedQueries += queries
+	return nil
+}
+
+func (f *FakeQueryQuotaService) RecordResponse(ctx context.Context, user identity.Requester, resp *backend.QueryDataResponse) {
+	f.RecordedResults++
+}
@@ -102,6 +102,8 @@ func (s *service) GetQuotasByScope(ctx context.Context, scope quota.Scope, id in
 		scopeParams.OrgID = id
 	} else if scope == quota.UserScope {
 		scopeParams.UserID = id
+	} else if scope == quota.TeamScope {
+		scopeParams.TeamID = id
 	}
 
 	c, err := s.getContext(ctx)
@@ -150,6 +152,7 @@ func (s *service) GetQuotasByScope(ctx context.Context, scope quota.Scope, id in
 			Limit:   limit,
 			OrgId:   scopeParams.OrgID,
 			UserId:  scopeParams.UserID,
+			TeamId:  scopeParams.TeamID,
 			Used:    used,
 			Service: string(srv),
 			Scope:   string(scope),
@@ -220,6 +223,11 @@ func (s *service) CheckQuotaReached(ctx context.Context, targetSrv quota.TargetS
 				continue
 			}
 
+			// do not check team quota if the team information is not available
+			if scope == quota.TeamScope && (scopeParams == nil || scopeParams.TeamID == 0) {
+				continue
+			}
+
 			u, ok := targetUsage.Get(t)
 			if !ok {
 				return false, quota.ErrUsageFoundForTarget.Errorf("no usage for target:%s", t)
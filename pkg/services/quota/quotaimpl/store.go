@@ -50,6 +50,14 @@ func (ss *sqlStore) Get(ctx quota.Context, scopeParams *quota.ScopeParameters) (
 		limits.Merge(userLimits)
 	}
 
+	if scopeParams.TeamID != 0 {
+		teamLimits, err := ss.getTeamScopeQuota(ctx, scopeParams.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		limits.Merge(teamLimits)
+	}
+
 	return &limits, nil
 }
 
@@ -60,6 +68,7 @@ func (ss *sqlStore) Update(ctx quota.Context, cmd *quota.UpdateQuotaCmd) error {
 			Target: cmd.Target,
 			UserId: cmd.UserID,
 			OrgId:  cmd.OrgID,
+			TeamId: cmd.TeamID,
 		}
 		has, err := sess.Get(&quota)
 		if err != nil {
@@ -89,7 +98,7 @@ func (ss *sqlStore) getUserScopeQuota(ctx quota.Context, userID int64) (*quota.M
 	r := quota.Map{}
 	err := ss.db.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
 		quotas := make([]*quota.Quota, 0)
-		if err := sess.Table("quota").Where("user_id=? AND org_id=0", userID).Find(&quotas); err != nil {
+		if err := sess.Table("quota").Where("user_id=? AND org_id=0 AND team_id=0", userID).Find(&quotas); err != nil {
 			return err
 		}
 
@@ -113,7 +122,7 @@ func (ss *sqlStore) getOrgScopeQuota(ctx quota.Context, OrgID int64) (*quota.Map
 	r := quota.Map{}
 	err := ss.db.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
 		quotas := make([]*quota.Quota, 0)
-		if err := sess.Table("quota").Where("user_id=0 AND org_id=?", OrgID).Find(&quotas); err != nil {
+		if err := sess.Table("quota").Where("user_id=0 AND org_id=? AND team_id=0", OrgID).Find(&quotas); err != nil {
 			return err
 		}
 
@@ -132,3 +141,27 @@ func (ss *sqlStore) getOrgScopeQuota(ctx quota.Context, OrgID int64) (*quota.Map
 	})
 	return &r, err
 }
+
+func (ss *sqlStore) getTeamScopeQuota(ctx quota.Context, teamID int64) (*quota.Map, error) {
+	r := quota.Map{}
+	err := ss.db.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		quotas := make([]*quota.Quota, 0)
+		if err := sess.Table("quota").Where("team_id=? AND user_id=0 AND org_id=0", teamID).Find(&quotas); err != nil {
+			return err
+		}
+
+		for _, q := range quotas {
+			srv, ok := ctx.TargetToSrv.Get(quota.Target(q.Target))
+			if !ok {
+				ss.logger.Info("failed to get service for target", "target", q.Target)
+			}
+			tag, err := quota.NewTag(srv, quota.Target(q.Target), quota.TeamScope)
+			if err != nil {
+				return err
+			}
+			r.Set(tag, q.Limit)
+		}
+		return nil
+	})
+	return &r, err
+}
@@ -0,0 +1,250 @@
+package selfobservability
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	ngac "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/api"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+)
+
+type stateReader interface {
+	GetAll(orgID int64) []*state.State
+}
+
+type ruleReader interface {
+	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error)
+}
+
+type alertingProvider struct {
+	states    stateReader
+	historian api.Historian
+	rules     ruleReader
+	ac        accesscontrol.AccessControl
+	authz     *ngac.RuleService
+}
+
+func newAlertingProvider(states stateReader, historian api.Historian, rules ruleReader, ac accesscontrol.AccessControl) *alertingProvider {
+	return &alertingProvider{
+		states:    states,
+		historian: historian,
+		rules:     rules,
+		ac:        ac,
+		authz:     ngac.NewRuleService(ac),
+	}
+}
+
+// readableRules returns the rules of the requester's organization the
+// requester can read, with the same checks as the rules API: reading the
+// folder and the rules in it, and querying all data sources of the group.
+func (p *alertingProvider) readableRules(ctx context.Context, requester identity.Requester) (map[string]*ngmodels.AlertRule, error) {
+	ok, err := p.ac.Evaluate(ctx, requester, accesscontrol.EvalPermission(accesscontrol.ActionAlertingRuleRead))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, grafanads.ErrSystemDataAccessDenied
+	}
+
+	rules, err := p.rules.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: requester.GetOrgID()})
+	if err != nil {
+		return nil, err
+	}
+
+	readable := make(map[string]*ngmodels.AlertRule, len(rules))
+	for _, group := range ngmodels.GroupByAlertRuleGroupKey(rules) {
+		ok, err := p.authz.HasAccessToRuleGroup(ctx, requester, group)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		for _, rule := range group {
+			readable[rule.UID] = rule
+		}
+	}
+	return readable, nil
+}
+
+// alertStates returns one row per alert instance in the state cache.
+func (p *alertingProvider) alertStates(ctx context.Context, requester identity.Requester, query grafanads.SystemDataQuery) (data.Frames, error) {
+	rules, err := p.readableRules(ctx, requester)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*state.State, 0)
+	for _, s := range p.states.GetAll(requester.GetOrgID()) {
+		if _, ok := rules[s.AlertRuleUID]; !ok {
+			continue
+		}
+		if query.RuleUID != "" && s.AlertRuleUID != query.RuleUID {
+			continue
+		}
+		if !matchLabels(s.Labels, query.Labels) {
+			continue
+		}
+		states = append(states, s)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].AlertRuleUID != states[j].AlertRuleUID {
+			return states[i].AlertRuleUID < states[j].AlertRuleUID
+		}
+		return states[i].CacheID < states[j].CacheID
+	})
+
+	frame := data.NewFrame("alertStates",
+		data.NewField("ruleUID", nil, make([]string, 0, len(states))),
+		data.NewField("ruleTitle", nil, make([]string, 0, len(states))),
+		data.NewField("folderUID", nil, make([]string, 0, len(states))),
+		data.NewField("state", nil, make([]string, 0, len(states))),
+		data.NewField("reason", nil, make([]string, 0, len(states))),
+		data.NewField("labels", nil, make([]json.RawMessage, 0, len(states))),
+		data.NewField("activeSince", nil, make([]time.Time, 0, len(states))),
+		data.NewField("lastEvaluation", nil, make([]time.Time, 0, len(states))),
+		data.NewField("evaluationDuration", nil, make([]float64, 0, len(states))).SetConfig(&data.FieldConfig{Unit: "s"}),
+	)
+	for _, s := range states {
+		rule := rules[s.AlertRuleUID]
+		labels, err := json.Marshal(s.Labels)
+		if err != nil {
+			return nil, err
+		}
+		frame.AppendRow(
+			rule.UID,
+			rule.Title,
+			rule.NamespaceUID,
+			s.State.String(),
+			s.StateReason,
+			json.RawMessage(labels),
+			s.StartsAt,
+			s.LastEvaluationTime,
+			s.EvaluationDuration.Seconds(),
+		)
+	}
+	return data.Frames{frame}, nil
+}
+
+// ruleEvaluations returns one row per alert rule with the latest evaluation
+// of its instances.
+func (p *alertingProvider) ruleEvaluations(ctx context.Context, requester identity.Requester, query grafanads.SystemDataQuery) (data.Frames, error) {
+	rules, err := p.readableRules(ctx, requester)
+	if err != nil {
+		return nil, err
+	}
+
+	type evaluation struct {
+		last      time.Time
+		duration  time.Duration
+		instances int64
+		health    string
+	}
+	evaluations := make(map[string]*evaluation, len(rules))
+	for _, s := range p.states.GetAll(requester.GetOrgID()) {
+		if _, ok := rules[s.AlertRuleUID]; !ok {
+			continue
+		}
+		e, ok := evaluations[s.AlertRuleUID]
+		if !ok {
+			e = &evaluation{health: "ok"}
+			evaluations[s.AlertRuleUID] = e
+		}
+		e.instances++
+		if s.LastEvaluationTime.After(e.last) {
+			e.last = s.LastEvaluationTime
+			e.duration = s.EvaluationDuration
+		}
+		switch s.State {
+		case eval.Error:
+			e.health = "error"
+		case eval.NoData:
+			if e.health == "ok" {
+				e.health = "nodata"
+			}
+		}
+	}
+
+	uids := make([]string, 0, len(rules))
+	for uid := range rules {
+		if query.RuleUID != "" && uid != query.RuleUID {
+			continue
+		}
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	frame := data.NewFrame("ruleEvaluations",
+		data.NewField("ruleUID", nil, make([]string, 0, len(uids))),
+		data.NewField("ruleTitle", nil, make([]string, 0, len(uids))),
+		data.NewField("folderUID", nil, make([]string, 0, len(uids))),
+		data.NewField("ruleGroup", nil, make([]string, 0, len(uids))),
+		data.NewField("health", nil, make([]string, 0, len(uids))),
+		data.NewField("instances", nil, make([]int64, 0, len(uids))),
+		data.NewField("lastEvaluation", nil, make([]*time.Time, 0, len(uids))),
+		data.NewField("evaluationDuration", nil, make([]*float64, 0, len(uids))).SetConfig(&data.FieldConfig{Unit: "s"}),
+	)
+	for _, uid := range uids {
+		rule := rules[uid]
+		e, ok := evaluations[uid]
+		if !ok {
+			// The rule was not evaluated yet on this instance.
+			frame.AppendRow(rule.UID, rule.Title, rule.NamespaceUID, rule.RuleGroup, "unknown", int64(0), (*time.Time)(nil), (*float64)(nil))
+			continue
+		}
+		last := e.last
+		duration := e.duration.Seconds()
+		frame.AppendRow(rule.UID, rule.Title, rule.NamespaceUID, rule.RuleGroup, e.health, e.instances, &last, &duration)
+	}
+	return data.Frames{frame}, nil
+}
+
+// alertStateHistory returns the state transitions recorded by the configured
+// state history backend.
+func (p *alertingProvider) alertStateHistory(ctx context.Context, requester identity.Requester, query grafanads.SystemDataQuery) (data.Frames, error) {
+	rules, err := p.readableRules(ctx, requester)
+	if err != nil {
+		return nil, err
+	}
+	if query.RuleUID != "" {
+		if _, ok := rules[query.RuleUID]; !ok {
+			return nil, grafanads.ErrSystemDataAccessDenied
+		}
+	}
+
+	frame, err := p.historian.Query(ctx, ngmodels.HistoryQuery{
+		RuleUID:      query.RuleUID,
+		OrgID:        requester.GetOrgID(),
+		Labels:       query.Labels,
+		From:         query.TimeRange.From,
+		To:           query.TimeRange.To,
+		Limit:        query.Limit,
+		SignedInUser: requester,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if frame == nil {
+		return data.Frames{}, nil
+	}
+	return data.Frames{frame}, nil
+}
+
+func matchLabels(labels data.Labels, matchers map[string]string) bool {
+	for k, v := range matchers {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
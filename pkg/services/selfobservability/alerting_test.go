@@ -0,0 +1,122 @@
+package selfobservability
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+)
+
+func TestAlertingProvider(t *testing.T) {
+	now := time.Now()
+	rules := ngmodels.RulesGroup{
+		testRule("rule-1", "folder-1", "ds-1"),
+		testRule("rule-2", "folder-2", "ds-1"),
+	}
+	states := fakeStateReader{
+		{OrgID: 1, AlertRuleUID: "rule-1", CacheID: "a", State: eval.Alerting, Labels: data.Labels{"team": "a"}, LastEvaluationTime: now, EvaluationDuration: 2 * time.Second},
+		{OrgID: 1, AlertRuleUID: "rule-1", CacheID: "b", State: eval.Error, Labels: data.Labels{"team": "b"}, LastEvaluationTime: now.Add(-time.Minute), EvaluationDuration: time.Second},
+		{OrgID: 1, AlertRuleUID: "rule-2", CacheID: "c", State: eval.Normal, LastEvaluationTime: now},
+	}
+	p := newAlertingProvider(states, nil, fakeRuleReader(rules), acimpl.ProvideAccessControl(setting.NewCfg()))
+
+	folderReader := &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: {
+		accesscontrol.ActionAlertingRuleRead: {dashboards.ScopeFoldersProvider.GetResourceScopeUID("folder-1")},
+		dashboards.ActionFoldersRead:         {dashboards.ScopeFoldersProvider.GetResourceScopeUID("folder-1")},
+		datasources.ActionQuery:              {datasources.ScopeProvider.GetResourceScopeUID("ds-1")},
+	}}}
+
+	t.Run("returns the states of the rules the user can read", func(t *testing.T) {
+		frames, err := p.alertStates(context.Background(), folderReader, grafanads.SystemDataQuery{})
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+
+		frame := frames[0]
+		require.Equal(t, 2, frame.Rows())
+		ruleUIDs, _ := frame.FieldByName("ruleUID")
+		require.Equal(t, "rule-1", ruleUIDs.At(0))
+		require.Equal(t, "rule-1", ruleUIDs.At(1))
+		stateField, _ := frame.FieldByName("state")
+		require.Equal(t, "Alerting", stateField.At(0))
+		require.Equal(t, "Error", stateField.At(1))
+	})
+
+	t.Run("filters states by labels", func(t *testing.T) {
+		frames, err := p.alertStates(context.Background(), folderReader, grafanads.SystemDataQuery{Labels: map[string]string{"team": "b"}})
+		require.NoError(t, err)
+		require.Equal(t, 1, frames[0].Rows())
+	})
+
+	t.Run("summarizes the latest evaluation of each rule", func(t *testing.T) {
+		frames, err := p.ruleEvaluations(context.Background(), folderReader, grafanads.SystemDataQuery{})
+		require.NoError(t, err)
+
+		frame := frames[0]
+		require.Equal(t, 1, frame.Rows())
+		health, _ := frame.FieldByName("health")
+		require.Equal(t, "error", health.At(0))
+		instances, _ := frame.FieldByName("instances")
+		require.Equal(t, int64(2), instances.At(0))
+		duration, _ := frame.FieldByName("evaluationDuration")
+		require.Equal(t, 2.0, *(duration.At(0).(*float64)))
+	})
+
+	t.Run("denies users without alert rule read permission", func(t *testing.T) {
+		viewer := &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: {
+			datasources.ActionQuery: {datasources.ScopeAll},
+		}}}
+		_, err := p.alertStates(context.Background(), viewer, grafanads.SystemDataQuery{})
+		require.ErrorIs(t, err, grafanads.ErrSystemDataAccessDenied)
+
+		_, err = p.alertStateHistory(context.Background(), folderReader, grafanads.SystemDataQuery{RuleUID: "rule-2"})
+		require.ErrorIs(t, err, grafanads.ErrSystemDataAccessDenied)
+	})
+}
+
+func testRule(uid, folderUID, dsUID string) *ngmodels.AlertRule {
+	return &ngmodels.AlertRule{
+		OrgID:        1,
+		UID:          uid,
+		Title:        uid,
+		NamespaceUID: folderUID,
+		RuleGroup:    "group",
+		Data:         []ngmodels.AlertQuery{{RefID: "A", DatasourceUID: dsUID}},
+	}
+}
+
+type fakeStateReader []*state.State
+
+func (f fakeStateReader) GetAll(orgID int64) []*state.State {
+	res := make([]*state.State, 0, len(f))
+	for _, s := range f {
+		if s.OrgID == orgID {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+type fakeRuleReader ngmodels.RulesGroup
+
+func (f fakeRuleReader) ListAlertRules(_ context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error) {
+	res := ngmodels.RulesGroup{}
+	for _, r := range f {
+		if r.OrgID == query.OrgID {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,78 @@
+package selfobservability
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/datasources/healthcheck"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+)
+
+var errHealthChecksDisabled = errors.New("background data source health checks are disabled")
+
+type healthHistoryLister interface {
+	IsDisabled() bool
+	ListHistories(orgID int64) []*healthcheck.HistoryDTO
+}
+
+type dataSourceHealthProvider struct {
+	healthCheck healthHistoryLister
+	dataSources datasources.DataSourceService
+	ac          accesscontrol.AccessControl
+}
+
+// QuerySystemData returns the latest background health check result of every
+// data source the requester can read.
+func (p *dataSourceHealthProvider) QuerySystemData(ctx context.Context, requester identity.Requester, _ grafanads.SystemDataQuery) (data.Frames, error) {
+	if p.healthCheck.IsDisabled() {
+		return nil, errHealthChecksDisabled
+	}
+
+	dss, err := p.dataSources.GetDataSources(ctx, &datasources.GetDataSourcesQuery{OrgID: requester.GetOrgID()})
+	if err != nil {
+		return nil, err
+	}
+	byUID := make(map[string]*datasources.DataSource, len(dss))
+	for _, ds := range dss {
+		byUID[ds.UID] = ds
+	}
+
+	frame := data.NewFrame("dataSourceHealth",
+		data.NewField("uid", nil, []string{}),
+		data.NewField("name", nil, []string{}),
+		data.NewField("type", nil, []string{}),
+		data.NewField("status", nil, []string{}),
+		data.NewField("message", nil, []string{}),
+		data.NewField("lastChecked", nil, []*time.Time{}),
+		data.NewField("latency", nil, []*float64{}).SetConfig(&data.FieldConfig{Unit: "s"}),
+	)
+	for _, h := range p.healthCheck.ListHistories(requester.GetOrgID()) {
+		ds, ok := byUID[h.UID]
+		if !ok {
+			continue
+		}
+		ok, err := p.ac.Evaluate(ctx, requester, accesscontrol.EvalPermission(datasources.ActionRead, datasources.ScopeProvider.GetResourceScopeUID(h.UID)))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		var message string
+		var latency *float64
+		if len(h.Results) > 0 {
+			message = h.Results[0].Message
+			l := h.Results[0].Latency.Seconds()
+			latency = &l
+		}
+		frame.AppendRow(h.UID, ds.Name, ds.Type, string(h.Status), message, h.LastChecked, latency)
+	}
+	return data.Frames{frame}, nil
+}
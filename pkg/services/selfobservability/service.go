@@ -0,0 +1,51 @@
+// Package selfobservability exposes Grafana's own state, such as alert
+// instances, data source health and user activity, as query types of the
+// built-in Grafana data source.
+package selfobservability
+
+import (
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/datasources/healthcheck"
+	"github.com/grafana/grafana/pkg/services/ngalert"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/stats"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+)
+
+// Service registers the self-observability providers in the Grafana data
+// source. The providers live here rather than in grafanads, as the services
+// they read from depend on the plugin infrastructure that grafanads is part of.
+type Service struct {
+	log log.Logger
+}
+
+func ProvideService(
+	grafanaDS *grafanads.Service,
+	ng *ngalert.AlertNG,
+	ruleStore *ngstore.DBstore,
+	healthCheck *healthcheck.Service,
+	dataSourceService datasources.DataSourceService,
+	statsService stats.Service,
+	ac accesscontrol.AccessControl,
+) *Service {
+	s := &Service{log: log.New("selfobservability")}
+
+	if !ng.IsDisabled() {
+		alerting := newAlertingProvider(ng.GetStateManager(), ng.GetHistorian(), ruleStore, ac)
+		grafanaDS.RegisterSystemDataProvider(grafanads.QueryTypeAlertStates, grafanads.SystemDataProviderFunc(alerting.alertStates))
+		grafanaDS.RegisterSystemDataProvider(grafanads.QueryTypeAlertStateHistory, grafanads.SystemDataProviderFunc(alerting.alertStateHistory))
+		grafanaDS.RegisterSystemDataProvider(grafanads.QueryTypeRuleEvaluations, grafanads.SystemDataProviderFunc(alerting.ruleEvaluations))
+	} else {
+		s.log.Debug("Unified alerting is disabled, alerting queries are not available")
+	}
+
+	health := &dataSourceHealthProvider{healthCheck: healthCheck, dataSources: dataSourceService, ac: ac}
+	grafanaDS.RegisterSystemDataProvider(grafanads.QueryTypeDataSourceHealth, health)
+
+	users := &activeUsersProvider{stats: statsService, ac: ac}
+	grafanaDS.RegisterSystemDataProvider(grafanads.QueryTypeActiveUsers, users)
+
+	return s
+}
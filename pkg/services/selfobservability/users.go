@@ -0,0 +1,48 @@
+package selfobservability
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/stats"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+)
+
+type activeUsersProvider struct {
+	stats stats.Service
+	ac    accesscontrol.AccessControl
+}
+
+// QuerySystemData returns the server wide number of active users and
+// sessions, the same figures as the server admin stats page.
+func (p *activeUsersProvider) QuerySystemData(ctx context.Context, requester identity.Requester, _ grafanads.SystemDataQuery) (data.Frames, error) {
+	ok, err := p.ac.Evaluate(ctx, requester, accesscontrol.EvalPermission(accesscontrol.ActionServerStatsRead))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, grafanads.ErrSystemDataAccessDenied
+	}
+
+	s, err := p.stats.GetAdminStats(ctx, &stats.GetAdminStatsQuery{})
+	if err != nil {
+		return nil, err
+	}
+
+	frame := data.NewFrame("activeUsers",
+		data.NewField("time", nil, []time.Time{time.Now()}),
+		data.NewField("activeUsers", nil, []int64{s.ActiveUsers}),
+		data.NewField("activeAdmins", nil, []int64{s.ActiveAdmins}),
+		data.NewField("activeEditors", nil, []int64{s.ActiveEditors}),
+		data.NewField("activeViewers", nil, []int64{s.ActiveViewers}),
+		data.NewField("activeSessions", nil, []int64{s.ActiveSessions}),
+		data.NewField("dailyActiveUsers", nil, []int64{s.DailyActiveUsers}),
+		data.NewField("dailyActiveSessions", nil, []int64{s.DailyActiveSessions}),
+		data.NewField("monthlyActiveUsers", nil, []int64{s.MonthlyActiveUsers}),
+	)
+	return data.Frames{frame}, nil
+}
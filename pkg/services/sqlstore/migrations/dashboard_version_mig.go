@@ -56,4 +56,8 @@ FROM dashboard;`
 	// change column type of dashboard_version.data
 	mg.AddMigration("alter dashboard_version.data to mediumtext v1", NewRawSQLMigration("").
 		Mysql("ALTER TABLE dashboard_version MODIFY data MEDIUMTEXT;"))
+
+	mg.AddMigration("add label column to dashboard_version", NewAddColumnMigration(dashboardVersionV1, &Column{
+		Name: "label", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
 }
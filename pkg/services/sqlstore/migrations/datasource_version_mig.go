@@ -0,0 +1,29 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addDataSourceVersionMigration(mg *Migrator) {
+	dataSourceVersionV1 := Table{
+		Name: "data_source_version",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "data_source_id", Type: DB_BigInt, Nullable: false},
+			{Name: "data_source_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "version", Type: DB_Int, Nullable: false},
+			{Name: "restored_from", Type: DB_Int, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "data", Type: DB_Text, Nullable: false},
+			{Name: "secure_json_changed", Type: DB_Text, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "data_source_uid"}},
+			{Cols: []string{"data_source_id", "version"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create data_source_version table v1", NewAddTableMigration(dataSourceVersionV1))
+	mg.AddMigration("add index data_source_version.org_id_data_source_uid", NewAddIndexMigration(dataSourceVersionV1, dataSourceVersionV1.Indices[0]))
+	mg.AddMigration("add unique index data_source_version.data_source_id_version", NewAddIndexMigration(dataSourceVersionV1, dataSourceVersionV1.Indices[1]))
+}
@@ -28,4 +28,13 @@ func addQuotaMigration(mg *Migrator) {
 	mg.AddMigration("Update quota table charset", NewTableCharsetMigration("quota", []*Column{
 		{Name: "target", Type: DB_NVarchar, Length: 190, Nullable: false},
 	}))
+
+	mg.AddMigration("Add column team_id to quota", NewAddColumnMigration(quotaV1, &Column{
+		Name: "team_id", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("Drop unique index org_id_user_id_target on quota", NewDropIndexMigration(quotaV1, quotaV1.Indices[0]))
+	mg.AddMigration("Add unique index org_id_user_id_team_id_target on quota", NewAddIndexMigration(quotaV1, &Index{
+		Cols: []string{"org_id", "user_id", "team_id", "target"}, Type: UniqueIndex,
+	}))
 }
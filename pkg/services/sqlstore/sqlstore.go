@@ -161,6 +161,19 @@ func (ss *SQLStore) Migrate(isDatabaseLockingEnabled bool) error {
 	return migrator.Start(isDatabaseLockingEnabled, ss.dbCfg.MigrationLockAttemptTimeout)
 }
 
+// MigrateEngine runs the registered migrations against another database engine. It's used to prepare the target
+// database when an instance is moved to another database backend.
+func (ss *SQLStore) MigrateEngine(engine *xorm.Engine) error {
+	if ss.migrations == nil {
+		return fmt.Errorf("no database migrations registered")
+	}
+
+	migrator := migrator.NewMigrator(engine, ss.Cfg)
+	ss.migrations.AddMigration(migrator)
+
+	return migrator.Start(false, 0)
+}
+
 // Reset resets database state.
 // If default org and user creation is enabled, it will be ensured they exist in the database.
 func (ss *SQLStore) Reset() error {
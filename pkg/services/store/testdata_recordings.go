@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+)
+
+const (
+	testDataRecordingsUser   SystemUserType = "testdata-recordings"
+	testDataRecordingsFolder                = "testdata-recordings"
+	testDataRecordingsExt                   = ".json"
+	maxTestDataRecordings                   = 1000
+)
+
+// TestDataRecordings keeps the recordings of the TestData replay scenario as
+// JSON files under `system/testdata-recordings/` in the storage of each org.
+type TestDataRecordings struct {
+	storage     StorageService
+	systemUsers SystemUsersProvider
+}
+
+func ProvideTestDataRecordings(storage StorageService, systemUsers SystemUsers) *TestDataRecordings {
+	systemUsers.RegisterUser(testDataRecordingsUser, func() map[string]filestorage.PathFilter {
+		filter := filestorage.NewPathFilter(
+			[]string{filestorage.Delimiter + testDataRecordingsFolder + filestorage.Delimiter},
+			[]string{filestorage.Delimiter + testDataRecordingsFolder},
+			nil, nil)
+
+		return map[string]filestorage.PathFilter{
+			ActionFilesRead:   filter,
+			ActionFilesWrite:  filter,
+			ActionFilesDelete: denyAllPathFilter,
+		}
+	})
+
+	return &TestDataRecordings{
+		storage:     storage,
+		systemUsers: systemUsers,
+	}
+}
+
+func (r *TestDataRecordings) ListRecordings(ctx context.Context, orgID int64) ([]string, error) {
+	u, err := r.systemUsers.GetUser(testDataRecordingsUser, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	listFrame, err := r.storage.List(ctx, u, RootSystem+"/"+testDataRecordingsFolder, maxTestDataRecordings)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	if listFrame == nil || listFrame.Frame == nil {
+		return names, nil
+	}
+
+	field, _ := listFrame.FieldByName(nameListFrameField)
+	if field == nil {
+		return names, nil
+	}
+	for i := 0; i < field.Len(); i++ {
+		name, ok := field.At(i).(string)
+		if ok && strings.HasSuffix(name, testDataRecordingsExt) {
+			names = append(names, strings.TrimSuffix(name, testDataRecordingsExt))
+		}
+	}
+	return names, nil
+}
+
+func (r *TestDataRecordings) ReadRecording(ctx context.Context, orgID int64, name string) ([]byte, error) {
+	u, err := r.systemUsers.GetUser(testDataRecordingsUser, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := r.storage.Read(ctx, u, testDataRecordingPath(name))
+	if err != nil || file == nil {
+		return nil, err
+	}
+	return file.Contents, nil
+}
+
+func (r *TestDataRecordings) WriteRecording(ctx context.Context, orgID int64, name string, body []byte, overwrite bool) error {
+	u, err := r.systemUsers.GetUser(testDataRecordingsUser, orgID)
+	if err != nil {
+		return err
+	}
+
+	return r.storage.Upload(ctx, u, &UploadRequest{
+		Contents:              body,
+		Path:                  testDataRecordingPath(name),
+		EntityType:            EntityTypeJSON,
+		OverwriteExistingFile: overwrite,
+	})
+}
+
+func testDataRecordingPath(name string) string {
+	return RootSystem + "/" + testDataRecordingsFolder + "/" + name + testDataRecordingsExt
+}
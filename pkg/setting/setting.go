@@ -220,6 +220,10 @@ type Cfg struct {
 	DashboardVersionsToKeep  int
 	MinRefreshInterval       string
 	DefaultHomeDashboardPath string
+	// DashboardVersionRetention applies to dashboards in folders without
+	// their own entry in DashboardVersionFolderRetention.
+	DashboardVersionRetention       DashboardVersionRetention
+	DashboardVersionFolderRetention map[string]DashboardVersionRetention
 
 	// Auth
 	LoginCookieName               string
@@ -324,9 +328,17 @@ type Cfg struct {
 
 	// Data sources
 	DataSourceLimit int
+	// Number of versions kept in the history of every data source.
+	DataSourceVersionsToKeep int
 	// Number of queries to be executed concurrently. Only for the datasource supports concurrency.
 	ConcurrentQueryCount int
 
+	// Background data source health checks
+	DataSourceHealthCheck DataSourceHealthCheckSettings
+
+	// Server-side export of query results
+	QueryExport QueryExportSettings
+
 	// IP range access control
 	IPRangeACEnabled     bool
 	IPRangeACAllowedURLs []*url.URL
@@ -336,6 +348,8 @@ type Cfg struct {
 	SqlDatasourceMaxOpenConnsDefault    int
 	SqlDatasourceMaxIdleConnsDefault    int
 	SqlDatasourceMaxConnLifetimeDefault int
+	// SqlDatasourceSQLiteAllowedPaths are the files and directories SQLite data sources can read
+	SqlDatasourceSQLiteAllowedPaths []string
 
 	// Snapshots
 	SnapshotEnabled      bool
@@ -390,6 +404,9 @@ type Cfg struct {
 
 	Quota QuotaSettings
 
+	// HTTP API rate limiting
+	RateLimiting RateLimitingSettings
+
 	// User settings
 	AllowUserSignUp            bool
 	AllowUserOrgCreate         bool
@@ -1086,6 +1103,9 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	cfg.DashboardVersionsToKeep = dashboards.Key("versions_to_keep").MustInt(20)
 	cfg.MinRefreshInterval = valueAsString(dashboards, "min_refresh_interval", "5s")
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
+	if err := readDashboardVersionRetentionSettings(iniFile, cfg); err != nil {
+		return err
+	}
 
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
@@ -1194,6 +1214,7 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	}
 
 	cfg.readQuotaSettings()
+	cfg.readRateLimitingSettings()
 
 	cfg.readExpressionsSettings()
 	if err := cfg.readGrafanaEnvironmentMetrics(); err != nil {
@@ -1202,6 +1223,8 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 
 	cfg.readDataSourcesSettings()
 	cfg.readDataSourceSecuritySettings()
+	cfg.readDataSourceHealthCheckSettings()
+	cfg.readQueryExportSettings()
 	cfg.readSqlDataSourceSettings()
 
 	cfg.Storage = readStorageSettings(iniFile)
@@ -1904,6 +1927,10 @@ func (cfg *Cfg) GetContentDeliveryURL(prefix string) (string, error) {
 func (cfg *Cfg) readDataSourcesSettings() {
 	datasources := cfg.Raw.Section("datasources")
 	cfg.DataSourceLimit = datasources.Key("datasource_limit").MustInt(5000)
+	cfg.DataSourceVersionsToKeep = datasources.Key("versions_to_keep").MustInt(20)
+	if cfg.DataSourceVersionsToKeep < 1 {
+		cfg.DataSourceVersionsToKeep = 1
+	}
 	cfg.ConcurrentQueryCount = datasources.Key("concurrent_query_count").MustInt(10)
 }
 
@@ -1931,6 +1958,7 @@ func (cfg *Cfg) readSqlDataSourceSettings() {
 	cfg.SqlDatasourceMaxOpenConnsDefault = sqlDatasources.Key("max_open_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxIdleConnsDefault = sqlDatasources.Key("max_idle_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxConnLifetimeDefault = sqlDatasources.Key("max_conn_lifetime_default").MustInt(14400)
+	cfg.SqlDatasourceSQLiteAllowedPaths = util.SplitString(sqlDatasources.Key("sqlite_allowed_paths").String())
 }
 
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
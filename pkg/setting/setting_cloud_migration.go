@@ -13,6 +13,7 @@ type CloudMigrationSettings struct {
 	DeleteAccessPolicyTimeout time.Duration
 	CreateTokenTimeout        time.Duration
 	TokenExpiresAfter         time.Duration
+	SnapshotMaxSize           int64
 }
 
 func (cfg *Cfg) readCloudMigrationSettings() {
@@ -25,4 +26,5 @@ func (cfg *Cfg) readCloudMigrationSettings() {
 	cfg.CloudMigration.DeleteAccessPolicyTimeout = cloudMigration.Key("delete_access_policy_timeout").MustDuration(5 * time.Second)
 	cfg.CloudMigration.CreateTokenTimeout = cloudMigration.Key("create_token_timeout").MustDuration(5 * time.Second)
 	cfg.CloudMigration.TokenExpiresAfter = cloudMigration.Key("token_expires_after").MustDuration(7 * 24 * time.Hour)
+	cfg.CloudMigration.SnapshotMaxSize = cloudMigration.Key("snapshot_max_size_mb").MustInt64(100) * 1024 * 1024
 }
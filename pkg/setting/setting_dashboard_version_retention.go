@@ -0,0 +1,60 @@
+package setting
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// dashboardVersionRetentionSectionPrefix is followed by a folder UID, use
+// "general" for dashboards at the root level.
+const dashboardVersionRetentionSectionPrefix = "dashboards.version_retention."
+
+type DashboardVersionRetention struct {
+	// VersionsToKeep is the number of most recent versions always kept per dashboard.
+	VersionsToKeep int
+	// MaxAge keeps every version newer than it, 0 disables the age based retention.
+	MaxAge time.Duration
+	// ProtectedLabels lists version labels that are never deleted.
+	ProtectedLabels []string
+}
+
+func readDashboardVersionRetentionSettings(iniFile *ini.File, cfg *Cfg) error {
+	dashboards := iniFile.Section("dashboards")
+	maxAge, err := gtime.ParseDuration(valueAsString(dashboards, "versions_max_age", "0"))
+	if err != nil {
+		return fmt.Errorf("invalid versions_max_age in [dashboards]: %w", err)
+	}
+	cfg.DashboardVersionRetention = DashboardVersionRetention{
+		VersionsToKeep:  cfg.DashboardVersionsToKeep,
+		MaxAge:          maxAge,
+		ProtectedLabels: util.SplitString(valueAsString(dashboards, "versions_protected_labels", "release")),
+	}
+
+	cfg.DashboardVersionFolderRetention = map[string]DashboardVersionRetention{}
+	for _, section := range iniFile.Sections() {
+		folderUID, ok := strings.CutPrefix(section.Name(), dashboardVersionRetentionSectionPrefix)
+		if !ok || folderUID == "" {
+			continue
+		}
+
+		retention := cfg.DashboardVersionRetention
+		retention.VersionsToKeep = section.Key("versions_to_keep").MustInt(retention.VersionsToKeep)
+		if section.HasKey("max_age") {
+			retention.MaxAge, err = gtime.ParseDuration(section.Key("max_age").String())
+			if err != nil {
+				return fmt.Errorf("invalid max_age in [%s]: %w", section.Name(), err)
+			}
+		}
+		if section.HasKey("protected_labels") {
+			retention.ProtectedLabels = util.SplitString(section.Key("protected_labels").String())
+		}
+		cfg.DashboardVersionFolderRetention[folderUID] = retention
+	}
+	return nil
+}
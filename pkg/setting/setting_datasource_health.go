@@ -0,0 +1,41 @@
+package setting
+
+import (
+	"time"
+)
+
+type DataSourceHealthCheckSettings struct {
+	// Enabled turns on the background data source health checks.
+	Enabled bool
+	// DefaultInterval between two health checks of a data source, can be
+	// overridden per data source with the healthCheckInterval JSON data field.
+	DefaultInterval time.Duration
+	// MinInterval is the lowest interval allowed for a data source.
+	MinInterval time.Duration
+	// Timeout for a single health check.
+	Timeout time.Duration
+	// HistorySize is the number of results kept per data source.
+	HistorySize int
+	// MaxConcurrentChecks limits the number of health checks running at the same time.
+	MaxConcurrentChecks int
+}
+
+func (cfg *Cfg) readDataSourceHealthCheckSettings() {
+	healthCheck := cfg.Raw.Section("datasources.health_check")
+	cfg.DataSourceHealthCheck.Enabled = healthCheck.Key("enabled").MustBool(false)
+	cfg.DataSourceHealthCheck.DefaultInterval = healthCheck.Key("default_interval").MustDuration(5 * time.Minute)
+	cfg.DataSourceHealthCheck.MinInterval = healthCheck.Key("min_interval").MustDuration(30 * time.Second)
+	cfg.DataSourceHealthCheck.Timeout = healthCheck.Key("timeout").MustDuration(30 * time.Second)
+	cfg.DataSourceHealthCheck.HistorySize = healthCheck.Key("history_size").MustInt(100)
+	cfg.DataSourceHealthCheck.MaxConcurrentChecks = healthCheck.Key("max_concurrent_checks").MustInt(5)
+
+	if cfg.DataSourceHealthCheck.DefaultInterval < cfg.DataSourceHealthCheck.MinInterval {
+		cfg.DataSourceHealthCheck.DefaultInterval = cfg.DataSourceHealthCheck.MinInterval
+	}
+	if cfg.DataSourceHealthCheck.HistorySize < 1 {
+		cfg.DataSourceHealthCheck.HistorySize = 1
+	}
+	if cfg.DataSourceHealthCheck.MaxConcurrentChecks < 1 {
+		cfg.DataSourceHealthCheck.MaxConcurrentChecks = 1
+	}
+}
@@ -0,0 +1,17 @@
+package setting
+
+type QueryExportSettings struct {
+	// Enabled turns on the /api/dashboards/uid/:uid/panels/:panelId/query/export endpoint.
+	Enabled bool
+	// MaxRows is the maximum number of rows, summed across all frames, of a single export.
+	MaxRows int64
+	// MaxBytes is the maximum size of an exported file.
+	MaxBytes int64
+}
+
+func (cfg *Cfg) readQueryExportSettings() {
+	queryExport := cfg.Raw.Section("query_export")
+	cfg.QueryExport.Enabled = queryExport.Key("enabled").MustBool(true)
+	cfg.QueryExport.MaxRows = queryExport.Key("max_rows").MustInt64(1000000)
+	cfg.QueryExport.MaxBytes = queryExport.Key("max_bytes").MustInt64(100 * 1024 * 1024)
+}
@@ -6,10 +6,21 @@ type OrgQuota struct {
 	Dashboard  int64 `target:"dashboard"`
 	ApiKey     int64 `target:"api_key"`
 	AlertRule  int64 `target:"alert_rule"`
+
+	QueriesPerMinute  int64 `target:"queries_per_minute"`
+	DataPointsPerHour int64 `target:"data_points_per_hour"`
 }
 
 type UserQuota struct {
 	Org int64 `target:"org_user"`
+
+	QueriesPerMinute  int64 `target:"queries_per_minute"`
+	DataPointsPerHour int64 `target:"data_points_per_hour"`
+}
+
+type TeamQuota struct {
+	QueriesPerMinute  int64 `target:"queries_per_minute"`
+	DataPointsPerHour int64 `target:"data_points_per_hour"`
 }
 
 type GlobalQuota struct {
@@ -22,12 +33,16 @@ type GlobalQuota struct {
 	AlertRule    int64 `target:"alert_rule"`
 	File         int64 `target:"file"`
 	Correlations int64 `target:"correlations"`
+
+	QueriesPerMinute  int64 `target:"queries_per_minute"`
+	DataPointsPerHour int64 `target:"data_points_per_hour"`
 }
 
 type QuotaSettings struct {
 	Enabled bool
 	Org     OrgQuota
 	User    UserQuota
+	Team    TeamQuota
 	Global  GlobalQuota
 }
 
@@ -43,11 +58,23 @@ func (cfg *Cfg) readQuotaSettings() {
 		Dashboard:  quota.Key("org_dashboard").MustInt64(10),
 		ApiKey:     quota.Key("org_api_key").MustInt64(10),
 		AlertRule:  quota.Key("org_alert_rule").MustInt64(100),
+
+		QueriesPerMinute:  quota.Key("org_queries_per_minute").MustInt64(-1),
+		DataPointsPerHour: quota.Key("org_data_points_per_hour").MustInt64(-1),
 	}
 
 	// per User limits
 	cfg.Quota.User = UserQuota{
 		Org: quota.Key("user_org").MustInt64(10),
+
+		QueriesPerMinute:  quota.Key("user_queries_per_minute").MustInt64(-1),
+		DataPointsPerHour: quota.Key("user_data_points_per_hour").MustInt64(-1),
+	}
+
+	// per Team limits
+	cfg.Quota.Team = TeamQuota{
+		QueriesPerMinute:  quota.Key("team_queries_per_minute").MustInt64(-1),
+		DataPointsPerHour: quota.Key("team_data_points_per_hour").MustInt64(-1),
 	}
 
 	// Global Limits
@@ -61,5 +88,8 @@ func (cfg *Cfg) readQuotaSettings() {
 		File:         quota.Key("global_file").MustInt64(-1),
 		AlertRule:    quota.Key("global_alert_rule").MustInt64(-1),
 		Correlations: quota.Key("global_correlations").MustInt64(-1),
+
+		QueriesPerMinute:  quota.Key("global_queries_per_minute").MustInt64(-1),
+		DataPointsPerHour: quota.Key("global_data_points_per_hour").MustInt64(-1),
 	}
 }
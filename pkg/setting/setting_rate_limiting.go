@@ -0,0 +1,69 @@
+package setting
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	RateLimitStoreMemory      = "memory"
+	RateLimitStoreRemoteCache = "remote_cache"
+)
+
+// RateLimit is a token bucket limit, a zero RequestsPerSecond disables it.
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// RateLimitRouteGroup groups the API routes starting with PathPrefix, every group has its own buckets.
+type RateLimitRouteGroup struct {
+	Name       string
+	PathPrefix string
+}
+
+type RateLimitingSettings struct {
+	Enabled bool
+	// Store is either memory or remote_cache, the latter shares the buckets between the instances of a HA setup.
+	Store string
+	// Limits by identity type: user, service-account, api-key and anonymous.
+	Limits map[string]RateLimit
+	// Org is the limit of all the requests of an org.
+	Org         RateLimit
+	RouteGroups []RateLimitRouteGroup
+}
+
+func (cfg *Cfg) readRateLimitingSettings() {
+	section := cfg.Raw.Section("rate_limiting")
+	cfg.RateLimiting.Enabled = section.Key("enabled").MustBool(false)
+	cfg.RateLimiting.Store = section.Key("store").In(RateLimitStoreMemory, []string{RateLimitStoreMemory, RateLimitStoreRemoteCache})
+
+	readLimit := func(prefix string, requestsPerSecond float64, burst int) RateLimit {
+		limit := RateLimit{
+			RequestsPerSecond: math.Max(section.Key(prefix+"_requests_per_second").MustFloat64(requestsPerSecond), 0),
+			Burst:             section.Key(prefix + "_burst").MustInt(burst),
+		}
+		if limit.Burst <= 0 {
+			limit.Burst = int(math.Max(math.Ceil(limit.RequestsPerSecond), 1))
+		}
+		return limit
+	}
+
+	cfg.RateLimiting.Limits = map[string]RateLimit{
+		"user":            readLimit("user", 50, 100),
+		"service-account": readLimit("service_account", 20, 40),
+		"api-key":         readLimit("api_key", 20, 40),
+		"anonymous":       readLimit("anonymous", 10, 20),
+	}
+	cfg.RateLimiting.Org = readLimit("org", 0, 0)
+
+	cfg.RateLimiting.RouteGroups = nil
+	for _, group := range section.Key("route_groups").Strings(" ") {
+		name, prefix, ok := strings.Cut(group, ":")
+		if !ok || name == "" || prefix == "" {
+			cfg.Logger.Warn("Ignoring invalid rate limiting route group, expected name:path_prefix", "group", group)
+			continue
+		}
+		cfg.RateLimiting.RouteGroups = append(cfg.RateLimiting.RouteGroups, RateLimitRouteGroup{Name: name, PathPrefix: prefix})
+	}
+}
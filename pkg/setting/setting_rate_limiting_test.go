@@ -0,0 +1,33 @@
+package setting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRateLimitingSettings(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg := NewCfg()
+		require.NoError(t, cfg.Load(CommandLineArgs{HomePath: "../../"}))
+		assert.False(t, cfg.RateLimiting.Enabled)
+		assert.Equal(t, RateLimitStoreMemory, cfg.RateLimiting.Store)
+		assert.Equal(t, RateLimit{RequestsPerSecond: 50, Burst: 100}, cfg.RateLimiting.Limits["user"])
+		assert.Equal(t, RateLimit{Burst: 1}, cfg.RateLimiting.Org)
+		assert.Equal(t, []RateLimitRouteGroup{{Name: "query", PathPrefix: "/api/ds/query"}, {Name: "search", PathPrefix: "/api/search"}}, cfg.RateLimiting.RouteGroups)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		cfg := NewCfg()
+		require.NoError(t, cfg.Load(CommandLineArgs{HomePath: "../../", Args: []string{
+			"cfg:rate_limiting.store=remote_cache",
+			"cfg:rate_limiting.api_key_requests_per_second=2.5",
+			"cfg:rate_limiting.api_key_burst=0",
+			"cfg:rate_limiting.route_groups=alerting:/api/v1/provisioning invalid",
+		}}))
+		assert.Equal(t, RateLimitStoreRemoteCache, cfg.RateLimiting.Store)
+		assert.Equal(t, RateLimit{RequestsPerSecond: 2.5, Burst: 3}, cfg.RateLimiting.Limits["api-key"])
+		assert.Equal(t, []RateLimitRouteGroup{{Name: "alerting", PathPrefix: "/api/v1/provisioning"}}, cfg.RateLimiting.RouteGroups)
+	})
+}
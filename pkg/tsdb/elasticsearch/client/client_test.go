@@ -3,6 +3,7 @@ package es
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -287,6 +288,46 @@ func TestClient_Index(t *testing.T) {
 	}
 }
 
+func TestClient_ExecuteEsql(t *testing.T) {
+	var request *http.Request
+	var requestBody []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		request = r
+		buf, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requestBody = buf
+
+		rw.Header().Set("Content-Type", "application/json")
+		_, err = rw.Write([]byte(`{
+			"columns": [{ "name": "count", "type": "long" }],
+			"values": [[9007199254740993]]
+		}`))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(ts.Close)
+
+	ds := DatasourceInfo{
+		URL:        ts.URL,
+		HTTPClient: ts.Client(),
+		Database:   "metrics",
+	}
+	c, err := NewClient(context.Background(), &ds, log.New("test", "test"), tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+
+	res, err := c.ExecuteEsql(&EsqlRequest{Query: "FROM metrics | STATS count = count()", Columnar: true})
+	require.NoError(t, err)
+
+	require.NotNil(t, request)
+	assert.Equal(t, http.MethodPost, request.Method)
+	assert.Equal(t, "/_query", request.URL.Path)
+	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{ "query": "FROM metrics | STATS count = count()", "columnar": true }`, string(requestBody))
+
+	assert.Equal(t, 200, res.Status)
+	require.Len(t, res.Columns, 1)
+	assert.Equal(t, "9007199254740993", res.Values[0][0].(json.Number).String())
+}
+
 func createMultisearchForTest(t *testing.T, c Client, timeRange backend.TimeRange) (*MultiSearchRequest, error) {
 	t.Helper()
 
@@ -0,0 +1,89 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	exp "github.com/grafana/grafana-plugin-sdk-go/experimental/errorsource"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EsqlRequest represents a request to the ES|QL query API
+type EsqlRequest struct {
+	Query    string `json:"query"`
+	Columnar bool   `json:"columnar"`
+}
+
+// EsqlColumn represents a column of an ES|QL response
+type EsqlColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// EsqlResponse represents a response of the ES|QL query API. With a columnar
+// request, Values holds one slice per column instead of one per row.
+type EsqlResponse struct {
+	Status  int                    `json:"status,omitempty"`
+	Error   map[string]interface{} `json:"error"`
+	Columns []EsqlColumn           `json:"columns"`
+	Values  [][]interface{}        `json:"values"`
+}
+
+func (c *baseClientImpl) ExecuteEsql(r *EsqlRequest) (*EsqlResponse, error) {
+	var err error
+	_, span := c.tracer.Start(c.ctx, "datasource.elasticsearch.queryData.executeEsql", trace.WithAttributes(
+		attribute.String("url", c.ds.URL),
+	))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	body, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	res, err := c.executeRequestWithContentType(http.MethodPost, "_query", "", body, "application/json")
+	if err != nil {
+		status := "error"
+		if errors.Is(err, context.Canceled) {
+			status = "cancelled"
+		}
+		lp := []any{"error", err, "status", status, "duration", time.Since(start), "stage", StageDatabaseRequest}
+		sourceErr := exp.Error{}
+		if errors.As(err, &sourceErr) {
+			lp = append(lp, "statusSource", sourceErr.Source())
+		}
+		c.logger.Error("Error received from Elasticsearch", lp...)
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	c.logger.Info("Response received from Elasticsearch", "status", "ok", "statusCode", res.StatusCode, "contentLength", res.ContentLength, "duration", time.Since(start), "stage", StageDatabaseRequest)
+
+	var er EsqlResponse
+	dec := json.NewDecoder(res.Body)
+	// Keep long values exact, they are converted to the column type when parsing the response
+	dec.UseNumber()
+	if err = dec.Decode(&er); err != nil {
+		c.logger.Error("Failed to decode response from Elasticsearch", "error", err, "duration", time.Since(start))
+		return nil, err
+	}
+	er.Status = res.StatusCode
+
+	return &er, nil
+}
@@ -58,6 +58,41 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 		return errorsource.AddPluginErrorToResponse(e.dataQueries[0].RefID, response, err), nil
 	}
 
+	// ES|QL queries are sent one by one to the query API, all other queries are
+	// grouped in a single multisearch request.
+	esqlQueries := make([]*Query, 0)
+	searchQueries := make([]*Query, 0, len(queries))
+	for _, q := range queries {
+		if isEsqlQuery(q) {
+			esqlQueries = append(esqlQueries, q)
+		} else {
+			searchQueries = append(searchQueries, q)
+		}
+	}
+
+	if len(searchQueries) > 0 {
+		searchResponse, err := e.executeSearch(searchQueries, start)
+		if err != nil {
+			// the error only concerns the search queries, the ES|QL queries are still executed
+			for _, q := range searchQueries {
+				errorsource.AddPluginErrorToResponse(q.RefID, response, err)
+			}
+		} else {
+			for refID, res := range searchResponse.Responses {
+				response.Responses[refID] = res
+			}
+		}
+	}
+
+	for _, q := range esqlQueries {
+		response.Responses[q.RefID] = e.executeEsqlQuery(q)
+	}
+
+	return response, nil
+}
+
+func (e *elasticsearchDataQuery) executeSearch(queries []*Query, start time.Time) (*backend.QueryDataResponse, error) {
+	response := backend.NewQueryDataResponse()
 	ms := e.client.MultiSearch()
 
 	for _, q := range queries {
@@ -74,14 +109,14 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 	if err != nil {
 		mqs, _ := json.Marshal(e.dataQueries)
 		e.logger.Error("Failed to build multisearch request", "error", err, "queriesLength", len(queries), "queries", string(mqs), "duration", time.Since(start), "stage", es.StagePrepareRequest)
-		return errorsource.AddPluginErrorToResponse(e.dataQueries[0].RefID, response, err), nil
+		return errorsource.AddPluginErrorToResponse(queries[0].RefID, response, err), nil
 	}
 
 	e.logger.Info("Prepared request", "queriesLength", len(queries), "duration", time.Since(start), "stage", es.StagePrepareRequest)
 	res, err := e.client.ExecuteMultisearch(req)
 	if err != nil {
 		// We are returning error containing the source that was added trough errorsource.Middleware
-		return errorsource.AddErrorToResponse(e.dataQueries[0].RefID, response, err), nil
+		return errorsource.AddErrorToResponse(queries[0].RefID, response, err), nil
 	}
 
 	return parseResponse(e.ctx, res.Responses, queries, e.client.GetConfiguredFields(), e.keepLabelsInResponse, e.logger, e.tracer)
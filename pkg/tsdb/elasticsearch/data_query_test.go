@@ -1806,6 +1806,9 @@ type fakeClient struct {
 	multiSearchError    error
 	builder             *es.MultiSearchRequestBuilder
 	multisearchRequests []*es.MultiSearchRequest
+	esqlResponse        *es.EsqlResponse
+	esqlError           error
+	esqlRequests        []*es.EsqlRequest
 }
 
 func newFakeClient() *fakeClient {
@@ -1819,6 +1822,7 @@ func newFakeClient() *fakeClient {
 		configuredFields:    configuredFields,
 		multisearchRequests: make([]*es.MultiSearchRequest, 0),
 		multiSearchResponse: &es.MultiSearchResponse{},
+		esqlResponse:        &es.EsqlResponse{},
 	}
 }
 
@@ -1836,6 +1840,11 @@ func (c *fakeClient) MultiSearch() *es.MultiSearchRequestBuilder {
 	return c.builder
 }
 
+func (c *fakeClient) ExecuteEsql(r *es.EsqlRequest) (*es.EsqlResponse, error) {
+	c.esqlRequests = append(c.esqlRequests, r)
+	return c.esqlResponse, c.esqlError
+}
+
 func newDataQuery(body string) (backend.QueryDataRequest, error) {
 	return backend.QueryDataRequest{
 		Queries: []backend.DataQuery{
@@ -0,0 +1,267 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/experimental/errorsource"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+const esqlQueryType = "esql"
+
+var esqlTimeFilterRegex = regexp.MustCompile(`\$__timeFilter(?:\(\s*([^)]*?)\s*\))?`)
+
+func isEsqlQuery(query *Query) bool {
+	return query.QueryType == esqlQueryType
+}
+
+func (e *elasticsearchDataQuery) executeEsqlQuery(q *Query) backend.DataResponse {
+	start := time.Now()
+	if strings.TrimSpace(q.RawQuery) == "" {
+		return errorsource.Response(errorsource.DownstreamError(errors.New("ES|QL query is empty"), false))
+	}
+
+	query := interpolateEsqlQuery(q, e.client.GetConfiguredFields().TimeField)
+	res, err := e.client.ExecuteEsql(&es.EsqlRequest{Query: query, Columnar: true})
+	if err != nil {
+		// We are returning error containing the source that was added trough errorsource.Middleware
+		return errorsource.Response(err)
+	}
+
+	if res.Error != nil {
+		me, _ := json.Marshal(res.Error)
+		e.logger.Error("Processing error response from Elasticsearch", "error", string(me), "query", query)
+		return errorsource.Response(errorsource.DownstreamError(errors.New(getErrorReason(res.Error)), false))
+	}
+
+	frames, err := esqlResponseToFrames(res, query)
+	if err != nil {
+		e.logger.Error("Failed to process ES|QL response", "error", err, "query", query, "stage", es.StageParseResponse)
+		return errorsource.Response(errorsource.PluginError(err, false))
+	}
+
+	e.logger.Info("Finished processing of ES|QL response", "duration", time.Since(start), "stage", es.StageParseResponse)
+	return backend.DataResponse{Frames: frames}
+}
+
+// interpolateEsqlQuery replaces the time range and interval macros of an ES|QL query.
+// $__timeFilter without argument filters on the time field configured in the data source.
+func interpolateEsqlQuery(q *Query, timeField string) string {
+	from := esqlDatetime(q.TimeRange.From)
+	to := esqlDatetime(q.TimeRange.To)
+
+	query := esqlTimeFilterRegex.ReplaceAllStringFunc(q.RawQuery, func(match string) string {
+		field := quoteEsqlIdentifier(timeField)
+		if arg := esqlTimeFilterRegex.FindStringSubmatch(match)[1]; arg != "" {
+			field = arg
+		}
+		return fmt.Sprintf("(%s >= %s AND %s <= %s)", field, from, field, to)
+	})
+	query = strings.ReplaceAll(query, "$__timeFrom", from)
+	query = strings.ReplaceAll(query, "$__timeTo", to)
+	query = strings.ReplaceAll(query, "$__interval_ms", strconv.FormatInt(q.Interval.Milliseconds(), 10))
+	query = strings.ReplaceAll(query, "$__interval", fmt.Sprintf("%d milliseconds", q.Interval.Milliseconds()))
+
+	return query
+}
+
+func esqlDatetime(t time.Time) string {
+	return fmt.Sprintf("TO_DATETIME(\"%s\")", t.UTC().Format("2006-01-02T15:04:05.000Z"))
+}
+
+func quoteEsqlIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// esqlResponseToFrames converts a columnar ES|QL response to a data frame. Responses
+// with a time column and numeric columns are returned as wide time series, so they
+// can be used in alert rules and expressions.
+func esqlResponseToFrames(res *es.EsqlResponse, executedQuery string) (data.Frames, error) {
+	frame := data.NewFrame("")
+	for i, column := range res.Columns {
+		var values []interface{}
+		if i < len(res.Values) {
+			values = res.Values[i]
+		}
+		field, err := esqlColumnToField(column, values)
+		if err != nil {
+			return nil, err
+		}
+		frame.Fields = append(frame.Fields, field)
+	}
+	frame.Meta = &data.FrameMeta{
+		ExecutedQueryString:    executedQuery,
+		PreferredVisualization: data.VisTypeTable,
+	}
+
+	schema := frame.TimeSeriesSchema()
+	if schema.Type == data.TimeSeriesTypeNot || schema.TimeIsNullable {
+		return data.Frames{frame}, nil
+	}
+
+	frame = sortFrameByTime(frame, schema.TimeIndex)
+	if schema.Type == data.TimeSeriesTypeLong {
+		var err error
+		frame, err = data.LongToWide(frame, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	frame.Meta = &data.FrameMeta{
+		Type:                   data.FrameTypeTimeSeriesWide,
+		ExecutedQueryString:    executedQuery,
+		PreferredVisualization: data.VisTypeGraph,
+	}
+
+	return data.Frames{frame}, nil
+}
+
+func esqlColumnToField(column es.EsqlColumn, values []interface{}) (*data.Field, error) {
+	var fieldType data.FieldType
+	switch column.Type {
+	case "date", "date_nanos":
+		fieldType = data.FieldTypeNullableTime
+	case "double", "float", "half_float", "scaled_float", "unsigned_long", "counter_double":
+		fieldType = data.FieldTypeNullableFloat64
+	case "long", "integer", "short", "byte", "counter_long", "counter_integer":
+		fieldType = data.FieldTypeNullableInt64
+	case "boolean":
+		fieldType = data.FieldTypeNullableBool
+	default:
+		fieldType = data.FieldTypeNullableString
+	}
+
+	field := data.NewFieldFromFieldType(fieldType, len(values))
+	field.Name = column.Name
+	hasNulls := false
+	for i, v := range values {
+		if v == nil {
+			hasNulls = true
+			continue
+		}
+		converted, err := convertEsqlValue(fieldType, v)
+		if err != nil {
+			// Multi-valued fields are returned as arrays, we keep such columns as JSON
+			return esqlJSONField(column.Name, values)
+		}
+		field.Set(i, converted)
+	}
+
+	if hasNulls {
+		return field, nil
+	}
+
+	// Columns without nulls use non nullable fields, time series detection requires it for the time column
+	nonNullable := data.NewFieldFromFieldType(fieldType.NonNullableType(), field.Len())
+	nonNullable.Name = column.Name
+	for i := 0; i < field.Len(); i++ {
+		v, _ := field.ConcreteAt(i)
+		nonNullable.Set(i, v)
+	}
+	return nonNullable, nil
+}
+
+func convertEsqlValue(fieldType data.FieldType, v interface{}) (interface{}, error) {
+	switch fieldType {
+	case data.FieldTypeNullableTime:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected date value %v", v)
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, err
+		}
+		return &t, nil
+	case data.FieldTypeNullableFloat64:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("unexpected numeric value %v", v)
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return &f, nil
+	case data.FieldTypeNullableInt64:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("unexpected integer value %v", v)
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return nil, err
+		}
+		return &i, nil
+	case data.FieldTypeNullableBool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("unexpected boolean value %v", v)
+		}
+		return &b, nil
+	default:
+		if s, ok := v.(string); ok {
+			return &s, nil
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		s := string(b)
+		return &s, nil
+	}
+}
+
+func esqlJSONField(name string, values []interface{}) (*data.Field, error) {
+	field := data.NewFieldFromFieldType(data.FieldTypeNullableJSON, len(values))
+	field.Name = name
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		raw := json.RawMessage(b)
+		field.Set(i, &raw)
+	}
+	return field, nil
+}
+
+// sortFrameByTime returns a copy of the frame with the rows sorted by ascending time,
+// as ES|QL does not guarantee any order unless the query sorts the results.
+func sortFrameByTime(frame *data.Frame, timeIndex int) *data.Frame {
+	timeField := frame.Fields[timeIndex]
+	rows := make([]int, frame.Rows())
+	for i := range rows {
+		rows[i] = i
+	}
+	sort.SliceStable(rows, func(a, b int) bool {
+		return timeField.At(rows[a]).(time.Time).Before(timeField.At(rows[b]).(time.Time))
+	})
+
+	sorted := data.NewFrame(frame.Name)
+	sorted.Meta = frame.Meta
+	for _, f := range frame.Fields {
+		field := data.NewFieldFromFieldType(f.Type(), len(rows))
+		field.Name = f.Name
+		field.Labels = f.Labels
+		field.Config = f.Config
+		for i, row := range rows {
+			field.Set(i, f.CopyAt(row))
+		}
+		sorted.Fields = append(sorted.Fields, field)
+	}
+	return sorted
+}
@@ -0,0 +1,196 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+func TestInterpolateEsqlQuery(t *testing.T) {
+	q := &Query{
+		RawQuery: "FROM logs | WHERE $__timeFilter AND $__timeFilter(event.created) AND @timestamp < $__timeTo | STATS count() BY BUCKET(@timestamp, $__interval)",
+		Interval: 30 * time.Second,
+		TimeRange: backend.TimeRange{
+			From: time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC),
+			To:   time.Date(2018, 5, 15, 17, 55, 0, 0, time.UTC),
+		},
+	}
+
+	require.Equal(t,
+		"FROM logs | WHERE (`@timestamp` >= TO_DATETIME(\"2018-05-15T17:50:00.000Z\") AND `@timestamp` <= TO_DATETIME(\"2018-05-15T17:55:00.000Z\"))"+
+			" AND (event.created >= TO_DATETIME(\"2018-05-15T17:50:00.000Z\") AND event.created <= TO_DATETIME(\"2018-05-15T17:55:00.000Z\"))"+
+			" AND @timestamp < TO_DATETIME(\"2018-05-15T17:55:00.000Z\") | STATS count() BY BUCKET(@timestamp, 30000 milliseconds)",
+		interpolateEsqlQuery(q, "@timestamp"),
+	)
+}
+
+func TestEsqlResponseToFrames(t *testing.T) {
+	t.Run("returns a table without time column", func(t *testing.T) {
+		res := esqlResponseFromJSON(t, `{
+			"columns": [{ "name": "host", "type": "keyword" }, { "name": "count", "type": "long" }, { "name": "up", "type": "boolean" }],
+			"values": [["a", "b"], [9007199254740993, 2], [true, null]]
+		}`)
+
+		frames, err := esqlResponseToFrames(res, "FROM logs")
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		frame := frames[0]
+		require.Equal(t, data.VisTypeTable, frame.Meta.PreferredVisualization)
+		require.Equal(t, "FROM logs", frame.Meta.ExecutedQueryString)
+		require.Equal(t, data.FieldTypeString, frame.Fields[0].Type())
+		require.Equal(t, int64(9007199254740993), frame.Fields[1].At(0))
+		require.Equal(t, data.FieldTypeNullableBool, frame.Fields[2].Type())
+		require.Nil(t, frame.Fields[2].At(1))
+	})
+
+	t.Run("returns a wide time series sorted by time", func(t *testing.T) {
+		res := esqlResponseFromJSON(t, `{
+			"columns": [{ "name": "avg", "type": "double" }, { "name": "bucket", "type": "date" }],
+			"values": [[2.5, 1.5], ["2018-05-15T17:51:00.000Z", "2018-05-15T17:50:00.000Z"]]
+		}`)
+
+		frames, err := esqlResponseToFrames(res, "")
+		require.NoError(t, err)
+		frame := frames[0]
+		require.Equal(t, data.FrameTypeTimeSeriesWide, frame.Meta.Type)
+		require.Equal(t, data.VisTypeGraph, frame.Meta.PreferredVisualization)
+		require.Equal(t, time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC), frame.Fields[1].At(0))
+		require.Equal(t, 1.5, frame.Fields[0].At(0))
+	})
+
+	t.Run("converts long time series to wide with labels", func(t *testing.T) {
+		res := esqlResponseFromJSON(t, `{
+			"columns": [{ "name": "count", "type": "long" }, { "name": "bucket", "type": "date" }, { "name": "host", "type": "keyword" }],
+			"values": [[1, 2, 3, 4], ["2018-05-15T17:50:00.000Z", "2018-05-15T17:50:00.000Z", "2018-05-15T17:51:00.000Z", "2018-05-15T17:51:00.000Z"], ["a", "b", "a", "b"]]
+		}`)
+
+		frames, err := esqlResponseToFrames(res, "")
+		require.NoError(t, err)
+		frame := frames[0]
+		require.Equal(t, data.FrameTypeTimeSeriesWide, frame.Meta.Type)
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, data.Labels{"host": "a"}, frame.Fields[1].Labels)
+		require.Equal(t, data.Labels{"host": "b"}, frame.Fields[2].Labels)
+	})
+
+	t.Run("keeps multi-valued columns as JSON", func(t *testing.T) {
+		res := esqlResponseFromJSON(t, `{
+			"columns": [{ "name": "ports", "type": "integer" }],
+			"values": [[[80, 443], 22]]
+		}`)
+
+		frames, err := esqlResponseToFrames(res, "")
+		require.NoError(t, err)
+		field := frames[0].Fields[0]
+		require.Equal(t, data.FieldTypeNullableJSON, field.Type())
+		require.JSONEq(t, `[80, 443]`, string(*field.At(0).(*json.RawMessage)))
+	})
+}
+
+func TestExecuteEsqlQuery(t *testing.T) {
+	from := time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC)
+	to := time.Date(2018, 5, 15, 17, 55, 0, 0, time.UTC)
+
+	t.Run("sends ES|QL queries to the query API", func(t *testing.T) {
+		c := newFakeClient()
+		c.esqlResponse = esqlResponseFromJSON(t, `{
+			"columns": [{ "name": "count", "type": "long" }],
+			"values": [[42]]
+		}`)
+
+		res, err := executeElasticsearchDataQuery(c, `{ "queryType": "esql", "query": "FROM logs | WHERE $__timeFilter | STATS count = count()" }`, from, to)
+		require.NoError(t, err)
+		require.Empty(t, c.multisearchRequests)
+		require.Len(t, c.esqlRequests, 1)
+		require.True(t, c.esqlRequests[0].Columnar)
+		require.Contains(t, c.esqlRequests[0].Query, "`@timestamp` >= TO_DATETIME(\"2018-05-15T17:50:00.000Z\")")
+
+		dataRes := res.Responses["A"]
+		require.NoError(t, dataRes.Error)
+		require.Equal(t, int64(42), dataRes.Frames[0].Fields[0].At(0))
+	})
+
+	t.Run("returns the reason of error responses", func(t *testing.T) {
+		c := newFakeClient()
+		c.esqlResponse = esqlResponseFromJSON(t, `{
+			"status": 400,
+			"error": { "type": "verification_exception", "reason": "Unknown column [foo]" }
+		}`)
+
+		res, err := executeElasticsearchDataQuery(c, `{ "queryType": "esql", "query": "FROM logs | KEEP foo" }`, from, to)
+		require.NoError(t, err)
+		dataRes := res.Responses["A"]
+		require.EqualError(t, dataRes.Error, "Unknown column [foo]")
+		require.Equal(t, backend.ErrorSourceDownstream, dataRes.ErrorSource)
+	})
+
+	t.Run("keeps the ES|QL responses when the search queries fail", func(t *testing.T) {
+		c := newFakeClient()
+		c.esqlResponse = esqlResponseFromJSON(t, `{
+			"columns": [{ "name": "count", "type": "long" }],
+			"values": [[42]]
+		}`)
+		// a bucket key that isn't a timestamp fails the parsing of the date histogram
+		c.multiSearchResponse = &es.MultiSearchResponse{
+			Responses: []*es.SearchResponse{
+				{Aggregations: map[string]interface{}{"2": map[string]interface{}{
+					"buckets": []interface{}{map[string]interface{}{"key": "invalid", "doc_count": 1}},
+				}}},
+			},
+		}
+
+		dataRequest := backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{
+					JSON:      json.RawMessage(`{ "queryType": "esql", "query": "FROM logs | STATS count = count()" }`),
+					TimeRange: backend.TimeRange{From: from, To: to},
+					RefID:     "A",
+				},
+				{
+					JSON: json.RawMessage(`{
+						"metrics": [{ "type": "count", "id": "1" }],
+						"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "2" }]
+					}`),
+					TimeRange: backend.TimeRange{From: from, To: to},
+					RefID:     "B",
+				},
+			},
+		}
+		query := newElasticsearchDataQuery(context.Background(), c, &dataRequest, log.New("test.logger"), tracing.InitializeTracerForTest())
+		res, err := query.execute()
+		require.NoError(t, err)
+		require.Len(t, c.multisearchRequests, 1)
+
+		require.NoError(t, res.Responses["A"].Error)
+		require.Equal(t, int64(42), res.Responses["A"].Frames[0].Fields[0].At(0))
+		require.Error(t, res.Responses["B"].Error)
+	})
+
+	t.Run("returns an error for empty queries", func(t *testing.T) {
+		c := newFakeClient()
+		res, err := executeElasticsearchDataQuery(c, `{ "queryType": "esql", "query": " " }`, from, to)
+		require.NoError(t, err)
+		require.Error(t, res.Responses["A"].Error)
+		require.Empty(t, c.esqlRequests)
+	})
+}
+
+func esqlResponseFromJSON(t *testing.T, body string) *es.EsqlResponse {
+	t.Helper()
+	var res es.EsqlResponse
+	dec := json.NewDecoder(bytes.NewBufferString(body))
+	dec.UseNumber()
+	require.NoError(t, dec.Decode(&res))
+	return &res
+}
@@ -9,6 +9,7 @@ import (
 
 // Query represents the time series query model of the datasource
 type Query struct {
+	QueryType     string       `json:"queryType"`
 	RawQuery      string       `json:"query"`
 	BucketAggs    []*BucketAgg `json:"bucketAggs"`
 	Metrics       []*MetricAgg `json:"metrics"`
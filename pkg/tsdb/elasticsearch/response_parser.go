@@ -1089,8 +1089,13 @@ func findAgg(target *Query, aggID string) (*BucketAgg, error) {
 }
 
 func getErrorFromElasticResponse(response *es.SearchResponse) string {
+	return getErrorReason(response.Error)
+}
+
+// getErrorReason returns the most specific reason of an Elasticsearch error object
+func getErrorReason(elasticError map[string]interface{}) string {
 	var errorString string
-	json := simplejson.NewFromAny(response.Error)
+	json := simplejson.NewFromAny(elasticError)
 	reason := json.Get("reason").MustString()
 	rootCauseReason := json.Get("root_cause").GetIndex(0).Get("reason").MustString()
 	causedByReason := json.Get("caused_by").Get("reason").MustString()
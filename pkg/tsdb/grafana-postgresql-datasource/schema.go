@@ -0,0 +1,42 @@
+package postgres
+
+import "github.com/grafana/grafana/pkg/tsdb/sqlschema"
+
+// postgresSchemaQueries introspects the schema with information_schema and the catalog. A connection can only see
+// the database it's connected to, it's the only database listed and the database parameter is ignored.
+type postgresSchemaQueries struct{}
+
+var _ sqlschema.Queries = postgresSchemaQueries{}
+
+func (postgresSchemaQueries) Databases() string {
+	return `SELECT current_database()`
+}
+
+func (postgresSchemaQueries) Schemas(string) (string, []any) {
+	return `SELECT schema_name FROM information_schema.schemata
+		WHERE schema_name NOT IN ('information_schema', 'pg_catalog', 'pg_toast') AND schema_name NOT LIKE 'pg_temp_%'
+		ORDER BY schema_name`, nil
+}
+
+func (postgresSchemaQueries) Tables(_, schema string) (string, []any) {
+	return `SELECT table_name FROM information_schema.tables
+		WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema())
+		ORDER BY table_name`, []any{schema}
+}
+
+func (postgresSchemaQueries) Columns(_, schema, table string) (string, []any) {
+	return `SELECT column_name, data_type, is_nullable FROM information_schema.columns
+		WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND table_name = $2
+		ORDER BY ordinal_position`, []any{schema, table}
+}
+
+func (postgresSchemaQueries) Indexes(_, schema, table string) (string, []any) {
+	return `SELECT i.relname, a.attname, ix.indisunique, ix.indisprimary
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(ix.indkey)
+		WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND t.relname = $2
+		ORDER BY i.relname, array_position(ix.indkey::int2[], a.attnum)`, []any{schema, table}
+}
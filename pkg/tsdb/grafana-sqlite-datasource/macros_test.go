@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroEngine(t *testing.T) {
+	engine := newSQLiteMacroEngine(backend.NewLoggerWith("logger", "test"))
+	query := &backend.DataQuery{JSON: []byte("{}")}
+
+	from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+	to := from.Add(5 * time.Minute)
+	timeRange := backend.TimeRange{From: from, To: to}
+
+	t.Run("interpolate __time function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__time(time_column)")
+		require.NoError(t, err)
+		require.Equal(t, `select CAST(strftime('%s', time_column) AS INTEGER) AS "time"`, sql)
+	})
+
+	t.Run("interpolate __timeGroup function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column , '5m')")
+		require.NoError(t, err)
+		sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupAlias(time_column,'5m')")
+		require.NoError(t, err)
+
+		require.Equal(t, "GROUP BY CAST(strftime('%s', time_column) AS INTEGER) / 300 * 300", sql)
+		require.Equal(t, sql+` AS "time"`, sql2)
+	})
+
+	t.Run("interpolate __timeFilter function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column)")
+		require.NoError(t, err)
+		require.Equal(t, "WHERE datetime(time_column) BETWEEN datetime(1523556000, 'unixepoch') AND datetime(1523556300, 'unixepoch')", sql)
+	})
+
+	t.Run("interpolate __timeFrom and __timeTo functions", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__timeFrom(), $__timeTo()")
+		require.NoError(t, err)
+		require.Equal(t, "select datetime(1523556000, 'unixepoch'), datetime(1523556300, 'unixepoch')", sql)
+	})
+
+	t.Run("interpolate __unixEpochFilter and __unixEpochGroup functions", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochGroupAlias(ts, '1m') WHERE $__unixEpochFilter(ts)")
+		require.NoError(t, err)
+		require.Equal(t, `select ts / 60 * 60 AS "time" WHERE ts >= 1523556000 AND ts <= 1523556300`, sql)
+	})
+
+	t.Run("unknown macro", func(t *testing.T) {
+		_, err := engine.Interpolate(query, timeRange, "select $__unknown(ts)")
+		require.ErrorContains(t, err, "unknown macro __unknown")
+	})
+}
@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	errMissingPath    = errors.New("path to the database file is required")
+	errPathNotAllowed = errors.New("path is not allowed, it must be listed in sqlite_allowed_paths of the [sql_datasources] configuration")
+)
+
+// allowedPath resolves the path of a database file and checks it's one of the allowed files, or inside one of the
+// allowed directories. Symbolic links are resolved first, so that a link can't point outside of the allowed paths.
+func allowedPath(path string, allowed []string) (string, error) {
+	if path == "" {
+		return "", errMissingPath
+	}
+	if strings.HasPrefix(path, "file:") || strings.ContainsAny(path, "?#") {
+		return "", fmt.Errorf("invalid path %q, it must be a file path without URI parameters", path)
+	}
+
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %q: %w", path, err)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %q: %w", path, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("invalid path %q: it is a directory", path)
+	}
+
+	for _, a := range allowed {
+		allowedResolved, err := resolvePath(a)
+		if err != nil {
+			continue
+		}
+		if resolved == allowedResolved || strings.HasPrefix(resolved, allowedResolved+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", errPathNotAllowed, path)
+}
+
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
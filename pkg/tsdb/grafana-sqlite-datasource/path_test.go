@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowedPath(t *testing.T) {
+	dir := t.TempDir()
+	allowedDir := filepath.Join(dir, "allowed")
+	require.NoError(t, os.Mkdir(allowedDir, 0750))
+
+	inside := filepath.Join(allowedDir, "metrics.db")
+	outside := filepath.Join(dir, "other.db")
+	for _, f := range []string{inside, outside} {
+		require.NoError(t, os.WriteFile(f, nil, 0600))
+	}
+
+	t.Run("file inside an allowed directory", func(t *testing.T) {
+		path, err := allowedPath(inside, []string{allowedDir})
+		require.NoError(t, err)
+		resolved, err := filepath.EvalSymlinks(inside)
+		require.NoError(t, err)
+		require.Equal(t, resolved, path)
+	})
+
+	t.Run("allowed file", func(t *testing.T) {
+		_, err := allowedPath(outside, []string{outside})
+		require.NoError(t, err)
+	})
+
+	t.Run("file outside of the allowed paths", func(t *testing.T) {
+		_, err := allowedPath(outside, []string{allowedDir})
+		require.ErrorIs(t, err, errPathNotAllowed)
+
+		_, err = allowedPath(filepath.Join(allowedDir, "..", "other.db"), []string{allowedDir})
+		require.ErrorIs(t, err, errPathNotAllowed)
+
+		_, err = allowedPath(outside, nil)
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("directory with the allowed directory as prefix", func(t *testing.T) {
+		sibling := allowedDir + "-sibling"
+		require.NoError(t, os.Mkdir(sibling, 0750))
+		f := filepath.Join(sibling, "metrics.db")
+		require.NoError(t, os.WriteFile(f, nil, 0600))
+
+		_, err := allowedPath(f, []string{allowedDir})
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("symbolic link pointing outside of the allowed paths", func(t *testing.T) {
+		link := filepath.Join(allowedDir, "link.db")
+		require.NoError(t, os.Symlink(outside, link))
+
+		_, err := allowedPath(link, []string{allowedDir})
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("invalid paths", func(t *testing.T) {
+		_, err := allowedPath("", []string{allowedDir})
+		require.ErrorIs(t, err, errMissingPath)
+
+		_, err = allowedPath(inside+"?mode=rw", []string{allowedDir})
+		require.Error(t, err)
+
+		_, err = allowedPath(allowedDir, []string{dir})
+		require.ErrorContains(t, err, "is a directory")
+
+		_, err = allowedPath(filepath.Join(allowedDir, "missing.db"), []string{allowedDir})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,36 @@
+package sqlite
+
+import "github.com/grafana/grafana/pkg/tsdb/sqlschema"
+
+// sqliteSchemaQueries introspects the schema of the database file with the sqlite_master table and the table valued
+// pragma functions. SQLite has no schemas and sqlite_master only describes the main database of the connection, it's
+// the only database listed and the database parameter is ignored.
+type sqliteSchemaQueries struct{}
+
+var _ sqlschema.Queries = sqliteSchemaQueries{}
+
+func (sqliteSchemaQueries) Databases() string {
+	return `SELECT name FROM pragma_database_list WHERE name = 'main'`
+}
+
+func (sqliteSchemaQueries) Schemas(string) (string, []any) {
+	return "", nil
+}
+
+func (sqliteSchemaQueries) Tables(string, string) (string, []any) {
+	return `SELECT name FROM sqlite_master
+		WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite\_%' ESCAPE '\'
+		ORDER BY name`, nil
+}
+
+func (sqliteSchemaQueries) Columns(_, _, table string) (string, []any) {
+	return `SELECT name, type, CASE WHEN "notnull" = 0 AND pk = 0 THEN 'YES' ELSE 'NO' END
+		FROM pragma_table_info(?)
+		ORDER BY cid`, []any{table}
+}
+
+func (sqliteSchemaQueries) Indexes(_, _, table string) (string, []any) {
+	return `SELECT il.name, ii.name, il."unique", il.origin = 'pk'
+		FROM pragma_index_list(?) AS il, pragma_index_info(il.name) AS ii
+		ORDER BY il.name, ii.seqno`, []any{table}
+}
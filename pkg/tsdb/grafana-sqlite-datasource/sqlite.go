@@ -0,0 +1,200 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/grafana-sqlite-datasource/sqleng"
+)
+
+// driverName is the name of the read-only driver the data source connects with
+const driverName = "grafana-sqlite-datasource"
+
+var registerDriver sync.Once
+
+type Service struct {
+	im     instancemgmt.InstanceManager
+	logger log.Logger
+}
+
+func ProvideService(cfg *setting.Cfg) *Service {
+	logger := backend.NewLoggerWith("logger", "tsdb.sqlite")
+	registerDriver.Do(func() {
+		sql.Register(driverName, &sqlite3.SQLiteDriver{ConnectHook: readOnlyConnectHook})
+	})
+	return &Service{
+		im:     datasource.NewInstanceManager(newInstanceSettings(cfg, logger)),
+		logger: logger,
+	}
+}
+
+func newInstanceSettings(cfg *setting.Cfg, logger log.Logger) datasource.InstanceFactoryFunc {
+	return func(ctx context.Context, settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+		jsonData := sqleng.JsonData{
+			MaxOpenConns:    cfg.SqlDatasourceMaxOpenConnsDefault,
+			MaxIdleConns:    cfg.SqlDatasourceMaxIdleConnsDefault,
+			ConnMaxLifetime: cfg.SqlDatasourceMaxConnLifetimeDefault,
+		}
+
+		err := json.Unmarshal(settings.JSONData, &jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("error reading settings: %w", err)
+		}
+
+		path, err := allowedPath(jsonData.Path, cfg.SqlDatasourceSQLiteAllowedPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		dsInfo := sqleng.DataSourceInfo{
+			JsonData: jsonData,
+			URL:      path,
+			ID:       settings.ID,
+			Updated:  settings.Updated,
+			UID:      settings.UID,
+		}
+
+		config := sqleng.DataPluginConfiguration{
+			DSInfo:            dsInfo,
+			TimeColumnNames:   []string{"time", "time_sec"},
+			MetricColumnTypes: []string{"TEXT", "CHAR", "VARCHAR", "NCHAR", "NVARCHAR", "CLOB"},
+			RowLimit:          cfg.DataProxyRowLimit,
+			SchemaQueries:     sqliteSchemaQueries{},
+		}
+
+		queryResultTransformer := sqliteQueryResultTransformer{
+			userError: cfg.UserFacingDefaultError,
+		}
+
+		db, err := sql.Open(driverName, connectionString(path))
+		if err != nil {
+			return nil, err
+		}
+
+		db.SetMaxOpenConns(config.DSInfo.JsonData.MaxOpenConns)
+		db.SetMaxIdleConns(config.DSInfo.JsonData.MaxIdleConns)
+		db.SetConnMaxLifetime(time.Duration(config.DSInfo.JsonData.ConnMaxLifetime) * time.Second)
+
+		return sqleng.NewQueryDataHandler(cfg.UserFacingDefaultError, db, config, &queryResultTransformer, newSQLiteMacroEngine(logger), logger)
+	}
+}
+
+// connectionString opens the database file read-only. The connection is also made query only, so that the database
+// can't be changed even when the file permissions allow it.
+func connectionString(path string) string {
+	return "file:" + (&url.URL{Path: path}).EscapedPath() + "?mode=ro&_query_only=true&_busy_timeout=5000"
+}
+
+// readOnlyConnectHook installs an authorizer on every connection that only allows reading. It denies, among others,
+// ATTACH, which would give access to files outside of the allowed paths, and VACUUM INTO, which writes a file.
+func readOnlyConnectHook(conn *sqlite3.SQLiteConn) error {
+	conn.RegisterAuthorizer(func(action int, arg1, arg2, _ string) int {
+		switch action {
+		case sqlite3.SQLITE_SELECT, sqlite3.SQLITE_READ, sqlite3.SQLITE_FUNCTION, sqlite3.SQLITE_RECURSIVE:
+			return sqlite3.SQLITE_OK
+		case sqlite3.SQLITE_PRAGMA:
+			// introspection pragmas can be read, but pragmas can't be set
+			if arg2 == "" && allowedPragmas[strings.ToLower(arg1)] {
+				return sqlite3.SQLITE_OK
+			}
+		}
+		return sqlite3.SQLITE_DENY
+	})
+	return nil
+}
+
+var allowedPragmas = map[string]bool{
+	"database_list": true,
+	"index_info":    true,
+	"index_list":    true,
+	"index_xinfo":   true,
+	"table_info":    true,
+	"table_list":    true,
+	"table_xinfo":   true,
+}
+
+func (s *Service) getDataSourceHandler(ctx context.Context, pluginCtx backend.PluginContext) (*sqleng.DataSourceHandler, error) {
+	i, err := s.im.Get(ctx, pluginCtx)
+	if err != nil {
+		return nil, err
+	}
+	instance := i.(*sqleng.DataSourceHandler)
+	return instance, nil
+}
+
+func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	dsHandler, err := s.getDataSourceHandler(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	return dsHandler.QueryData(ctx, req)
+}
+
+// CheckHealth opens the database file and runs a query
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	dsHandler, err := s.getDataSourceHandler(ctx, req.PluginContext)
+	if err != nil {
+		if errors.Is(err, errPathNotAllowed) || errors.Is(err, errMissingPath) {
+			return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: err.Error()}, nil
+		}
+		return nil, err
+	}
+
+	if err := dsHandler.Ping(); err != nil {
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: dsHandler.TransformQueryError(s.logger, err).Error()}, nil
+	}
+	return &backend.CheckHealthResult{Status: backend.HealthStatusOk, Message: "Database Connection OK"}, nil
+}
+
+// CallResource serves the schema introspection resources of the database
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	dsHandler, err := s.getDataSourceHandler(ctx, req.PluginContext)
+	if err != nil {
+		return err
+	}
+	return dsHandler.CallResource(ctx, req, sender)
+}
+
+type sqliteQueryResultTransformer struct {
+	userError string
+}
+
+func (t *sqliteQueryResultTransformer) TransformQueryError(logger log.Logger, err error) error {
+	var driverErr sqlite3.Error
+	if errors.As(err, &driverErr) {
+		switch driverErr.Code {
+		case sqlite3.ErrError, sqlite3.ErrAuth:
+			// syntax errors, unknown tables and columns and statements denied by the read-only authorizer
+			return err
+		}
+		logger.Error("Query error", "error", err)
+		return fmt.Errorf("query failed - %s", t.userError)
+	}
+
+	return err
+}
+
+func (t *sqliteQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
+	return nil
+}
+
+// GetConverters returns the dynamic converter, the type of a column in SQLite is the type of its values, which can
+// vary between rows and isn't known for expressions until they're evaluated.
+func (t *sqliteQueryResultTransformer) GetConverters() []sqlutil.Converter {
+	return []sqlutil.Converter{{Name: "dynamic", Dynamic: true}}
+}
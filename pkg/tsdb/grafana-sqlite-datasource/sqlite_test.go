@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestReadOnlyConnection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.db")
+
+	writable, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = writable.Exec(`CREATE TABLE metrics (time TEXT, host TEXT, value REAL);
+		INSERT INTO metrics VALUES ('2024-01-01 10:00:00', 'a', 1.5), ('2024-01-01 10:01:00', 'b', 2)`)
+	require.NoError(t, err)
+	require.NoError(t, writable.Close())
+
+	// the driver is registered with the service
+	_ = ProvideService(setting.NewCfg())
+
+	db, err := sql.Open(driverName, connectionString(path))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM metrics WHERE value > ?", 1).Scan(&count))
+	assert.Equal(t, 2, count)
+
+	var columns int
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM pragma_table_info('metrics')").Scan(&columns))
+	assert.Equal(t, 3, columns)
+
+	for _, statement := range []string{
+		"INSERT INTO metrics VALUES ('2024-01-01 10:02:00', 'c', 3)",
+		"DELETE FROM metrics",
+		"CREATE TABLE other (id INTEGER)",
+		"ATTACH DATABASE '" + filepath.Join(t.TempDir(), "other.db") + "' AS other",
+		"VACUUM INTO '" + filepath.Join(t.TempDir(), "copy.db") + "'",
+		"PRAGMA query_only = false",
+	} {
+		_, err := db.Exec(statement)
+		assert.Error(t, err, statement)
+	}
+}
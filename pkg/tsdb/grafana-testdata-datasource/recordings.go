@@ -0,0 +1,234 @@
+package testdatasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// RecordingStore persists the recordings used by the replay scenario.
+// Recordings are opaque JSON documents scoped to an organization.
+type RecordingStore interface {
+	ListRecordings(ctx context.Context, orgID int64) ([]string, error)
+	ReadRecording(ctx context.Context, orgID int64, name string) ([]byte, error)
+	WriteRecording(ctx context.Context, orgID int64, name string, body []byte, overwrite bool) error
+}
+
+// maxRecordingSize bounds the size of an uploaded recording.
+const maxRecordingSize = 10 << 20
+
+var (
+	errRecordingsNotAvailable = errors.New("recordings are not available in this environment")
+	errInvalidRecordingName   = errors.New("recording name may only contain letters, digits, '-' and '_'")
+	errRecordingNotFound      = errors.New("recording not found")
+
+	recordingNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,100}$`)
+)
+
+// ProvideServiceWithRecordings returns a TestData service that can record
+// query responses into the given store and replay them.
+func ProvideServiceWithRecordings(recordings RecordingStore) *Service {
+	s := ProvideService()
+	s.recordings = recordings
+	return s
+}
+
+// recording is the stored representation of a captured query response.
+// From and To is the time range the frames were originally queried for.
+type recording struct {
+	From   time.Time     `json:"from"`
+	To     time.Time     `json:"to"`
+	Frames []*data.Frame `json:"frames"`
+}
+
+func (s *Service) loadRecording(ctx context.Context, orgID int64, name string) (*recording, error) {
+	if s.recordings == nil {
+		return nil, errRecordingsNotAvailable
+	}
+	if !recordingNameRegex.MatchString(name) {
+		return nil, errInvalidRecordingName
+	}
+
+	body, err := s.recordings.ReadRecording(ctx, orgID, name)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, errRecordingNotFound
+	}
+
+	rec := &recording{}
+	if err := json.Unmarshal(body, rec); err != nil {
+		return nil, fmt.Errorf("failed to parse recording %q: %w", name, err)
+	}
+	return rec, nil
+}
+
+func (s *Service) handleReplayScenario(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	resp := backend.NewQueryDataResponse()
+
+	for _, q := range req.Queries {
+		model, err := GetJSONModel(q.JSON)
+		if err != nil {
+			return nil, err
+		}
+
+		if model.StringInput == "" {
+			continue
+		}
+
+		rec, err := s.loadRecording(ctx, req.PluginContext.OrgID, model.StringInput)
+		if err != nil {
+			resp.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+			continue
+		}
+
+		shiftFrames(rec.Frames, q.TimeRange.From.Sub(rec.From))
+
+		respD := resp.Responses[q.RefID]
+		respD.Frames = append(respD.Frames, rec.Frames...)
+		resp.Responses[q.RefID] = respD
+	}
+
+	return resp, nil
+}
+
+// shiftFrames moves every time value in the frames by offset, so a recording
+// made for one time range lines up with the range of the replaying query.
+func shiftFrames(frames data.Frames, offset time.Duration) {
+	if offset == 0 {
+		return
+	}
+
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			switch field.Type() {
+			case data.FieldTypeTime:
+				for i := 0; i < field.Len(); i++ {
+					field.Set(i, field.At(i).(time.Time).Add(offset))
+				}
+			case data.FieldTypeNullableTime:
+				for i := 0; i < field.Len(); i++ {
+					if v := field.At(i).(*time.Time); v != nil {
+						shifted := v.Add(offset)
+						field.Set(i, &shifted)
+					}
+				}
+			}
+		}
+	}
+}
+
+// recordingsHandler lists the stored recordings on GET /recordings and
+// stores a new recording on POST /recordings/<name>. Storing requires the
+// Admin role, the one allowed to change data sources by default, and an
+// existing recording is only replaced with ?overwrite=true.
+func (s *Service) recordingsHandler(rw http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ctxLogger := s.logger.FromContext(ctx)
+
+	if s.recordings == nil {
+		http.Error(rw, errRecordingsNotAvailable.Error(), http.StatusNotImplemented)
+		return
+	}
+
+	pluginCtx := httpadapter.PluginConfigFromContext(ctx)
+	orgID := pluginCtx.OrgID
+	name := strings.Trim(strings.TrimPrefix(req.URL.Path, "/recordings"), "/")
+
+	switch {
+	case req.Method == http.MethodGet && name == "":
+		names, err := s.recordings.ListRecordings(ctx, orgID)
+		if err != nil {
+			ctxLogger.Error("Failed to list recordings", "error", err)
+			http.Error(rw, "failed to list recordings", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(ctxLogger, rw, names)
+	case req.Method == http.MethodPost && name != "":
+		if user := pluginCtx.User; user == nil || user.Role != "Admin" {
+			http.Error(rw, "storing recordings requires the Admin role", http.StatusForbidden)
+			return
+		}
+		if !recordingNameRegex.MatchString(name) {
+			http.Error(rw, errInvalidRecordingName.Error(), http.StatusBadRequest)
+			return
+		}
+
+		overwrite := req.URL.Query().Get("overwrite") == "true"
+		if !overwrite {
+			existing, err := s.recordings.ReadRecording(ctx, orgID, name)
+			if err != nil {
+				ctxLogger.Error("Failed to read recording", "name", name, "error", err)
+				http.Error(rw, "failed to store recording", http.StatusInternalServerError)
+				return
+			}
+			if existing != nil {
+				http.Error(rw, "recording already exists, use overwrite=true to replace it", http.StatusConflict)
+				return
+			}
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, maxRecordingSize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				http.Error(rw, fmt.Sprintf("recording exceeds the maximum size of %d bytes", maxBytesErr.Limit), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(rw, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+
+		// Round-trip the body so only well formed frames end up in the store.
+		rec := &recording{}
+		if err := json.Unmarshal(body, rec); err != nil {
+			http.Error(rw, fmt.Sprintf("invalid recording: %s", err), http.StatusBadRequest)
+			return
+		}
+		if rec.From.IsZero() || rec.To.Before(rec.From) {
+			http.Error(rw, "invalid recording: a valid from/to time range is required", http.StatusBadRequest)
+			return
+		}
+		body, err = json.Marshal(rec)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("invalid recording: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		if err := s.recordings.WriteRecording(ctx, orgID, name, body, overwrite); err != nil {
+			ctxLogger.Error("Failed to store recording", "name", name, "error", err)
+			http.Error(rw, "failed to store recording", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(ctxLogger, rw, map[string]any{"name": name, "frames": len(rec.Frames)})
+	default:
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func writeJSON(logger log.Logger, rw http.ResponseWriter, v any) {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		logger.Error("Failed to marshal response body to JSON", "error", err)
+		http.Error(rw, "failed to marshal response", http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	if _, err := rw.Write(bytes); err != nil {
+		logger.Error("Failed to write response", "error", err)
+	}
+}
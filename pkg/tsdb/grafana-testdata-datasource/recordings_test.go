@@ -0,0 +1,197 @@
+package testdatasource
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/grafana-testdata-datasource/kinds"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	recordedFrom := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	frame := data.NewFrame("recorded",
+		data.NewField("time", nil, []time.Time{recordedFrom, recordedFrom.Add(time.Minute)}),
+		data.NewField("value", nil, []float64{1, 2}),
+	)
+	body, err := json.Marshal(recording{
+		From:   recordedFrom,
+		To:     recordedFrom.Add(time.Hour),
+		Frames: []*data.Frame{frame},
+	})
+	require.NoError(t, err)
+
+	t.Run("stores and lists recordings per org", func(t *testing.T) {
+		s := ProvideServiceWithRecordings(newFakeRecordingStore())
+
+		resp := callResource(t, s, 1, "POST", "recordings/cpu", body)
+		require.Equal(t, 200, resp.Status)
+
+		resp = callResource(t, s, 1, "GET", "recordings", nil)
+		require.Equal(t, 200, resp.Status)
+		require.JSONEq(t, `["cpu"]`, string(resp.Body))
+
+		resp = callResource(t, s, 2, "GET", "recordings", nil)
+		require.Equal(t, 200, resp.Status)
+		require.JSONEq(t, `[]`, string(resp.Body))
+	})
+
+	t.Run("requires the Admin role to store recordings", func(t *testing.T) {
+		s := ProvideServiceWithRecordings(newFakeRecordingStore())
+
+		resp := callResourceAs(t, s, &backend.User{Role: "Viewer"}, 1, "POST", "recordings/cpu", body)
+		require.Equal(t, 403, resp.Status)
+		resp = callResourceAs(t, s, &backend.User{Role: "Editor"}, 1, "POST", "recordings/cpu", body)
+		require.Equal(t, 403, resp.Status)
+		resp = callResourceAs(t, s, nil, 1, "POST", "recordings/cpu", body)
+		require.Equal(t, 403, resp.Status)
+
+		resp = callResource(t, s, 1, "GET", "recordings", nil)
+		require.JSONEq(t, `[]`, string(resp.Body))
+	})
+
+	t.Run("only overwrites recordings when asked to", func(t *testing.T) {
+		store := newFakeRecordingStore()
+		require.NoError(t, store.WriteRecording(context.Background(), 1, "cpu", []byte(`{}`), false))
+		s := ProvideServiceWithRecordings(store)
+
+		resp := callResource(t, s, 1, "POST", "recordings/cpu", body)
+		require.Equal(t, 409, resp.Status)
+		require.JSONEq(t, `{}`, string(store.recordings[1]["cpu"]))
+
+		resp = callResource(t, s, 1, "POST", "recordings/cpu?overwrite=true", body)
+		require.Equal(t, 200, resp.Status)
+		require.NotEqual(t, `{}`, string(store.recordings[1]["cpu"]))
+	})
+
+	t.Run("rejects recordings which are too large", func(t *testing.T) {
+		s := ProvideServiceWithRecordings(newFakeRecordingStore())
+
+		resp := callResource(t, s, 1, "POST", "recordings/cpu", make([]byte, maxRecordingSize+1))
+		require.Equal(t, 413, resp.Status)
+	})
+
+	t.Run("rejects invalid recordings", func(t *testing.T) {
+		s := ProvideServiceWithRecordings(newFakeRecordingStore())
+
+		resp := callResource(t, s, 1, "POST", "recordings/cpu.load", body)
+		require.Equal(t, 400, resp.Status)
+
+		resp = callResource(t, s, 1, "POST", "recordings/cpu", []byte(`{"frames":[]}`))
+		require.Equal(t, 400, resp.Status)
+	})
+
+	t.Run("replays a recording shifted to the query time range", func(t *testing.T) {
+		store := newFakeRecordingStore()
+		require.NoError(t, store.WriteRecording(context.Background(), 1, "cpu", body, false))
+		s := ProvideServiceWithRecordings(store)
+
+		queryFrom := time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC)
+		req := &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{OrgID: 1},
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				QueryType: string(kinds.TestDataQueryTypeReplay),
+				TimeRange: backend.TimeRange{From: queryFrom, To: queryFrom.Add(time.Hour)},
+				JSON:      []byte(`{"stringInput": "cpu"}`),
+			}},
+		}
+
+		resp, err := s.handleReplayScenario(context.Background(), req)
+		require.NoError(t, err)
+		dr := resp.Responses["A"]
+		require.NoError(t, dr.Error)
+		require.Len(t, dr.Frames, 1)
+		require.Equal(t, queryFrom, dr.Frames[0].Fields[0].At(0).(time.Time).UTC())
+		require.Equal(t, queryFrom.Add(time.Minute), dr.Frames[0].Fields[0].At(1).(time.Time).UTC())
+		require.Equal(t, 2.0, dr.Frames[0].Fields[1].At(1))
+	})
+
+	t.Run("returns an error for unknown recordings", func(t *testing.T) {
+		s := ProvideServiceWithRecordings(newFakeRecordingStore())
+
+		req := &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{OrgID: 1},
+			Queries: []backend.DataQuery{{
+				RefID: "A",
+				JSON:  []byte(`{"stringInput": "missing"}`),
+			}},
+		}
+
+		resp, err := s.handleReplayScenario(context.Background(), req)
+		require.NoError(t, err)
+		require.ErrorContains(t, resp.Responses["A"].Error, errRecordingNotFound.Error())
+	})
+
+	t.Run("returns an error when recordings are not available", func(t *testing.T) {
+		s := ProvideService()
+
+		resp := callResource(t, s, 1, "GET", "recordings", nil)
+		require.Equal(t, 501, resp.Status)
+	})
+}
+
+type fakeRecordingStore struct {
+	recordings map[int64]map[string][]byte
+}
+
+func newFakeRecordingStore() *fakeRecordingStore {
+	return &fakeRecordingStore{recordings: map[int64]map[string][]byte{}}
+}
+
+func (f *fakeRecordingStore) ListRecordings(_ context.Context, orgID int64) ([]string, error) {
+	names := make([]string, 0)
+	for name := range f.recordings[orgID] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (f *fakeRecordingStore) ReadRecording(_ context.Context, orgID int64, name string) ([]byte, error) {
+	return f.recordings[orgID][name], nil
+}
+
+func (f *fakeRecordingStore) WriteRecording(_ context.Context, orgID int64, name string, body []byte, _ bool) error {
+	if f.recordings[orgID] == nil {
+		f.recordings[orgID] = map[string][]byte{}
+	}
+	f.recordings[orgID][name] = body
+	return nil
+}
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (fs *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	fs.resp = resp
+	return nil
+}
+
+func callResource(t *testing.T, s *Service, orgID int64, method, path string, body []byte) *backend.CallResourceResponse {
+	t.Helper()
+	return callResourceAs(t, s, &backend.User{Role: "Admin"}, orgID, method, path, body)
+}
+
+func callResourceAs(t *testing.T, s *Service, user *backend.User, orgID int64, method, path string, body []byte) *backend.CallResourceResponse {
+	t.Helper()
+
+	sender := &fakeSender{}
+	err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+		PluginContext: backend.PluginContext{OrgID: orgID, User: user},
+		Path:          path,
+		Method:        method,
+		URL:           path,
+		Body:          body,
+	}, sender)
+	require.NoError(t, err)
+	require.NotNil(t, sender.resp)
+	return sender.resp
+}
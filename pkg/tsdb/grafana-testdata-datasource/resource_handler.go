@@ -24,6 +24,8 @@ func (s *Service) registerRoutes() *http.ServeMux {
 	mux.HandleFunc("/boom", s.testPanicHandler)
 	mux.HandleFunc("/sims", s.sims.GetSimulationHandler)
 	mux.HandleFunc("/sim/", s.sims.GetSimulationHandler)
+	mux.HandleFunc("/recordings", s.recordingsHandler)
+	mux.HandleFunc("/recordings/", s.recordingsHandler)
 	return mux
 }
 
@@ -198,6 +198,13 @@ Timestamps will line up evenly on timeStepSeconds (For example, 60 seconds means
 		Name: "Trace",
 	})
 
+	s.registerScenario(&Scenario{
+		ID:          kinds.TestDataQueryTypeReplay,
+		Name:        "Replay recording",
+		Description: "Replays a stored recording of a query response, shifted to the time range of the query",
+		handler:     s.handleReplayScenario,
+	})
+
 	s.queryMux.HandleFunc("", s.handleFallbackScenario)
 }
 
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"sync"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -57,9 +58,10 @@ func ProvideService(search searchV2.SearchService, store store.StorageService) *
 
 func newService(search searchV2.SearchService, store store.StorageService) *Service {
 	s := &Service{
-		search: search,
-		store:  store,
-		log:    log.New("grafanads"),
+		search:          search,
+		store:           store,
+		log:             log.New("grafanads"),
+		systemProviders: map[string]SystemDataProvider{},
 	}
 
 	return s
@@ -70,6 +72,9 @@ type Service struct {
 	search searchV2.SearchService
 	store  store.StorageService
 	log    log.Logger
+
+	systemMu        sync.RWMutex
+	systemProviders map[string]SystemDataProvider
 }
 
 func DataSourceModel(orgId int64) *datasources.DataSource {
@@ -98,6 +103,10 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		case queryTypeSearch:
 			response.Responses[q.RefID] = s.doSearchQuery(ctx, req, q)
 		default:
+			if provider, ok := s.systemDataProvider(q.QueryType); ok {
+				response.Responses[q.RefID] = s.doSystemDataQuery(ctx, provider, q)
+				continue
+			}
 			response.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("unknown query type"),
 			}
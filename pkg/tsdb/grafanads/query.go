@@ -16,6 +16,24 @@ const (
 	queryTypeRead = "read"
 )
 
+// Self-observability query types, served by the registered SystemDataProviders.
+const (
+	// QueryTypeAlertStates returns the current alert instances from the state cache
+	QueryTypeAlertStates = "alertStates"
+
+	// QueryTypeAlertStateHistory returns the state transitions of alert instances
+	QueryTypeAlertStateHistory = "alertStateHistory"
+
+	// QueryTypeRuleEvaluations returns the latest evaluation time and duration of alert rules
+	QueryTypeRuleEvaluations = "ruleEvaluations"
+
+	// QueryTypeDataSourceHealth returns the latest background health check result of data sources
+	QueryTypeDataSourceHealth = "dataSourceHealth"
+
+	// QueryTypeActiveUsers returns the number of active users and sessions
+	QueryTypeActiveUsers = "activeUsers"
+)
+
 type listQueryModel struct {
 	Path string `json:"path"`
 }
@@ -0,0 +1,80 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+)
+
+// ErrSystemDataAccessDenied is returned by a SystemDataProvider when the
+// requester is not allowed to see the data.
+var ErrSystemDataAccessDenied = errors.New("access denied")
+
+// SystemDataQuery is the model of the self-observability query types.
+type SystemDataQuery struct {
+	QueryType string            `json:"queryType"`
+	RuleUID   string            `json:"ruleUID,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Limit     int               `json:"limit,omitempty"`
+
+	TimeRange backend.TimeRange `json:"-"`
+}
+
+// SystemDataProvider returns frames describing Grafana's own state for one of
+// the self-observability query types. Providers live next to the data they
+// expose, and are responsible for applying access control for the requester.
+type SystemDataProvider interface {
+	QuerySystemData(ctx context.Context, requester identity.Requester, query SystemDataQuery) (data.Frames, error)
+}
+
+// SystemDataProviderFunc is an adapter to use a function as a SystemDataProvider.
+type SystemDataProviderFunc func(ctx context.Context, requester identity.Requester, query SystemDataQuery) (data.Frames, error)
+
+func (f SystemDataProviderFunc) QuerySystemData(ctx context.Context, requester identity.Requester, query SystemDataQuery) (data.Frames, error) {
+	return f(ctx, requester, query)
+}
+
+// RegisterSystemDataProvider makes the query type available in the Grafana
+// data source. Registering the same query type twice replaces the provider.
+func (s *Service) RegisterSystemDataProvider(queryType string, provider SystemDataProvider) {
+	s.systemMu.Lock()
+	defer s.systemMu.Unlock()
+	s.systemProviders[queryType] = provider
+}
+
+func (s *Service) systemDataProvider(queryType string) (SystemDataProvider, bool) {
+	s.systemMu.RLock()
+	defer s.systemMu.RUnlock()
+	p, ok := s.systemProviders[queryType]
+	return p, ok
+}
+
+func (s *Service) doSystemDataQuery(ctx context.Context, provider SystemDataProvider, query backend.DataQuery) backend.DataResponse {
+	q := SystemDataQuery{}
+	if err := json.Unmarshal(query.JSON, &q); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse query: %s", err))
+	}
+	q.TimeRange = query.TimeRange
+
+	requester, err := appcontext.User(ctx)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusUnauthorized, "self-observability queries require a signed in user")
+	}
+
+	frames, err := provider.QuerySystemData(ctx, requester, q)
+	if err != nil {
+		if errors.Is(err, ErrSystemDataAccessDenied) {
+			return backend.ErrDataResponse(backend.StatusForbidden, err.Error())
+		}
+		s.log.FromContext(ctx).Error("Failed to query system data", "queryType", q.QueryType, "error", err)
+		return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+	}
+	return backend.DataResponse{Frames: frames}
+}
@@ -0,0 +1,73 @@
+package graphite
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+const maxErrorMessageLength = 500
+
+var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// responseError is returned when Graphite answers with a non 2xx status code
+type responseError struct {
+	status     string
+	statusCode int
+	message    string
+}
+
+func (e *responseError) Error() string {
+	if e.message == "" {
+		return fmt.Sprintf("request failed, status: %s", e.status)
+	}
+	return fmt.Sprintf("request failed, status: %s, message: %s", e.status, e.message)
+}
+
+func newResponseError(res *http.Response, body []byte) *responseError {
+	return &responseError{
+		status:     res.Status,
+		statusCode: res.StatusCode,
+		message:    errorMessage(body),
+	}
+}
+
+// errorMessage extracts a readable message from a Graphite error body. Graphite-web
+// returns HTML pages with a Python traceback, the exception is on the last line.
+func errorMessage(body []byte) string {
+	text := htmlTagRegex.ReplaceAllString(string(body), "")
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if len(line) > maxErrorMessageLength {
+			line = line[:maxErrorMessageLength]
+		}
+		return line
+	}
+	return ""
+}
+
+// errorResponse returns a data response with the status of the Graphite response. Other
+// errors, such as an unreachable Graphite or an unexpected response, are reported as bad gateway.
+func errorResponse(err error) backend.DataResponse {
+	var respErr *responseError
+	if errors.As(err, &respErr) {
+		return backend.DataResponse{
+			Error:       err,
+			Status:      backend.Status(respErr.statusCode),
+			ErrorSource: backend.ErrorSourceDownstream,
+		}
+	}
+	return backend.DataResponse{
+		Error:       err,
+		Status:      backend.Status(http.StatusBadGateway),
+		ErrorSource: backend.ErrorSourceDownstream,
+	}
+}
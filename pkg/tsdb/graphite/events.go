@@ -0,0 +1,106 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// isEventsQuery returns true for annotation queries filtering Graphite events by tags,
+// annotation queries with a target are render queries.
+func isEventsQuery(query backend.DataQuery) bool {
+	model, err := simplejson.NewJson(query.JSON)
+	if err != nil {
+		return false
+	}
+	return model.Get(FromAnnotationsModelField).MustBool() &&
+		model.Get(TargetModelField).MustString() == "" &&
+		model.Get(TargetFullModelField).MustString() == ""
+}
+
+func (s *Service) queryEvents(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery) backend.DataResponse {
+	model, err := simplejson.NewJson(query.JSON)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+	}
+
+	ctx, span := s.tracer.Start(ctx, "graphite events query")
+	defer span.End()
+
+	from, until := epochMStoGraphiteTime(query.TimeRange)
+	params := url.Values{
+		"from":  []string{from},
+		"until": []string{until},
+	}
+	if tags := model.Get(TagsModelField).MustStringArray(); len(tags) > 0 {
+		params.Set("tags", strings.Join(tags, " "))
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+	}
+	u.Path = path.Join(u.Path, "events/get_data")
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusInternal, fmt.Sprintf("failed to create request: %s", err))
+	}
+	s.tracer.Inject(ctx, req.Header, span)
+
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return errorResponse(err)
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if res.StatusCode/100 != 2 {
+		logger.Info("Events request failed", "status", res.Status, "body", string(body))
+		return errorResponse(newResponseError(res, body))
+	}
+
+	var events []EventDTO
+	if err := json.Unmarshal(body, &events); err != nil {
+		logger.Info("Failed to unmarshal graphite events response", "error", err, "status", res.Status, "body", string(body))
+		return errorResponse(err)
+	}
+
+	return backend.DataResponse{Frames: data.Frames{eventsToFrame(query.RefID, events)}}
+}
+
+// eventsToFrame converts Graphite events to an annotations frame
+func eventsToFrame(refID string, events []EventDTO) *data.Frame {
+	frame := data.NewFrame(refID,
+		data.NewField("time", nil, make([]time.Time, 0, len(events))),
+		data.NewField("title", nil, make([]string, 0, len(events))),
+		data.NewField("tags", nil, make([]string, 0, len(events))),
+		data.NewField("text", nil, make([]string, 0, len(events))),
+	)
+	for _, e := range events {
+		sec := int64(e.When)
+		nsec := int64((e.When - float64(sec)) * float64(time.Second))
+		frame.AppendRow(time.Unix(sec, nsec).UTC(), e.What, strings.Join(e.tags(), ","), e.Data)
+	}
+	return frame
+}
@@ -0,0 +1,112 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestQueryDataEventsAndErrors(t *testing.T) {
+	var renderForm url.Values
+	var eventsQuery url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/render":
+			require.NoError(t, r.ParseForm())
+			renderForm = r.PostForm
+			if r.PostForm.Get("from") == "0" {
+				rw.WriteHeader(http.StatusBadRequest)
+				_, _ = rw.Write([]byte("<html><body><pre>Traceback\nValueError: invalid function</pre></body></html>"))
+				return
+			}
+			_, _ = rw.Write([]byte(`[{"target": "a.b A", "datapoints": [[1, 1], [2, 2]]}]`))
+		case "/events/get_data":
+			eventsQuery = r.URL.Query()
+			_, _ = rw.Write([]byte(`[
+				{"when": 1.5, "what": "deploy", "tags": ["app", "prod"], "data": "v1.2"},
+				{"when": 3, "what": "restart", "tags": "app,dev", "data": ""}
+			]`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	service := &Service{
+		im:     testInstanceManager{info: datasourceInfo{URL: ts.URL, HTTPClient: ts.Client()}},
+		tracer: tracing.InitializeTracerForTest(),
+	}
+	timeRange := backend.TimeRange{From: time.Unix(100, 0), To: time.Unix(200, 0)}
+
+	t.Run("runs events and render queries", func(t *testing.T) {
+		res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{RefID: "A", TimeRange: timeRange, MaxDataPoints: 1200, JSON: []byte(`{"target": "a.b", "consolidateBy": "max"}`)},
+				{RefID: "B", TimeRange: timeRange, JSON: []byte(`{"fromAnnotations": true, "tags": ["app", "prod"]}`)},
+			},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "1200", renderForm.Get("maxDataPoints"))
+		require.Equal(t, []string{`aliasSub(consolidateBy(a.b,'max'),"(^.*$)","\1 A")`}, renderForm["target"])
+		require.NoError(t, res.Responses["A"].Error)
+		require.Len(t, res.Responses["A"].Frames, 1)
+
+		require.Equal(t, "app prod", eventsQuery.Get("tags"))
+		require.Equal(t, "100", eventsQuery.Get("from"))
+		events := res.Responses["B"]
+		require.NoError(t, events.Error)
+		frame := events.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, time.Unix(1, 500000000).UTC(), frame.Fields[0].At(0))
+		require.Equal(t, "deploy", frame.Fields[1].At(0))
+		require.Equal(t, "app,prod", frame.Fields[2].At(0))
+		require.Equal(t, "app,dev", frame.Fields[2].At(1))
+		require.Equal(t, "v1.2", frame.Fields[3].At(0))
+	})
+
+	t.Run("returns Graphite errors as query responses", func(t *testing.T) {
+		res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{RefID: "A", TimeRange: backend.TimeRange{From: time.Unix(0, 0), To: time.Unix(200, 0)}, JSON: []byte(`{"target": "a.b"}`)},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "500", renderForm.Get("maxDataPoints"))
+
+		dr := res.Responses["A"]
+		require.EqualError(t, dr.Error, "request failed, status: 400 Bad Request, message: ValueError: invalid function")
+		require.Equal(t, backend.StatusBadRequest, dr.Status)
+		require.Equal(t, backend.ErrorSourceDownstream, dr.ErrorSource)
+	})
+
+	t.Run("rejects unknown consolidation functions", func(t *testing.T) {
+		_, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{RefID: "A", TimeRange: timeRange, JSON: []byte(`{"target": "a.b", "consolidateBy": "mean"}`)},
+			},
+		})
+		require.Error(t, err)
+	})
+}
+
+type testInstanceManager struct {
+	info datasourceInfo
+}
+
+func (m testInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return m.info, nil
+}
+
+func (m testInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
@@ -37,10 +37,30 @@ type Service struct {
 }
 
 const (
-	TargetFullModelField = "targetFull"
-	TargetModelField     = "target"
+	TargetFullModelField      = "targetFull"
+	TargetModelField          = "target"
+	ConsolidateByModelField   = "consolidateBy"
+	FromAnnotationsModelField = "fromAnnotations"
+	TagsModelField            = "tags"
+
+	defaultMaxDataPoints = 500
 )
 
+var errNoQueryTarget = errors.New("no query target found for the alert rule")
+
+// consolidationFunctions are the functions Graphite accepts in consolidateBy
+var consolidationFunctions = map[string]bool{
+	"average":  true,
+	"avg":      true,
+	"avg_zero": true,
+	"median":   true,
+	"sum":      true,
+	"min":      true,
+	"max":      true,
+	"first":    true,
+	"last":     true,
+}
+
 func ProvideService(httpClientProvider httpclient.Provider, tracer tracing.Tracer) *Service {
 	return &Service{
 		im:     datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
@@ -98,8 +118,39 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		return nil, err
 	}
 
+	// Events queries are sent one by one to the events API, all other queries are
+	// grouped in a single render request.
+	renderQueries := make([]backend.DataQuery, 0, len(req.Queries))
+	eventsQueries := make([]backend.DataQuery, 0)
+	for _, q := range req.Queries {
+		if isEventsQuery(q) {
+			eventsQueries = append(eventsQueries, q)
+		} else {
+			renderQueries = append(renderQueries, q)
+		}
+	}
+
+	result := &backend.QueryDataResponse{Responses: make(backend.Responses)}
+	if len(renderQueries) > 0 {
+		result, err = s.queryRender(ctx, logger, dsInfo, req.PluginContext, renderQueries)
+		if err != nil && !(errors.Is(err, errNoQueryTarget) && len(eventsQueries) > 0) {
+			return result, err
+		}
+		if result.Responses == nil {
+			result.Responses = make(backend.Responses)
+		}
+	}
+
+	for _, q := range eventsQueries {
+		result.Responses[q.RefID] = s.queryEvents(ctx, logger, dsInfo, q)
+	}
+
+	return result, nil
+}
+
+func (s *Service) queryRender(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, pluginCtx backend.PluginContext, queries []backend.DataQuery) (*backend.QueryDataResponse, error) {
 	// take the first query in the request list, since all query should share the same timerange
-	q := req.Queries[0]
+	q := queries[0]
 
 	/*
 		graphite doc about from and until, with sdk we are getting absolute instead of relative time
@@ -110,12 +161,12 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		"from":          []string{from},
 		"until":         []string{until},
 		"format":        []string{"json"},
-		"maxDataPoints": []string{"500"},
+		"maxDataPoints": []string{strconv.FormatInt(maxDataPoints(queries), 10)},
 		"target":        []string{},
 	}
 
 	// Convert datasource query to graphite target request
-	targetList, emptyQueries, origRefIds, err := s.processQueries(logger, req.Queries)
+	targetList, emptyQueries, origRefIds, err := s.processQueries(logger, queries)
 	if err != nil {
 		return nil, err
 	}
@@ -124,8 +175,8 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	if len(emptyQueries) != 0 {
 		logger.Warn("Found query models without targets", "models without targets", strings.Join(emptyQueries, "\n"))
 		// If no queries had a valid target, return an error; otherwise, attempt with the targets we have
-		if len(emptyQueries) == len(req.Queries) {
-			return &result, errors.New("no query target found for the alert rule")
+		if len(emptyQueries) == len(queries) {
+			return &result, errNoQueryTarget
 		}
 	}
 	formData["target"] = targetList
@@ -148,10 +199,14 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		attribute.String("from", from),
 		attribute.String("until", until),
 		attribute.Int64("datasource_id", dsInfo.Id),
-		attribute.Int64("org_id", req.PluginContext.OrgID),
+		attribute.Int64("org_id", pluginCtx.OrgID),
 	)
 	s.tracer.Inject(ctx, graphiteReq.Header, span)
 
+	result = backend.QueryDataResponse{
+		Responses: make(backend.Responses),
+	}
+
 	res, err := dsInfo.HTTPClient.Do(graphiteReq)
 	if res != nil {
 		span.SetAttributes(attribute.Int("graphite.response.code", res.StatusCode))
@@ -159,7 +214,10 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return &result, err
+		for _, refID := range origRefIds {
+			result.Responses[refID] = errorResponse(err)
+		}
+		return &result, nil
 	}
 
 	defer func() {
@@ -173,11 +231,10 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return &result, err
-	}
-
-	result = backend.QueryDataResponse{
-		Responses: make(backend.Responses),
+		for _, refID := range origRefIds {
+			result.Responses[refID] = errorResponse(err)
+		}
+		return &result, nil
 	}
 
 	for _, f := range frames {
@@ -194,6 +251,21 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	return &result, nil
 }
 
+// maxDataPoints returns the largest maxDataPoints of the queries, Graphite consolidates
+// the points of every target of the render request to this number.
+func maxDataPoints(queries []backend.DataQuery) int64 {
+	var res int64
+	for _, q := range queries {
+		if q.MaxDataPoints > res {
+			res = q.MaxDataPoints
+		}
+	}
+	if res == 0 {
+		return defaultMaxDataPoints
+	}
+	return res
+}
+
 // processQueries converts each datasource query to a graphite query target. It returns the list of
 // targets, a list of invalid queries, and a mapping of formatted refIds (used in the target query)
 // to original query refIds, later used to associate ressponses with the original queries
@@ -221,6 +293,15 @@ func (s *Service) processQueries(logger log.Logger, queries []backend.DataQuery)
 		}
 		target := fixIntervalFormat(currTarget)
 
+		// The consolidation function applies when Graphite returns more points than maxDataPoints,
+		// an explicit consolidateBy in the target takes precedence.
+		if consolidateBy := model.Get(ConsolidateByModelField).MustString(); consolidateBy != "" && !strings.Contains(target, "consolidateBy(") {
+			if !consolidationFunctions[consolidateBy] {
+				return nil, nil, nil, fmt.Errorf("invalid consolidation function %q in query %s", consolidateBy, query.RefID)
+			}
+			target = fmt.Sprintf("consolidateBy(%s,'%s')", target, consolidateBy)
+		}
+
 		// This is a somewhat inglorious way to ensure we can associate results with the right query
 		// By using aliasSub, we can get back a resolved series Target name (accounting for other aliases)
 		// And the original refId. Since there are no restrictions on refId, we need to format it to make it
@@ -249,7 +330,7 @@ func (s *Service) parseResponse(logger log.Logger, res *http.Response) ([]Target
 
 	if res.StatusCode/100 != 2 {
 		logger.Info("Request failed", "status", res.Status, "body", string(body))
-		return nil, fmt.Errorf("request failed, status: %s", res.Status)
+		return nil, newResponseError(res, body)
 	}
 
 	var data []TargetResponseDTO
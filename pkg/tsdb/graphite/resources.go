@@ -0,0 +1,78 @@
+package graphite
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// tagResources are the Graphite tag API endpoints used to discover the series
+// matching a seriesByTag expression
+var tagResources = map[string]bool{
+	"tags":                     true,
+	"tags/autoComplete/tags":   true,
+	"tags/autoComplete/values": true,
+	"tags/findSeries":          true,
+}
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	resource := strings.Trim(req.Path, "/")
+	if !tagResources[resource] {
+		return sender.Send(&backend.CallResourceResponse{Status: http.StatusNotFound})
+	}
+	if req.Method != http.MethodGet {
+		return sender.Send(&backend.CallResourceResponse{Status: http.StatusMethodNotAllowed})
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return err
+	}
+
+	reqURL, err := url.Parse(req.URL)
+	if err != nil {
+		return sender.Send(&backend.CallResourceResponse{Status: http.StatusBadRequest})
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, resource)
+	u.RawQuery = reqURL.RawQuery
+
+	graphiteReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := dsInfo.HTTPClient.Do(graphiteReq)
+	if err != nil {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: http.StatusBadGateway,
+			Body:   []byte(err.Error()),
+		})
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  res.StatusCode,
+		Headers: map[string][]string{"Content-Type": {res.Header.Get("Content-Type")}},
+		Body:    body,
+	})
+}
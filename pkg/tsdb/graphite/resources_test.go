@@ -0,0 +1,55 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallResource(t *testing.T) {
+	var requestURI string
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requestURI = r.URL.RequestURI()
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`["app.cpu;host=a"]`))
+	}))
+	t.Cleanup(ts.Close)
+
+	service := &Service{im: testInstanceManager{info: datasourceInfo{URL: ts.URL, HTTPClient: ts.Client()}}}
+
+	call := func(method, path string) *backend.CallResourceResponse {
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			Method: method,
+			Path:   path,
+			URL:    path + "?expr=name%3Dapp.cpu",
+		}, sender)
+		require.NoError(t, err)
+		return sender.res
+	}
+
+	t.Run("forwards tag discovery requests", func(t *testing.T) {
+		res := call(http.MethodGet, "tags/findSeries")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.JSONEq(t, `["app.cpu;host=a"]`, string(res.Body))
+		require.Equal(t, "/tags/findSeries?expr=name%3Dapp.cpu", requestURI)
+	})
+
+	t.Run("rejects other resources and methods", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, call(http.MethodGet, "render").Status)
+		require.Equal(t, http.StatusMethodNotAllowed, call(http.MethodPost, "tags/findSeries").Status)
+	})
+}
+
+type fakeSender struct {
+	res *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(res *backend.CallResourceResponse) error {
+	s.res = res
+	return nil
+}
@@ -1,6 +1,10 @@
 package graphite
 
-import "github.com/grafana/grafana/pkg/tsdb/legacydata"
+import (
+	"strings"
+
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+)
 
 type TargetResponseDTO struct {
 	Target     string                          `json:"target"`
@@ -8,3 +12,27 @@ type TargetResponseDTO struct {
 	// Graphite <=1.1.7 may return some tags as numbers requiring extra conversion. See https://github.com/grafana/grafana/issues/37614
 	Tags map[string]any `json:"tags"`
 }
+
+type EventDTO struct {
+	When float64 `json:"when"`
+	What string  `json:"what"`
+	Data string  `json:"data"`
+	// Depending on the Graphite version tags are a list or a string separated by commas or spaces
+	Tags any `json:"tags"`
+}
+
+func (e EventDTO) tags() []string {
+	switch tags := e.Tags.(type) {
+	case string:
+		return strings.FieldsFunc(tags, func(r rune) bool { return r == ',' || r == ' ' })
+	case []any:
+		res := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+	return nil
+}
@@ -85,6 +85,7 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			InsecureGrpc:  jsonData.InsecureGrpc,
 			Token:         settings.DecryptedSecureJSONData["token"],
 			Timeout:       opts.Timeouts.Timeout,
+			LinkMappings:  jsonData.LinkMappings,
 		}
 		return model, nil
 	}
@@ -103,16 +104,23 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 
 	logger.Debug(fmt.Sprintf("Making a %s type query", dsInfo.Version))
 
+	var res *backend.QueryDataResponse
 	switch dsInfo.Version {
 	case influxVersionFlux:
-		return flux.Query(ctx, dsInfo, *req)
+		res, err = flux.Query(ctx, dsInfo, *req)
 	case influxVersionInfluxQL:
-		return influxql.Query(ctx, tracer, dsInfo, req, s.features)
+		res, err = influxql.Query(ctx, tracer, dsInfo, req, s.features)
 	case influxVersionSQL:
-		return fsql.Query(ctx, dsInfo, *req)
+		res, err = fsql.Query(ctx, dsInfo, *req)
 	default:
 		return nil, fmt.Errorf("unknown influxdb version")
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	applyLinkMappings(logger, res, dsInfo.LinkMappings)
+	return res, nil
 }
 
 func (s *Service) getDSInfo(ctx context.Context, pluginCtx backend.PluginContext) (*models.DatasourceInfo, error) {
@@ -0,0 +1,97 @@
+package influxdb
+
+import (
+	"regexp"
+	"slices"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
+)
+
+// applyLinkMappings adds the data links configured in the data source settings to the
+// fields of every response frame. It is applied to InfluxQL, Flux and SQL responses alike.
+// A field matches a mapping by its name, its display name or its "_field" label.
+func applyLinkMappings(logger log.Logger, res *backend.QueryDataResponse, mappings []models.LinkMapping) {
+	if res == nil || len(mappings) == 0 {
+		return
+	}
+
+	matchers := make([]func(name string) bool, len(mappings))
+	for i, mapping := range mappings {
+		matchers[i] = fieldMatcher(logger, mapping.Field)
+	}
+
+	for _, dr := range res.Responses {
+		for _, frame := range dr.Frames {
+			for _, field := range frame.Fields {
+				names := fieldNames(field)
+				for i, mapping := range mappings {
+					matched := slices.IndexFunc(names, matchers[i])
+					if matched < 0 {
+						continue
+					}
+					if field.Config == nil {
+						field.Config = &data.FieldConfig{}
+					}
+					field.Config.Links = append(field.Config.Links, dataLink(mapping, names[matched]))
+				}
+			}
+		}
+	}
+}
+
+// fieldNames returns the names a link mapping is matched against. Responses don't
+// always keep the column in the field name: InfluxQL names value fields "Value" and
+// keeps the column in the display name, Flux names them "_value" and keeps the column
+// in the "_field" label. The other labels are tags and never name the column.
+func fieldNames(field *data.Field) []string {
+	names := []string{field.Name}
+	if field.Config != nil && field.Config.DisplayNameFromDS != "" {
+		names = append(names, field.Config.DisplayNameFromDS)
+	}
+	if name := field.Labels["_field"]; name != "" {
+		names = append(names, name)
+	}
+	return names
+}
+
+// fieldMatcher matches field names equal to the configured field, or matching it
+// when it is a regular expression.
+func fieldMatcher(logger log.Logger, field string) func(name string) bool {
+	re, err := regexp.Compile("^(?:" + field + ")$")
+	if err != nil {
+		logger.Warn("Invalid link mapping field pattern, matching the exact field name", "field", field, "error", err)
+	}
+	return func(name string) bool {
+		if field == "" {
+			return false
+		}
+		return name == field || (re != nil && re.MatchString(name))
+	}
+}
+
+// dataLink builds the link of a mapping. Internal links without a display label are
+// titled with the name the field matched with, the configured field can be a pattern.
+func dataLink(mapping models.LinkMapping, matchedName string) data.DataLink {
+	if mapping.DatasourceUID == "" {
+		return data.DataLink{
+			Title: mapping.URLDisplayLabel,
+			URL:   mapping.URL,
+		}
+	}
+
+	title := mapping.URLDisplayLabel
+	if title == "" {
+		title = matchedName
+	}
+	return data.DataLink{
+		Title: title,
+		Internal: &data.InternalDataLink{
+			DatasourceUID: mapping.DatasourceUID,
+			Query:         map[string]any{"query": mapping.URL},
+		},
+	}
+}
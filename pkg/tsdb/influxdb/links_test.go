@@ -0,0 +1,131 @@
+package influxdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
+)
+
+func TestApplyLinkMappings(t *testing.T) {
+	newResponse := func() *backend.QueryDataResponse {
+		return &backend.QueryDataResponse{
+			Responses: backend.Responses{
+				"A": backend.DataResponse{
+					Frames: data.Frames{
+						data.NewFrame("",
+							data.NewField("trace_id", nil, []string{"abc"}),
+							data.NewField("span_id", nil, []string{"def"}),
+							data.NewField("duration", nil, []float64{1.5}),
+						),
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("adds an internal link to the matching field", func(t *testing.T) {
+		res := newResponse()
+		applyLinkMappings(logger, res, []models.LinkMapping{
+			{Field: "trace_id", URL: "${__value.raw}", DatasourceUID: "tempo"},
+		})
+
+		fields := res.Responses["A"].Frames[0].Fields
+		require.Len(t, fields[0].Config.Links, 1)
+		link := fields[0].Config.Links[0]
+		require.Equal(t, "trace_id", link.Title)
+		require.Equal(t, "tempo", link.Internal.DatasourceUID)
+		require.Equal(t, map[string]any{"query": "${__value.raw}"}, link.Internal.Query)
+		require.Nil(t, fields[1].Config)
+		require.Nil(t, fields[2].Config)
+	})
+
+	t.Run("adds an external link to the fields matching a regex", func(t *testing.T) {
+		res := newResponse()
+		applyLinkMappings(logger, res, []models.LinkMapping{
+			{Field: ".*_id", URL: "http://example.com/${__value.raw}", URLDisplayLabel: "Open"},
+		})
+
+		fields := res.Responses["A"].Frames[0].Fields
+		for _, field := range fields[:2] {
+			require.Equal(t, []data.DataLink{{Title: "Open", URL: "http://example.com/${__value.raw}"}}, field.Config.Links)
+		}
+		require.Nil(t, fields[2].Config)
+	})
+
+	t.Run("titles internal links with the name of the field matching the regex", func(t *testing.T) {
+		res := newResponse()
+		applyLinkMappings(logger, res, []models.LinkMapping{
+			{Field: ".*_id", URL: "${__value.raw}", DatasourceUID: "tempo"},
+		})
+
+		fields := res.Responses["A"].Frames[0].Fields
+		require.Equal(t, "trace_id", fields[0].Config.Links[0].Title)
+		require.Equal(t, "span_id", fields[1].Config.Links[0].Title)
+	})
+
+	t.Run("matches InfluxQL value fields by their display name", func(t *testing.T) {
+		valueField := data.NewField("Value", data.Labels{"host": "a"}, []*string{nil})
+		valueField.SetConfig(&data.FieldConfig{DisplayNameFromDS: "spans.trace_id {host: a}"})
+		res := &backend.QueryDataResponse{Responses: backend.Responses{"A": backend.DataResponse{
+			Frames: data.Frames{data.NewFrame("spans.trace_id {host: a}",
+				data.NewField("Time", nil, []time.Time{time.Unix(0, 0)}),
+				valueField,
+			)},
+		}}}
+		applyLinkMappings(logger, res, []models.LinkMapping{
+			{Field: `spans\.trace_id.*`, URL: "${__value.raw}", DatasourceUID: "tempo"},
+		})
+
+		fields := res.Responses["A"].Frames[0].Fields
+		require.Nil(t, fields[0].Config)
+		require.Len(t, fields[1].Config.Links, 1)
+		require.Equal(t, "spans.trace_id {host: a}", fields[1].Config.DisplayNameFromDS)
+	})
+
+	t.Run("matches Flux value fields by their labels", func(t *testing.T) {
+		res := &backend.QueryDataResponse{Responses: backend.Responses{"A": backend.DataResponse{
+			Frames: data.Frames{data.NewFrame("",
+				data.NewField("_time", nil, []time.Time{time.Unix(0, 0)}),
+				data.NewField("_value", data.Labels{"_field": "trace_id", "_measurement": "spans"}, []string{"abc"}),
+			)},
+		}}}
+		applyLinkMappings(logger, res, []models.LinkMapping{
+			{Field: "trace_id", URL: "${__value.raw}", DatasourceUID: "tempo"},
+		})
+
+		fields := res.Responses["A"].Frames[0].Fields
+		require.Nil(t, fields[0].Config)
+		require.Len(t, fields[1].Config.Links, 1)
+		require.Equal(t, "trace_id", fields[1].Config.Links[0].Title)
+	})
+
+	t.Run("doesn't match Flux value fields by their tags", func(t *testing.T) {
+		res := &backend.QueryDataResponse{Responses: backend.Responses{"A": backend.DataResponse{
+			Frames: data.Frames{data.NewFrame("",
+				data.NewField("_value", data.Labels{"_field": "duration", "trace_id": "abc"}, []float64{1.5}),
+			)},
+		}}}
+		applyLinkMappings(logger, res, []models.LinkMapping{
+			{Field: "abc", URL: "${__value.raw}", DatasourceUID: "tempo"},
+		})
+
+		require.Nil(t, res.Responses["A"].Frames[0].Fields[0].Config)
+	})
+
+	t.Run("matches the exact field name when the pattern is invalid", func(t *testing.T) {
+		res := newResponse()
+		res.Responses["A"].Frames[0].Fields[2].Name = "duration("
+		applyLinkMappings(logger, res, []models.LinkMapping{
+			{Field: "duration(", URL: "http://example.com"},
+		})
+
+		fields := res.Responses["A"].Frames[0].Fields
+		require.Nil(t, fields[0].Config)
+		require.Len(t, fields[2].Config.Links, 1)
+	})
+}
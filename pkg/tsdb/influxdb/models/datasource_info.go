@@ -22,4 +22,16 @@ type DatasourceInfo struct {
 
 	// FlightSQL grpc connection
 	InsecureGrpc bool `json:"insecureGrpc"`
+
+	LinkMappings []LinkMapping `json:"linkMappings"`
+}
+
+// LinkMapping adds a data link to the result fields matching Field, which can be
+// a field name or a regular expression. The link opens URL, or runs URL as a query
+// against the data source DatasourceUID when it is set.
+type LinkMapping struct {
+	Field           string `json:"field"`
+	URL             string `json:"url"`
+	URLDisplayLabel string `json:"urlDisplayLabel,omitempty"`
+	DatasourceUID   string `json:"datasourceUid,omitempty"`
 }
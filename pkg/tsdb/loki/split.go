@@ -0,0 +1,207 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/concurrency"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const defaultSplitConcurrency = 4
+
+// querySplitting configures the splitting of long metric range queries in time chunks, so that they don't time out or
+// hit the query limits of Loki. Splitting is disabled when duration is 0.
+type querySplitting struct {
+	duration    time.Duration
+	concurrency int
+}
+
+type splittingJSONData struct {
+	QuerySplitDuration    string `json:"querySplitDuration"`
+	QuerySplitConcurrency int    `json:"querySplitConcurrency"`
+}
+
+func parseQuerySplitting(jsonData json.RawMessage) (querySplitting, error) {
+	splitting := querySplitting{concurrency: defaultSplitConcurrency}
+	if len(jsonData) == 0 {
+		return splitting, nil
+	}
+
+	var settings splittingJSONData
+	if err := json.Unmarshal(jsonData, &settings); err != nil {
+		return splitting, fmt.Errorf("error reading settings: %w", err)
+	}
+	if settings.QuerySplitDuration != "" {
+		duration, err := gtime.ParseDuration(settings.QuerySplitDuration)
+		if err != nil {
+			return splitting, fmt.Errorf("invalid querySplitDuration: %w", err)
+		}
+		splitting.duration = duration
+	}
+	if settings.QuerySplitConcurrency > 0 {
+		splitting.concurrency = settings.QuerySplitConcurrency
+	}
+	return splitting, nil
+}
+
+type timeRange struct {
+	start time.Time
+	end   time.Time
+}
+
+// splitTimeRange splits the time range of a metric query in chunks of at most duration, aligned to step. The end of a
+// range query is inclusive in Loki, a chunk ends a step before the next one starts. This is the same splitting as the
+// one done by the Loki frontend and by the browser.
+func splitTimeRange(start, end time.Time, step, duration time.Duration) []timeRange {
+	if step < time.Millisecond || duration < step {
+		// chunks can't be smaller than step
+		return []timeRange{{start: start, end: end}}
+	}
+
+	// the duration is made a multiple of step, lowering it if necessary
+	alignedDuration := duration / step * step
+	alignedStart := time.UnixMilli(start.UnixMilli() - start.UnixMilli()%step.Milliseconds())
+
+	var ranges []timeRange
+	for chunkStart := alignedStart; ; {
+		next := chunkStart.Add(alignedDuration)
+		if !next.Before(end) {
+			// the last chunk always ends with the range, even when the end is on a chunk boundary
+			return append(ranges, timeRange{start: chunkStart, end: end})
+		}
+		ranges = append(ranges, timeRange{start: chunkStart, end: next.Add(-step)})
+		chunkStart = next
+	}
+}
+
+// isLogsQuery returns whether the expression selects log lines rather than computing a metric. A log query always
+// starts with a stream selector, a metric query with a function, an aggregation or a parenthesis.
+func isLogsQuery(expr string) bool {
+	return strings.HasPrefix(strings.TrimSpace(expr), "{")
+}
+
+// shouldSplit returns whether a query is split in time chunks: only metric range queries are, merging chunks of log
+// lines would need the line limit to be enforced across chunks.
+func shouldSplit(query *lokiQuery, splitting querySplitting) bool {
+	return splitting.duration > 0 &&
+		query.QueryType == QueryTypeRange &&
+		query.End.Sub(query.Start) > splitting.duration &&
+		!isLogsQuery(query.Expr)
+}
+
+// runSplitQuery runs a metric query in time chunks with bounded concurrency and merges the frames of the chunks. When
+// some chunks fail the frames of the others are returned with a warning notice, the query fails when all chunks do.
+func runSplitQuery(ctx context.Context, api *LokiAPI, query *lokiQuery, splitting querySplitting, responseOpts ResponseOpts, plog log.Logger) (*backend.DataResponse, error) {
+	ranges := splitTimeRange(query.Start, query.End, query.Step, splitting.duration)
+	if len(ranges) == 1 {
+		return runQuery(ctx, api, query, responseOpts, plog)
+	}
+
+	responses := make([]*backend.DataResponse, len(ranges))
+	errs := make([]error, len(ranges))
+	var mu sync.Mutex
+	_ = concurrency.ForEachJob(ctx, len(ranges), splitting.concurrency, func(ctx context.Context, idx int) error {
+		chunk := *query
+		chunk.Start, chunk.End = ranges[idx].start, ranges[idx].end
+		res, err := runQuery(ctx, api, &chunk, responseOpts, plog)
+
+		mu.Lock()
+		defer mu.Unlock()
+		responses[idx], errs[idx] = res, err
+		return nil // errors are saved per chunk, the other chunks still run
+	})
+
+	var successful []*backend.DataResponse
+	var failed int
+	var firstErr error
+	for i, err := range errs {
+		if err == nil && responses[i] == nil {
+			// the chunk didn't run, the context was canceled
+			err = ctx.Err()
+		}
+		if err != nil || responses[i] == nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		successful = append(successful, responses[i])
+	}
+
+	if len(successful) == 0 {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("all %d time chunks of the query failed", len(ranges))
+		}
+		return &backend.DataResponse{}, firstErr
+	}
+
+	res := &backend.DataResponse{Frames: mergeChunkFrames(successful)}
+	if failed > 0 {
+		plog.Warn("Some time chunks of a split query failed", "failed", failed, "chunks", len(ranges), "error", firstErr)
+		notice := data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     fmt.Sprintf("Partial result: %d of %d time chunks of the query failed: %v", failed, len(ranges), firstErr),
+		}
+		for _, frame := range res.Frames {
+			frame.AppendNotices(notice)
+		}
+	}
+	return res, nil
+}
+
+// mergeChunkFrames appends the rows of the frames of a series in consecutive chunks. The responses are in time order,
+// a series is identified by the name and labels of its frame.
+func mergeChunkFrames(responses []*backend.DataResponse) data.Frames {
+	var merged data.Frames
+	bySeries := map[string]*data.Frame{}
+
+	for _, res := range responses {
+		for _, frame := range res.Frames {
+			key := seriesKey(frame)
+			existing, ok := bySeries[key]
+			if !ok || !sameFieldTypes(existing, frame) {
+				bySeries[key] = frame
+				merged = append(merged, frame)
+				continue
+			}
+			for i, field := range frame.Fields {
+				for row := 0; row < field.Len(); row++ {
+					existing.Fields[i].Append(field.At(row))
+				}
+			}
+		}
+	}
+	return merged
+}
+
+func seriesKey(frame *data.Frame) string {
+	var b strings.Builder
+	b.WriteString(frame.Name)
+	for _, field := range frame.Fields {
+		b.WriteString("\x00")
+		b.WriteString(field.Name)
+		b.WriteString(field.Labels.String())
+	}
+	return b.String()
+}
+
+func sameFieldTypes(a, b *data.Frame) bool {
+	if len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Type() != b.Fields[i].Type() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+package loki
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestSplitTimeRange(t *testing.T) {
+	start := time.UnixMilli(1_000_000)
+
+	t.Run("chunks are aligned to step and don't overlap", func(t *testing.T) {
+		ranges := splitTimeRange(start, start.Add(25*time.Minute), time.Minute, 10*time.Minute)
+		require.Len(t, ranges, 3)
+
+		alignedStart := time.UnixMilli(960_000)
+		assert.Equal(t, alignedStart, ranges[0].start)
+		assert.Equal(t, alignedStart.Add(9*time.Minute), ranges[0].end)
+		assert.Equal(t, alignedStart.Add(10*time.Minute), ranges[1].start)
+		assert.Equal(t, alignedStart.Add(20*time.Minute), ranges[2].start)
+		assert.Equal(t, start.Add(25*time.Minute), ranges[2].end)
+	})
+
+	t.Run("duration is lowered to a multiple of step", func(t *testing.T) {
+		ranges := splitTimeRange(time.UnixMilli(0), time.UnixMilli(0).Add(time.Hour), 7*time.Minute, 15*time.Minute)
+		require.Len(t, ranges, 5)
+		assert.Equal(t, 14*time.Minute, ranges[1].start.Sub(ranges[0].start))
+	})
+
+	t.Run("last chunk ends with the range when the end is on a chunk boundary", func(t *testing.T) {
+		alignedStart := time.UnixMilli(960_000)
+		ranges := splitTimeRange(start, alignedStart.Add(30*time.Minute), time.Minute, 10*time.Minute)
+		require.Len(t, ranges, 3)
+		assert.Equal(t, alignedStart.Add(20*time.Minute), ranges[2].start)
+		assert.Equal(t, alignedStart.Add(30*time.Minute), ranges[2].end)
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+		ranges := splitTimeRange(start, start, time.Minute, 10*time.Minute)
+		assert.Equal(t, []timeRange{{start: time.UnixMilli(960_000), end: start}}, ranges)
+	})
+
+	t.Run("duration smaller than step", func(t *testing.T) {
+		ranges := splitTimeRange(start, start.Add(time.Hour), 10*time.Minute, time.Minute)
+		assert.Equal(t, []timeRange{{start: start, end: start.Add(time.Hour)}}, ranges)
+	})
+}
+
+func TestShouldSplit(t *testing.T) {
+	start := time.UnixMilli(0)
+	splitting := querySplitting{duration: time.Hour, concurrency: 2}
+	metricQuery := &lokiQuery{Expr: `sum(rate({job="grafana"}[5m]))`, QueryType: QueryTypeRange, Start: start, End: start.Add(3 * time.Hour)}
+
+	assert.True(t, shouldSplit(metricQuery, splitting))
+	assert.False(t, shouldSplit(metricQuery, querySplitting{}))
+
+	logsQuery := *metricQuery
+	logsQuery.Expr = ` {job="grafana"} |= "error"`
+	assert.False(t, shouldSplit(&logsQuery, splitting))
+
+	instantQuery := *metricQuery
+	instantQuery.QueryType = QueryTypeInstant
+	assert.False(t, shouldSplit(&instantQuery, splitting))
+
+	shortQuery := *metricQuery
+	shortQuery.End = start.Add(time.Hour)
+	assert.False(t, shouldSplit(&shortQuery, splitting))
+}
+
+func TestParseQuerySplitting(t *testing.T) {
+	splitting, err := parseQuerySplitting([]byte(`{"querySplitDuration":"1d","querySplitConcurrency":2}`))
+	require.NoError(t, err)
+	assert.Equal(t, querySplitting{duration: 24 * time.Hour, concurrency: 2}, splitting)
+
+	splitting, err = parseQuerySplitting([]byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, querySplitting{concurrency: defaultSplitConcurrency}, splitting)
+
+	_, err = parseQuerySplitting([]byte(`{"querySplitDuration":"soon"}`))
+	require.Error(t, err)
+}
+
+// chunkRoundTripper answers every chunk with a matrix holding one sample at the start of the chunk, and fails the
+// chunks starting at failStart
+type chunkRoundTripper struct {
+	mu        sync.Mutex
+	starts    []int64
+	failStart int64
+	failAll   bool
+}
+
+func (rt *chunkRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start, err := strconv.ParseInt(req.URL.Query().Get("start"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	rt.mu.Lock()
+	rt.starts = append(rt.starts, start)
+	rt.mu.Unlock()
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	if rt.failAll || start == rt.failStart {
+		return &http.Response{StatusCode: http.StatusBadRequest, Header: header, Body: io.NopCloser(bytes.NewBufferString(`{"message":"too many outstanding requests"}`))}, nil
+	}
+
+	body := fmt.Sprintf(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"job":"grafana"},"values":[[%d,"1"]]}]}}`, start/int64(time.Second))
+	return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+}
+
+func TestRunSplitQuery(t *testing.T) {
+	start := time.Unix(0, 0).UTC()
+	query := &lokiQuery{
+		Expr:      `sum by (job) (rate({job="grafana"}[1m]))`,
+		QueryType: QueryTypeRange,
+		Direction: DirectionBackward,
+		Step:      time.Minute,
+		Start:     start,
+		End:       start.Add(3 * time.Hour),
+		RefID:     "A",
+	}
+	splitting := querySplitting{duration: time.Hour, concurrency: 2}
+	newAPI := func(rt http.RoundTripper) *LokiAPI {
+		return newLokiAPI(&http.Client{Transport: rt}, "http://localhost:3100", backend.NewLoggerWith("logger", "test"), tracing.InitializeTracerForTest(), false)
+	}
+
+	t.Run("frames of the chunks are merged", func(t *testing.T) {
+		rt := &chunkRoundTripper{failStart: -1}
+		res, err := runSplitQuery(context.Background(), newAPI(rt), query, splitting, ResponseOpts{}, backend.NewLoggerWith("logger", "test"))
+		require.NoError(t, err)
+
+		assert.Len(t, rt.starts, 3)
+		require.Len(t, res.Frames, 1)
+		frame := res.Frames[0]
+		require.Equal(t, 3, frame.Rows())
+		for i, expected := range []time.Time{start, start.Add(time.Hour), start.Add(2 * time.Hour)} {
+			assert.Equal(t, expected, frame.Fields[0].At(i).(time.Time).UTC())
+		}
+		assert.Empty(t, frame.Meta.Notices)
+	})
+
+	t.Run("failed chunks return a partial result with a warning", func(t *testing.T) {
+		rt := &chunkRoundTripper{failStart: start.Add(time.Hour).UnixNano()}
+		res, err := runSplitQuery(context.Background(), newAPI(rt), query, splitting, ResponseOpts{}, backend.NewLoggerWith("logger", "test"))
+		require.NoError(t, err)
+
+		require.Len(t, res.Frames, 1)
+		assert.Equal(t, 2, res.Frames[0].Rows())
+		require.Len(t, res.Frames[0].Meta.Notices, 1)
+		assert.Equal(t, data.NoticeSeverityWarning, res.Frames[0].Meta.Notices[0].Severity)
+		assert.Contains(t, res.Frames[0].Meta.Notices[0].Text, "1 of 3 time chunks")
+	})
+
+	t.Run("the query fails when all chunks fail", func(t *testing.T) {
+		rt := &chunkRoundTripper{failAll: true}
+		_, err := runSplitQuery(context.Background(), newAPI(rt), query, splitting, ResponseOpts{}, backend.NewLoggerWith("logger", "test"))
+		require.ErrorContains(t, err, "too many outstanding requests")
+		assert.Len(t, rt.starts, 3)
+	})
+}
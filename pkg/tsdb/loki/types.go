@@ -39,4 +39,5 @@ type lokiQuery struct {
 	End                 time.Time
 	RefID               string
 	SupportingQueryType SupportingQueryType
+	SplitDuration       time.Duration
 }
@@ -0,0 +1,42 @@
+package mssql
+
+import "github.com/grafana/grafana/pkg/tsdb/sqlschema"
+
+// mssqlSchemaQueries introspects the schema of the database the connection uses, through INFORMATION_SCHEMA and the
+// catalog views. It's the only database listed and the database parameter is ignored.
+type mssqlSchemaQueries struct{}
+
+var _ sqlschema.Queries = mssqlSchemaQueries{}
+
+func (mssqlSchemaQueries) Databases() string {
+	return `SELECT DB_NAME()`
+}
+
+func (mssqlSchemaQueries) Schemas(string) (string, []any) {
+	return `SELECT schema_name FROM INFORMATION_SCHEMA.SCHEMATA
+		WHERE schema_name NOT IN ('INFORMATION_SCHEMA', 'sys', 'guest') AND schema_name NOT LIKE 'db[_]%'
+		ORDER BY schema_name`, nil
+}
+
+func (mssqlSchemaQueries) Tables(_, schema string) (string, []any) {
+	return `SELECT table_name FROM INFORMATION_SCHEMA.TABLES
+		WHERE table_schema = COALESCE(NULLIF(@p1, ''), SCHEMA_NAME())
+		ORDER BY table_name`, []any{schema}
+}
+
+func (mssqlSchemaQueries) Columns(_, schema, table string) (string, []any) {
+	return `SELECT column_name, data_type, is_nullable FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE table_schema = COALESCE(NULLIF(@p1, ''), SCHEMA_NAME()) AND table_name = @p2
+		ORDER BY ordinal_position`, []any{schema, table}
+}
+
+func (mssqlSchemaQueries) Indexes(_, schema, table string) (string, []any) {
+	return `SELECT i.name, c.name, i.is_unique, i.is_primary_key
+		FROM sys.indexes i
+		JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		JOIN sys.tables t ON t.object_id = i.object_id
+		JOIN sys.schemas s ON s.schema_id = t.schema_id
+		WHERE s.name = COALESCE(NULLIF(@p1, ''), SCHEMA_NAME()) AND t.name = @p2 AND i.name IS NOT NULL
+		ORDER BY i.name, ic.key_ordinal`, []any{schema, table}
+}
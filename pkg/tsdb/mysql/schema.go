@@ -0,0 +1,37 @@
+package mysql
+
+import "github.com/grafana/grafana/pkg/tsdb/sqlschema"
+
+// mysqlSchemaQueries introspects the schema with information_schema. MySQL databases and schemas are the same thing,
+// the schema parameter is ignored.
+type mysqlSchemaQueries struct{}
+
+var _ sqlschema.Queries = mysqlSchemaQueries{}
+
+func (mysqlSchemaQueries) Databases() string {
+	return `SELECT schema_name FROM information_schema.schemata
+		WHERE schema_name NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys')
+		ORDER BY schema_name`
+}
+
+func (mysqlSchemaQueries) Schemas(string) (string, []any) {
+	return "", nil
+}
+
+func (mysqlSchemaQueries) Tables(database, _ string) (string, []any) {
+	return `SELECT table_name FROM information_schema.tables
+		WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE())
+		ORDER BY table_name`, []any{database}
+}
+
+func (mysqlSchemaQueries) Columns(database, _, table string) (string, []any) {
+	return `SELECT column_name, column_type, is_nullable FROM information_schema.columns
+		WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ?
+		ORDER BY ordinal_position`, []any{database, table}
+}
+
+func (mysqlSchemaQueries) Indexes(database, _, table string) (string, []any) {
+	return `SELECT index_name, column_name, non_unique = 0, index_name = 'PRIMARY' FROM information_schema.statistics
+		WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ?
+		ORDER BY index_name, seq_in_index`, []any{database, table}
+}
@@ -20,6 +20,8 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqlschema"
 )
 
 // MetaKeyExecutedQueryString is the key where the executed query should get stored
@@ -77,6 +79,8 @@ type DataPluginConfiguration struct {
 	TimeColumnNames   []string
 	MetricColumnTypes []string
 	RowLimit          int64
+	// SchemaQueries enables the schema introspection resources
+	SchemaQueries sqlschema.Queries
 }
 
 type DataSourceHandler struct {
@@ -89,6 +93,7 @@ type DataSourceHandler struct {
 	dsInfo                 DataSourceInfo
 	rowLimit               int64
 	userError              string
+	schema                 *sqlschema.Handler
 }
 
 type QueryJson struct {
@@ -135,6 +140,11 @@ func NewQueryDataHandler(userFacingDefaultError string, db *sql.DB, config DataP
 	}
 
 	queryDataHandler.db = db
+	if config.SchemaQueries != nil {
+		queryDataHandler.schema = sqlschema.NewHandler(config.SchemaQueries, db, config.DSInfo.Database, log, func(err error) error {
+			return queryDataHandler.TransformQueryError(log, err)
+		})
+	}
 	return &queryDataHandler, nil
 }
 
@@ -153,6 +163,11 @@ func (e *DataSourceHandler) Dispose() {
 	e.log.Debug("DB disposed")
 }
 
+// CallResource serves the schema introspection resources of the database
+func (e *DataSourceHandler) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return e.schema.CallResource(ctx, req, sender)
+}
+
 func (e *DataSourceHandler) Ping() error {
 	return e.db.Ping()
 }
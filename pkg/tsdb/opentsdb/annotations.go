@@ -0,0 +1,141 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// isAnnotationQuery returns true for annotation queries reading the annotations of a metric
+func isAnnotationQuery(query backend.DataQuery) bool {
+	model, err := simplejson.NewJson(query.JSON)
+	if err != nil {
+		return false
+	}
+	return model.Get("fromAnnotations").MustBool() && model.Get("target").MustString() != ""
+}
+
+// queryAnnotations returns the annotations of the series of the target metric, or the
+// global annotations when isGlobal is set.
+func (s *Service) queryAnnotations(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery) backend.DataResponse {
+	model, err := simplejson.NewJson(query.JSON)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+	}
+
+	tsdbQuery := OpenTsdbQuery{
+		Start: query.TimeRange.From.UnixNano() / int64(time.Millisecond),
+		End:   query.TimeRange.To.UnixNano() / int64(time.Millisecond),
+		Queries: []map[string]any{
+			{"aggregator": "sum", "metric": model.Get("target").MustString()},
+		},
+		GlobalAnnotations: true,
+	}
+
+	req, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+	}
+
+	body, dr := s.doRequest(logger, dsInfo, req)
+	if dr != nil {
+		return *dr
+	}
+
+	var responseData []OpenTsdbResponse
+	if err := json.Unmarshal(body, &responseData); err != nil {
+		logger.Info("Failed to unmarshal opentsdb annotations response", "error", err, "body", string(body))
+		return downstreamErrorResponse(backend.StatusBadGateway, err)
+	}
+
+	isGlobal := model.Get("isGlobal").MustBool()
+	return backend.DataResponse{Frames: data.Frames{annotationsToFrame(query.RefID, responseData, isGlobal)}}
+}
+
+// annotationsToFrame converts OpenTSDB annotations to an annotations frame. Annotations returned
+// with several series, such as global annotations, are only added once.
+func annotationsToFrame(refID string, responseData []OpenTsdbResponse, isGlobal bool) *data.Frame {
+	frame := data.NewFrame(refID,
+		data.NewField("time", nil, []time.Time{}),
+		data.NewField("timeEnd", nil, []time.Time{}),
+		data.NewField("text", nil, []string{}),
+		data.NewField("tags", nil, []string{}),
+	)
+
+	seen := make(map[string]bool)
+	for _, series := range responseData {
+		annotations := series.Annotations
+		if isGlobal {
+			annotations = series.GlobalAnnotations
+		}
+		for _, a := range annotations {
+			key := fmt.Sprintf("%s/%d/%s", a.TSUID, a.StartTime, a.Description)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			start := time.Unix(a.StartTime, 0).UTC()
+			end := start
+			if a.EndTime > a.StartTime {
+				end = time.Unix(a.EndTime, 0).UTC()
+			}
+			frame.AppendRow(start, end, a.Description, annotationTags(series, isGlobal))
+		}
+	}
+	return frame
+}
+
+// annotationTags returns the tags of the series of per-series annotations, as a comma separated list
+func annotationTags(series OpenTsdbResponse, isGlobal bool) string {
+	if isGlobal || len(series.Tags) == 0 {
+		return ""
+	}
+	labels := data.Labels(series.Tags)
+	return labels.String()
+}
+
+// doRequest sends the request and returns the response body. Failed requests and non 2xx
+// responses are returned as a data response with a downstream error.
+func (s *Service) doRequest(logger log.Logger, dsInfo *datasourceInfo, req *http.Request) ([]byte, *backend.DataResponse) {
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		dr := downstreamErrorResponse(backend.StatusBadGateway, err)
+		return nil, &dr
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		dr := downstreamErrorResponse(backend.StatusBadGateway, err)
+		return nil, &dr
+	}
+
+	if res.StatusCode/100 != 2 {
+		logger.Info("Request failed", "status", res.Status, "body", string(body))
+		dr := downstreamErrorResponse(backend.Status(res.StatusCode), fmt.Errorf("request failed, status: %s", res.Status))
+		return nil, &dr
+	}
+	return body, nil
+}
+
+func downstreamErrorResponse(status backend.Status, err error) backend.DataResponse {
+	return backend.DataResponse{
+		Error:       err,
+		Status:      status,
+		ErrorSource: backend.ErrorSourceDownstream,
+	}
+}
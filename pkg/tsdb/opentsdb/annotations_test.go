@@ -0,0 +1,128 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryAnnotations(t *testing.T) {
+	var requests []OpenTsdbQuery
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/query", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var q OpenTsdbQuery
+		require.NoError(t, json.Unmarshal(body, &q))
+		requests = append(requests, q)
+
+		_, _ = w.Write([]byte(`[
+			{
+				"metric": "deploys",
+				"tags": { "host": "web01" },
+				"dps": {},
+				"annotations": [
+					{ "tsuid": "01", "description": "Deploy v2", "startTime": 1405544146, "endTime": 0 }
+				],
+				"globalAnnotations": [
+					{ "description": "Outage", "startTime": 1405544100, "endTime": 1405544200 }
+				]
+			},
+			{
+				"metric": "deploys",
+				"tags": { "host": "web02" },
+				"dps": {},
+				"globalAnnotations": [
+					{ "description": "Outage", "startTime": 1405544100, "endTime": 1405544200 }
+				]
+			}
+		]`))
+	}))
+	t.Cleanup(ts.Close)
+
+	service := &Service{
+		im: testInstanceManager{info: datasourceInfo{URL: ts.URL, HTTPClient: ts.Client()}},
+	}
+	timeRange := backend.TimeRange{From: time.Unix(1405544000, 0), To: time.Unix(1405545000, 0)}
+
+	t.Run("returns the annotations of the series", func(t *testing.T) {
+		requests = nil
+		res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{RefID: "A", TimeRange: timeRange, JSON: []byte(`{"fromAnnotations": true, "target": "deploys"}`)},
+			},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, requests, 1)
+		require.True(t, requests[0].GlobalAnnotations)
+		require.Equal(t, int64(1405544000000), requests[0].Start)
+		require.Equal(t, "deploys", requests[0].Queries[0]["metric"])
+
+		dr := res.Responses["A"]
+		require.NoError(t, dr.Error)
+		frame := dr.Frames[0]
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, time.Unix(1405544146, 0).UTC(), frame.Fields[0].At(0))
+		require.Equal(t, time.Unix(1405544146, 0).UTC(), frame.Fields[1].At(0))
+		require.Equal(t, "Deploy v2", frame.Fields[2].At(0))
+		require.Equal(t, "host=web01", frame.Fields[3].At(0))
+	})
+
+	t.Run("returns the global annotations once", func(t *testing.T) {
+		res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{RefID: "A", TimeRange: timeRange, JSON: []byte(`{"fromAnnotations": true, "target": "deploys", "isGlobal": true}`)},
+			},
+		})
+		require.NoError(t, err)
+
+		frame := res.Responses["A"].Frames[0]
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, time.Unix(1405544200, 0).UTC(), frame.Fields[1].At(0))
+		require.Equal(t, "Outage", frame.Fields[2].At(0))
+		require.Equal(t, "", frame.Fields[3].At(0))
+	})
+}
+
+func TestQueryAnnotationsError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(ts.Close)
+
+	service := &Service{
+		im: testInstanceManager{info: datasourceInfo{URL: ts.URL, HTTPClient: ts.Client()}},
+	}
+
+	res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{
+			{RefID: "A", JSON: []byte(`{"fromAnnotations": true, "target": "deploys"}`)},
+		},
+	})
+	require.NoError(t, err)
+	dr := res.Responses["A"]
+	require.Error(t, dr.Error)
+	require.Equal(t, backend.StatusBadRequest, dr.Status)
+	require.Equal(t, backend.ErrorSourceDownstream, dr.ErrorSource)
+}
+
+type testInstanceManager struct {
+	info datasourceInfo
+}
+
+func (m testInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return &m.info, nil
+}
+
+func (m testInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
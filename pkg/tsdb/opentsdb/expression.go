@@ -0,0 +1,126 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const expressionQueryType = "expression"
+
+// queryExpression runs a query against the /api/query/exp endpoint. The expression model holds
+// the filters, metrics, expressions and outputs of the query, the time section is built from the
+// query time range and the aggregation and downsampling options of the query.
+func (s *Service) queryExpression(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery) backend.DataResponse {
+	body, err := buildExpression(query)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, err.Error())
+	}
+
+	req, err := s.createPostRequest(ctx, logger, dsInfo, "api/query/exp", body)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+	}
+
+	resBody, dr := s.doRequest(logger, dsInfo, req)
+	if dr != nil {
+		return *dr
+	}
+
+	var responseData OpenTsdbExpResponse
+	if err := json.Unmarshal(resBody, &responseData); err != nil {
+		logger.Info("Failed to unmarshal opentsdb expression response", "error", err, "body", string(resBody))
+		return downstreamErrorResponse(backend.StatusBadGateway, err)
+	}
+
+	return backend.DataResponse{Frames: expressionOutputsToFrames(responseData.Outputs)}
+}
+
+func buildExpression(query backend.DataQuery) (map[string]any, error) {
+	model, err := simplejson.NewJson(query.JSON)
+	if err != nil {
+		return nil, err
+	}
+
+	expression := model.Get("expression").MustMap()
+	if metrics, ok := expression["metrics"].([]any); !ok || len(metrics) == 0 {
+		return nil, errors.New("expression query requires at least one metric")
+	}
+
+	aggregator := model.Get("aggregator").MustString()
+	if aggregator == "" {
+		aggregator = "sum"
+	}
+	timeSection := map[string]any{
+		"start":      query.TimeRange.From.UnixNano() / int64(time.Millisecond),
+		"end":        query.TimeRange.To.UnixNano() / int64(time.Millisecond),
+		"aggregator": aggregator,
+	}
+
+	if !model.Get("disableDownsampling").MustBool() {
+		downsampleInterval := model.Get("downsampleInterval").MustString()
+		if downsampleInterval == "" {
+			downsampleInterval = "1m" // default value for blank
+		}
+		downsampleAggregator := model.Get("downsampleAggregator").MustString()
+		if downsampleAggregator == "" {
+			downsampleAggregator = "avg"
+		}
+		downsampler := map[string]any{
+			"interval":   downsampleInterval,
+			"aggregator": downsampleAggregator,
+		}
+		if fillPolicy := model.Get("downsampleFillPolicy").MustString(); fillPolicy != "" && fillPolicy != "none" {
+			downsampler["fillPolicy"] = map[string]any{"policy": fillPolicy}
+		}
+		timeSection["downsampler"] = downsampler
+	}
+
+	body := make(map[string]any, len(expression)+1)
+	for k, v := range expression {
+		body[k] = v
+	}
+	body["time"] = timeSection
+	return body, nil
+}
+
+// expressionOutputsToFrames returns a frame per series of the outputs. The data points of an
+// output are rows of a timestamp followed by the value of every series, described by the meta.
+func expressionOutputsToFrames(outputs []OpenTsdbExpOutput) data.Frames {
+	frames := data.Frames{}
+	for _, output := range outputs {
+		name := output.Alias
+		if name == "" {
+			name = output.ID
+		}
+
+		for _, meta := range output.Meta {
+			if meta.Index == 0 {
+				// The first column holds the timestamps
+				continue
+			}
+
+			timeVector := make([]time.Time, 0, len(output.Dps))
+			values := make([]*float64, 0, len(output.Dps))
+			for _, row := range output.Dps {
+				if len(row) <= meta.Index || row[0] == nil {
+					continue
+				}
+				timeVector = append(timeVector, time.UnixMilli(int64(*row[0])).UTC())
+				values = append(values, row[meta.Index])
+			}
+
+			frames = append(frames, data.NewFrame(name,
+				data.NewField("time", nil, timeVector),
+				data.NewField("value", meta.CommonTags, values)))
+		}
+	}
+	return frames
+}
@@ -0,0 +1,127 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryExpression(t *testing.T) {
+	var expRequest map[string]any
+	var metricRequest OpenTsdbQuery
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		switch r.URL.Path {
+		case "/api/query/exp":
+			require.NoError(t, json.Unmarshal(body, &expRequest))
+			_, _ = w.Write([]byte(`{
+				"outputs": [
+					{
+						"id": "e",
+						"alias": "errors ratio",
+						"dps": [[1405544100000, 0.5, 1], [1405544160000, null, 2]],
+						"meta": [
+							{ "index": 0, "metrics": ["timestamp"] },
+							{ "index": 1, "metrics": ["errors", "requests"], "commonTags": { "host": "web01" } },
+							{ "index": 2, "metrics": ["errors", "requests"], "commonTags": { "host": "web02" } }
+						]
+					}
+				]
+			}`))
+		case "/api/query":
+			require.NoError(t, json.Unmarshal(body, &metricRequest))
+			_, _ = w.Write([]byte(`[{ "metric": "cpu", "dps": { "1405544146": 50.0 }, "tags": {} }]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	service := &Service{
+		im: testInstanceManager{info: datasourceInfo{URL: ts.URL, HTTPClient: ts.Client()}},
+	}
+	timeRange := backend.TimeRange{From: time.Unix(1405544000, 0), To: time.Unix(1405545000, 0)}
+
+	t.Run("runs expression and metric queries", func(t *testing.T) {
+		res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{RefID: "A", TimeRange: timeRange, JSON: []byte(`{"metric": "cpu", "aggregator": "avg", "disableDownsampling": true}`)},
+				{RefID: "B", TimeRange: timeRange, QueryType: expressionQueryType, JSON: []byte(`{
+					"queryType": "expression",
+					"aggregator": "max",
+					"downsampleInterval": "5m",
+					"downsampleFillPolicy": "null",
+					"expression": {
+						"metrics": [{ "id": "a", "metric": "errors" }, { "id": "b", "metric": "requests" }],
+						"expressions": [{ "id": "e", "expr": "a / b" }],
+						"outputs": [{ "id": "e", "alias": "errors ratio" }]
+					}
+				}`)},
+			},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, metricRequest.Queries, 1)
+		require.Equal(t, "cpu", metricRequest.Queries[0]["metric"])
+		require.Len(t, res.Responses["A"].Frames, 1)
+
+		require.Equal(t, map[string]any{
+			"start":      float64(1405544000000),
+			"end":        float64(1405545000000),
+			"aggregator": "max",
+			"downsampler": map[string]any{
+				"interval":   "5m",
+				"aggregator": "avg",
+				"fillPolicy": map[string]any{"policy": "null"},
+			},
+		}, expRequest["time"])
+		require.Len(t, expRequest["metrics"], 2)
+
+		dr := res.Responses["B"]
+		require.NoError(t, dr.Error)
+		require.Len(t, dr.Frames, 2)
+		frame := dr.Frames[0]
+		require.Equal(t, "errors ratio", frame.Name)
+		require.Equal(t, data.Labels{"host": "web01"}, frame.Fields[1].Labels)
+		require.Equal(t, time.UnixMilli(1405544100000).UTC(), frame.Fields[0].At(0))
+		require.Equal(t, 0.5, *frame.Fields[1].At(0).(*float64))
+		require.Nil(t, frame.Fields[1].At(1))
+		require.Equal(t, 2.0, *dr.Frames[1].Fields[1].At(1).(*float64))
+	})
+
+	t.Run("returns an error for expressions without metrics", func(t *testing.T) {
+		res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{RefID: "A", TimeRange: timeRange, QueryType: expressionQueryType, JSON: []byte(`{"queryType": "expression", "expression": {}}`)},
+			},
+		})
+		require.NoError(t, err)
+		require.EqualError(t, res.Responses["A"].Error, "expression query requires at least one metric")
+	})
+}
+
+func TestBuildMetricWithPercentiles(t *testing.T) {
+	service := &Service{}
+	metric := service.buildMetric(backend.DataQuery{
+		JSON: []byte(`{
+			"metric": "latency",
+			"aggregator": "sum",
+			"disableDownsampling": true,
+			"percentiles": [99.9, 95],
+			"showHistogramBuckets": true
+		}`),
+	})
+
+	require.Equal(t, []any{json.Number("99.9"), json.Number("95")}, metric["percentiles"])
+	require.Equal(t, true, metric["showHistogramBuckets"])
+}
@@ -68,14 +68,36 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 
 	logger := logger.FromContext(ctx)
 
-	q := req.Queries[0]
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	// Annotation and expression queries use their own requests, metric queries are sent together
+	result := backend.NewQueryDataResponse()
+	metricQueries := make([]backend.DataQuery, 0, len(req.Queries))
+	for _, query := range req.Queries {
+		switch {
+		case isAnnotationQuery(query):
+			result.Responses[query.RefID] = s.queryAnnotations(ctx, logger, dsInfo, query)
+		case query.QueryType == expressionQueryType:
+			result.Responses[query.RefID] = s.queryExpression(ctx, logger, dsInfo, query)
+		default:
+			metricQueries = append(metricQueries, query)
+		}
+	}
+	if len(metricQueries) == 0 {
+		return result, nil
+	}
+
+	q := metricQueries[0]
 
 	myRefID := q.RefID
 
 	tsdbQuery.Start = q.TimeRange.From.UnixNano() / int64(time.Millisecond)
 	tsdbQuery.End = q.TimeRange.To.UnixNano() / int64(time.Millisecond)
 
-	for _, query := range req.Queries {
+	for _, query := range metricQueries {
 		metric := s.buildMetric(query)
 		tsdbQuery.Queries = append(tsdbQuery.Queries, metric)
 	}
@@ -85,11 +107,6 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		logger.Debug("OpenTsdb request", "params", tsdbQuery)
 	}
 
-	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
-	if err != nil {
-		return nil, err
-	}
-
 	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
@@ -107,20 +124,27 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		}
 	}()
 
-	result, err := s.parseResponse(logger, res, myRefID)
+	metricResult, err := s.parseResponse(logger, res, myRefID)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
 	}
 
+	for refID, r := range metricResult.Responses {
+		result.Responses[refID] = r
+	}
 	return result, nil
 }
 
 func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, data OpenTsdbQuery) (*http.Request, error) {
+	return s.createPostRequest(ctx, logger, dsInfo, "api/query", data)
+}
+
+func (s *Service) createPostRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, endpoint string, data any) (*http.Request, error) {
 	u, err := url.Parse(dsInfo.URL)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = path.Join(u.Path, "api/query")
+	u.Path = path.Join(u.Path, endpoint)
 
 	postData, err := json.Marshal(data)
 	if err != nil {
@@ -251,6 +275,16 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]any {
 		metric["filters"] = filters.MustArray()
 	}
 
+	// Setting histogram options, percentiles are computed from histogram metrics
+	percentiles, percentilesCheck := model.CheckGet("percentiles")
+	if percentilesCheck && len(percentiles.MustArray()) > 0 {
+		metric["percentiles"] = percentiles.MustArray()
+	}
+
+	if model.Get("showHistogramBuckets").MustBool() {
+		metric["showHistogramBuckets"] = true
+	}
+
 	return metric
 }
 
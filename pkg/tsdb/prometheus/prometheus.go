@@ -8,7 +8,10 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
 
+	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/promlib"
+	"github.com/grafana/grafana/pkg/promlib/querydata"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/azureauth"
 )
 
@@ -16,11 +19,25 @@ type Service struct {
 	lib *promlib.Service
 }
 
-func ProvideService(httpClientProvider *sdkhttpclient.Provider) *Service {
+func ProvideService(httpClientProvider *sdkhttpclient.Provider, cfg *setting.Cfg, cacheStorage remotecache.CacheStorage) *Service {
 	plog := backend.NewLoggerWith("logger", "tsdb.prometheus")
 	plog.Debug("Initializing")
 	return &Service{
-		lib: promlib.NewService(httpClientProvider, plog, extendClientOpts),
+		lib: promlib.NewServiceWithCache(httpClientProvider, plog, extendClientOpts, queryCache(cfg, cacheStorage)),
+	}
+}
+
+// queryCache returns the remote cache to store the results of backend incremental queries when it is
+// shared between Grafana instances. The database cache is not used, as every refresh would write to it.
+func queryCache(cfg *setting.Cfg, cacheStorage remotecache.CacheStorage) querydata.Cache {
+	if cacheStorage == nil || cfg == nil || cfg.RemoteCacheOptions == nil {
+		return nil
+	}
+	switch cfg.RemoteCacheOptions.Name {
+	case "redis", "memcached":
+		return cacheStorage
+	default:
+		return nil
 	}
 }
 
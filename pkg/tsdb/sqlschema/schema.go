@@ -0,0 +1,309 @@
+// Package sqlschema serves the schema introspection resources of the SQL data sources. The dialect specific parts are
+// the queries, see Queries.
+package sqlschema
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+)
+
+// cacheTTL is how long the schema of the database is cached, the cache is dropped with the data source instance when
+// the data source settings change
+const cacheTTL = 5 * time.Minute
+
+var (
+	errNotSupported       = errors.New("not supported by this data source")
+	errDatabaseNotAllowed = errors.New("database can't be introspected by this data source")
+	errMissingParameter   = errors.New("missing parameter")
+)
+
+// Queries builds the dialect specific queries used to introspect the schema of the database. A query returning an
+// empty string isn't supported by the dialect.
+type Queries interface {
+	// Databases returns a query selecting the names of the databases the other queries can introspect. A dialect that
+	// can't scope its queries to another database than the one of the connection only selects that database.
+	Databases() string
+	// Schemas returns a query selecting the schema names of a database
+	Schemas(database string) (string, []any)
+	// Tables returns a query selecting the table names of a schema, the default schema when schema is empty
+	Tables(database, schema string) (string, []any)
+	// Columns returns a query selecting the name, type and nullability (YES or NO) of the columns of a table
+	Columns(database, schema, table string) (string, []any)
+	// Indexes returns a query selecting the name, column name, uniqueness and primary key flag of the indexes of a
+	// table, ordered by index and column position
+	Indexes(database, schema, table string) (string, []any)
+}
+
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+type Index struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+	Primary bool     `json:"primary"`
+}
+
+// Handler serves the schema introspection resources: databases, schemas, tables, columns and indexes. The database,
+// schema and table are passed as query parameters.
+type Handler struct {
+	queries Queries
+	db      *sql.DB
+	// database is the default database of the data source, the only one that can be introspected when it's set
+	database       string
+	log            log.Logger
+	transformError func(error) error
+	cache          cache
+}
+
+// NewHandler returns a handler introspecting db with the queries of its dialect. transformError turns the query
+// errors into errors that can be shown to the user.
+func NewHandler(queries Queries, db *sql.DB, database string, logger log.Logger, transformError func(error) error) *Handler {
+	return &Handler{
+		queries:        queries,
+		db:             db,
+		database:       database,
+		log:            logger,
+		transformError: transformError,
+	}
+}
+
+// CallResource serves the schema introspection resources, a nil handler doesn't support any
+func (h *Handler) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	if h == nil {
+		return sendResponse(sender, http.StatusNotFound, map[string]string{"message": "schema introspection is " + errNotSupported.Error()})
+	}
+	if req.Method != http.MethodGet {
+		return sendResponse(sender, http.StatusMethodNotAllowed, map[string]string{"message": "method not allowed"})
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return sendResponse(sender, http.StatusBadRequest, map[string]string{"message": err.Error()})
+	}
+
+	result, err := h.schema(ctx, strings.Trim(req.Path, "/"), u.Query())
+	switch {
+	case errors.Is(err, errNotSupported):
+		return sendResponse(sender, http.StatusNotFound, map[string]string{"message": err.Error()})
+	case errors.Is(err, errDatabaseNotAllowed), errors.Is(err, errMissingParameter):
+		return sendResponse(sender, http.StatusBadRequest, map[string]string{"message": err.Error()})
+	case err != nil:
+		h.log.Error("Schema introspection failed", "path", req.Path, "error", err)
+		return sendResponse(sender, http.StatusInternalServerError, map[string]string{"message": h.transformError(err).Error()})
+	}
+	return sendResponse(sender, http.StatusOK, result)
+}
+
+func (h *Handler) schema(ctx context.Context, resource string, params url.Values) (any, error) {
+	if resource == "databases" {
+		return h.databases(ctx)
+	}
+
+	database, err := h.schemaDatabase(ctx, params.Get("database"))
+	if err != nil {
+		return nil, err
+	}
+	schema, table := params.Get("schema"), params.Get("table")
+	key := strings.Join([]string{resource, database, schema, table}, "/")
+
+	switch resource {
+	case "schemas":
+		return h.cached(key, func() (any, error) {
+			query, args := h.queries.Schemas(database)
+			return h.queryNames(ctx, query, args...)
+		})
+	case "tables":
+		return h.cached(key, func() (any, error) {
+			query, args := h.queries.Tables(database, schema)
+			return h.queryNames(ctx, query, args...)
+		})
+	case "columns":
+		if table == "" {
+			return nil, fmt.Errorf("%w: table", errMissingParameter)
+		}
+		return h.cached(key, func() (any, error) {
+			query, args := h.queries.Columns(database, schema, table)
+			return h.queryColumns(ctx, query, args...)
+		})
+	case "indexes":
+		if table == "" {
+			return nil, fmt.Errorf("%w: table", errMissingParameter)
+		}
+		return h.cached(key, func() (any, error) {
+			query, args := h.queries.Indexes(database, schema, table)
+			return h.queryIndexes(ctx, query, args...)
+		})
+	default:
+		return nil, fmt.Errorf("resource %q is %w", resource, errNotSupported)
+	}
+}
+
+func (h *Handler) databases(ctx context.Context) ([]string, error) {
+	// a data source configured with a database only gives access to that database
+	if h.database != "" {
+		return []string{h.database}, nil
+	}
+	databases, err := h.cached("databases", func() (any, error) {
+		return h.queryNames(ctx, h.queries.Databases())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return databases.([]string), nil
+}
+
+// schemaDatabase returns the database to introspect, the default database of the data source when none is requested.
+// The requested database must be one of the databases resource, the dialect queries can't introspect the others.
+func (h *Handler) schemaDatabase(ctx context.Context, requested string) (string, error) {
+	if requested == "" || requested == h.database {
+		return h.database, nil
+	}
+	databases, err := h.databases(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(databases, requested) {
+		return "", fmt.Errorf("%w: %s", errDatabaseNotAllowed, requested)
+	}
+	return requested, nil
+}
+
+func (h *Handler) cached(key string, load func() (any, error)) (any, error) {
+	if value, ok := h.cache.get(key, time.Now()); ok {
+		return value, nil
+	}
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	h.cache.set(key, value, time.Now())
+	return value, nil
+}
+
+func (h *Handler) queryNames(ctx context.Context, query string, args ...any) ([]string, error) {
+	if query == "" {
+		return nil, errNotSupported
+	}
+	rows, err := h.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func (h *Handler) queryColumns(ctx context.Context, query string, args ...any) ([]Column, error) {
+	if query == "" {
+		return nil, errNotSupported
+	}
+	rows, err := h.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns := []Column{}
+	for rows.Next() {
+		var c Column
+		var nullable string
+		if err := rows.Scan(&c.Name, &c.Type, &nullable); err != nil {
+			return nil, err
+		}
+		c.Nullable = strings.EqualFold(nullable, "YES")
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+func (h *Handler) queryIndexes(ctx context.Context, query string, args ...any) ([]Index, error) {
+	if query == "" {
+		return nil, errNotSupported
+	}
+	rows, err := h.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	indexes := []Index{}
+	for rows.Next() {
+		var name, column string
+		var unique, primary bool
+		if err := rows.Scan(&name, &column, &unique, &primary); err != nil {
+			return nil, err
+		}
+		// the rows are ordered by index, a new name starts a new index
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			indexes = append(indexes, Index{Name: name, Unique: unique, Primary: primary})
+		}
+		last := &indexes[len(indexes)-1]
+		last.Columns = append(last.Columns, column)
+	}
+	return indexes, rows.Err()
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+type cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func (c *cache) get(key string, now time.Time) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || now.After(entry.expires) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *cache) set(key string, value any, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]cacheEntry{}
+	}
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(cacheTTL)}
+}
+
+func sendResponse(sender backend.CallResourceResponseSender, status int, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  status,
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+		Body:    data,
+	})
+}
@@ -0,0 +1,128 @@
+package sqlschema
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQueries can only introspect the database of the connection, the database of an in-memory SQLite connection is
+// main
+type fakeQueries struct{}
+
+func (fakeQueries) Databases() string                     { return "SELECT 'main'" }
+func (fakeQueries) Schemas(string) (string, []any)        { return "", nil }
+func (fakeQueries) Tables(string, string) (string, []any) { return "SELECT 'table'", nil }
+func (fakeQueries) Columns(_, _, _ string) (string, []any) {
+	return "SELECT 'column', 'TEXT', 'YES'", nil
+}
+func (fakeQueries) Indexes(_, _, _ string) (string, []any) {
+	return "SELECT 'index', 'column', true, false", nil
+}
+
+type callResourceResponseSenderFunc func(res *backend.CallResourceResponse) error
+
+func (fn callResourceResponseSenderFunc) Send(res *backend.CallResourceResponse) error {
+	return fn(res)
+}
+
+func callSchemaResource(t *testing.T, h *Handler, method, path string) (int, string) {
+	t.Helper()
+	var res *backend.CallResourceResponse
+	sender := callResourceResponseSenderFunc(func(r *backend.CallResourceResponse) error {
+		res = r
+		return nil
+	})
+	err := h.CallResource(context.Background(), &backend.CallResourceRequest{Method: method, Path: path, URL: path}, sender)
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	return res.Status, string(res.Body)
+}
+
+func TestSchemaResources(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	newHandler := func(database string) *Handler {
+		return NewHandler(fakeQueries{}, db, database, backend.NewLoggerWith("logger", "test"), func(err error) error { return err })
+	}
+
+	t.Run("databases returns the configured database", func(t *testing.T) {
+		status, body := callSchemaResource(t, newHandler("grafana"), http.MethodGet, "databases")
+		require.Equal(t, http.StatusOK, status)
+		var databases []string
+		require.NoError(t, json.Unmarshal([]byte(body), &databases))
+		assert.Equal(t, []string{"grafana"}, databases)
+	})
+
+	t.Run("another database than the configured one is rejected", func(t *testing.T) {
+		status, body := callSchemaResource(t, newHandler("grafana"), http.MethodGet, "tables?database=other")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, body, errDatabaseNotAllowed.Error())
+	})
+
+	t.Run("without a configured database only the databases the dialect can introspect are allowed", func(t *testing.T) {
+		h := newHandler("")
+		status, body := callSchemaResource(t, h, http.MethodGet, "databases")
+		require.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `["main"]`, body)
+
+		status, body = callSchemaResource(t, h, http.MethodGet, "tables?database=main")
+		require.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `["table"]`, body)
+
+		status, body = callSchemaResource(t, h, http.MethodGet, "tables?database=other")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, body, errDatabaseNotAllowed.Error())
+	})
+
+	t.Run("columns and indexes", func(t *testing.T) {
+		status, body := callSchemaResource(t, newHandler("grafana"), http.MethodGet, "columns?table=table")
+		require.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `[{"name":"column","type":"TEXT","nullable":true}]`, body)
+
+		status, body = callSchemaResource(t, newHandler("grafana"), http.MethodGet, "indexes?table=table")
+		require.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `[{"name":"index","columns":["column"],"unique":true,"primary":false}]`, body)
+	})
+
+	t.Run("columns requires a table", func(t *testing.T) {
+		status, body := callSchemaResource(t, newHandler("grafana"), http.MethodGet, "columns")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, body, "missing parameter: table")
+	})
+
+	t.Run("unsupported resources", func(t *testing.T) {
+		status, _ := callSchemaResource(t, newHandler("grafana"), http.MethodGet, "schemas")
+		assert.Equal(t, http.StatusNotFound, status)
+		status, _ = callSchemaResource(t, newHandler("grafana"), http.MethodGet, "views")
+		assert.Equal(t, http.StatusNotFound, status)
+		status, _ = callSchemaResource(t, nil, http.MethodGet, "databases")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("only GET is allowed", func(t *testing.T) {
+		status, _ := callSchemaResource(t, newHandler("grafana"), http.MethodPost, "databases")
+		assert.Equal(t, http.StatusMethodNotAllowed, status)
+	})
+
+	t.Run("cached values are returned without querying", func(t *testing.T) {
+		h := newHandler("grafana")
+		h.cache.set("tables/grafana//", []string{"a", "b"}, time.Now())
+		status, body := callSchemaResource(t, h, http.MethodGet, "tables")
+		require.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `["a","b"]`, body)
+
+		_, ok := h.cache.get("tables/grafana//", time.Now().Add(cacheTTL+time.Second))
+		assert.False(t, ok)
+	})
+}
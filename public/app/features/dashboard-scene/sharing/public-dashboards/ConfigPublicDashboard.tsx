@@ -15,6 +15,7 @@ import { ShareModal } from '../ShareModal';
 import { ConfirmModal } from './ConfirmModal';
 import { SharePublicDashboardTab } from './SharePublicDashboardTab';
 import { useUnsupportedDatasources } from './hooks';
+import { getVariableOptions } from './utils';
 
 interface Props extends SceneComponentProps<SharePublicDashboardTab> {
   publicDashboard?: PublicDashboard;
@@ -64,6 +65,7 @@ export function ConfigPublicDashboard({ model, publicDashboard, isGetLoading }:
       timeRange={timeRange.value}
       showSaveChangesAlert={hasWritePermissions && isDirty}
       hasTemplateVariables={hasTemplateVariables}
+      variables={getVariableOptions(dashboard)}
     />
   );
 }
@@ -1,5 +1,6 @@
 import { DataSourceWithBackend } from '@grafana/runtime';
 import {
+  MultiValueVariable,
   SceneGridItemLike,
   VizPanel,
   SceneQueryRunner,
@@ -7,6 +8,7 @@ import {
   SceneGridLayout,
   SceneGridRow,
 } from '@grafana/scenes';
+import { PublicDashboardVariable } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SharePublicDashboardUtils';
 import { supportedDatasources } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SupportedPubdashDatasources';
 import { getDatasourceSrv } from 'app/features/plugins/datasource_srv';
 
@@ -98,3 +100,18 @@ function panelDatasourceTypes(gridItem: SceneGridItemLike) {
 
   return Array.from(types);
 }
+
+/**
+ * Get the template variables of a dashboard with the values they can be set to.
+ */
+export const getVariableOptions = (dashboard: DashboardScene): PublicDashboardVariable[] => {
+  const variables = dashboard.state.$variables?.state.variables ?? [];
+
+  return variables
+    .filter((variable): variable is MultiValueVariable => variable instanceof MultiValueVariable)
+    .map((variable) => ({
+      name: variable.state.name,
+      values: variable.state.options.map((option) => String(option.value)),
+    }))
+    .filter((variable) => variable.values.length > 0);
+};
@@ -40,13 +40,16 @@ import { UnsupportedTemplateVariablesAlert } from '../ModalAlerts/UnsupportedTem
 import {
   dashboardHasTemplateVariables,
   generatePublicDashboardUrl,
+  getVariableOptions,
   PublicDashboard,
+  PublicDashboardVariable,
 } from '../SharePublicDashboardUtils';
 
 import { Configuration } from './Configuration';
 import { EmailSharingConfiguration } from './EmailSharingConfiguration';
 import { SettingsBar } from './SettingsBar';
 import { SettingsSummary } from './SettingsSummary';
+import { ViewerAccessConfiguration, ViewerAccessSettings } from './ViewerAccessConfiguration';
 
 const selectors = e2eSelectors.pages.ShareDashboardModal.PublicDashboard;
 
@@ -61,6 +64,7 @@ interface Props {
   showSaveChangesAlert?: boolean;
   publicDashboard?: PublicDashboard;
   hasTemplateVariables?: boolean;
+  variables?: PublicDashboardVariable[];
   timeRange: TimeRange;
   onRevoke: () => void;
   dashboard: DashboardModel | DashboardScene;
@@ -70,6 +74,7 @@ export function ConfigPublicDashboardBase({
   onRevoke,
   timeRange,
   hasTemplateVariables = false,
+  variables = [],
   showSaveChangesAlert = false,
   unsupportedDatasources = [],
   publicDashboard,
@@ -106,6 +111,16 @@ export function ConfigPublicDashboardBase({
     });
   };
 
+  const onViewerAccessChange = (settings: ViewerAccessSettings) => {
+    update({
+      dashboard: dashboard,
+      payload: {
+        ...publicDashboard!,
+        ...settings,
+      },
+    });
+  };
+
   const onChange = async (name: keyof ConfigPublicDashboardForm, value: boolean) => {
     setValue(name, value);
     await handleSubmit((data) => onPublicDashboardUpdate(data))();
@@ -119,7 +134,7 @@ export function ConfigPublicDashboardBase({
     <div className={styles.configContainer}>
       {showSaveChangesAlert && <SaveDashboardChangesAlert />}
       {!hasWritePermissions && <NoUpsertPermissionsAlert mode="edit" />}
-      {hasTemplateVariables && <UnsupportedTemplateVariablesAlert />}
+      {hasTemplateVariables && !publicDashboard?.variables?.length && <UnsupportedTemplateVariablesAlert />}
       {unsupportedDatasources.length > 0 && (
         <UnsupportedDataSourcesAlert unsupportedDataSources={unsupportedDatasources.join(', ')} />
       )}
@@ -187,6 +202,12 @@ export function ConfigPublicDashboardBase({
           data-testid={selectors.SettingsDropdown}
         >
           <Configuration disabled={disableInputs} onChange={onChange} register={register} timeRange={timeRange} />
+          <ViewerAccessConfiguration
+            disabled={disableInputs}
+            publicDashboard={publicDashboard}
+            variables={variables}
+            onChange={onViewerAccessChange}
+          />
         </SettingsBar>
       </Field>
 
@@ -244,6 +265,7 @@ export function ConfigPublicDashboard({ publicDashboard, unsupportedDatasources
           timeRange={timeRange}
           showSaveChangesAlert={hasWritePermissions && dashboard.hasUnsavedChanges()}
           hasTemplateVariables={hasTemplateVariables}
+          variables={getVariableOptions(dashboard.getVariables())}
           onRevoke={() => {
             DashboardInteractions.revokePublicDashboardClicked();
             showModal(DeletePublicDashboardModal, {
@@ -0,0 +1,95 @@
+import React from 'react';
+
+import { dateTime } from '@grafana/data/src';
+import { Button, DateTimePicker, FieldSet, Label, MultiSelect, VerticalGroup } from '@grafana/ui/src';
+import { Layout } from '@grafana/ui/src/components/Layout/Layout';
+import { Trans, t } from 'app/core/internationalization';
+
+import { PublicDashboard, PublicDashboardVariable } from '../SharePublicDashboardUtils';
+
+// the API removes the expiration when it receives the zero time
+const NEVER_EXPIRES = '0001-01-01T00:00:00Z';
+
+export type ViewerAccessSettings = Pick<PublicDashboard, 'expiresAt' | 'variables'>;
+
+export const ViewerAccessConfiguration = ({
+  disabled,
+  publicDashboard,
+  variables,
+  onChange,
+}: {
+  disabled: boolean;
+  publicDashboard?: PublicDashboard;
+  variables: PublicDashboardVariable[];
+  onChange: (settings: ViewerAccessSettings) => void;
+}) => {
+  const expiresAt = publicDashboard?.expiresAt;
+  const allowedVariables = publicDashboard?.variables ?? [];
+
+  const onVariableChange = (name: string, values: string[]) => {
+    const others = allowedVariables.filter((variable) => variable.name !== name);
+    // an empty list removes the allow-list
+    onChange({ variables: values.length > 0 ? [...others, { name, values }] : others });
+  };
+
+  return (
+    <FieldSet disabled={disabled}>
+      <VerticalGroup spacing="md">
+        <Layout orientation={1} spacing="xs" justify="space-between">
+          <Label
+            description={t(
+              'public-dashboard.settings-configuration.expiration-label-desc',
+              'The public dashboard cannot be viewed after this time'
+            )}
+          >
+            <Trans i18nKey="public-dashboard.settings-configuration.expiration-label">Expiration</Trans>
+          </Label>
+          <Layout orientation={0} spacing="sm">
+            <DateTimePicker
+              date={expiresAt ? dateTime(expiresAt) : undefined}
+              minDate={new Date()}
+              onChange={(date) => onChange({ expiresAt: date.toISOString() })}
+            />
+            <Button
+              variant="secondary"
+              fill="outline"
+              disabled={disabled || !expiresAt}
+              onClick={() => onChange({ expiresAt: NEVER_EXPIRES })}
+            >
+              <Trans i18nKey="public-dashboard.settings-configuration.never-expire-button">Never expire</Trans>
+            </Button>
+          </Layout>
+        </Layout>
+        {variables.length > 0 && (
+          <Layout orientation={1} spacing="xs">
+            <Label
+              description={t(
+                'public-dashboard.settings-configuration.variables-label-desc',
+                'Values viewers can select for each template variable. Variables without values cannot be changed'
+              )}
+            >
+              <Trans i18nKey="public-dashboard.settings-configuration.variables-label">Template variables</Trans>
+            </Label>
+            {variables.map((variable) => (
+              <Layout key={variable.name} orientation={0} spacing="sm" justify="space-between">
+                <Label>{variable.name}</Label>
+                <MultiSelect
+                  aria-label={variable.name}
+                  width={40}
+                  disabled={disabled}
+                  placeholder={t(
+                    'public-dashboard.settings-configuration.variable-values-placeholder',
+                    'Cannot be changed'
+                  )}
+                  options={variable.values.map((value) => ({ label: value, value }))}
+                  value={allowedVariables.find((allowed) => allowed.name === variable.name)?.values ?? []}
+                  onChange={(selected) => onVariableChange(variable.name, selected.map((option) => option.value!))}
+                />
+              </Layout>
+            ))}
+          </Layout>
+        )}
+      </VerticalGroup>
+    </FieldSet>
+  );
+};
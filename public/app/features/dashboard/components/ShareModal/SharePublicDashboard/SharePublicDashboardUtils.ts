@@ -26,6 +26,14 @@ export interface PublicDashboard extends PublicDashboardSettings {
   timeSettings?: object;
   share: PublicDashboardShareType;
   recipients?: Array<{ uid: string; recipient: string }>;
+  expiresAt?: string;
+  variables?: PublicDashboardVariable[];
+}
+
+/** A template variable viewers of the public dashboard may change, and the values they may pick */
+export interface PublicDashboardVariable {
+  name: string;
+  values: string[];
 }
 
 export interface SessionDashboard {
@@ -47,6 +55,18 @@ export const dashboardHasTemplateVariables = (variables: TypedVariableModel[]):
   return variables.length > 0;
 };
 
+/**
+ * Get the template variables of a dashboard with the values they can be set to.
+ */
+export const getVariableOptions = (variables: TypedVariableModel[]): PublicDashboardVariable[] => {
+  return variables
+    .filter((variable) => 'options' in variable && variable.options.length > 0)
+    .map((variable) => ({
+      name: variable.name,
+      values: 'options' in variable ? variable.options.map((option) => String(option.value)) : [],
+    }));
+};
+
 export const publicDashboardPersisted = (publicDashboard?: PublicDashboard): boolean => {
   return publicDashboard?.uid !== '' && publicDashboard?.uid !== undefined;
 };
@@ -3,7 +3,7 @@ import _, { isFunction } from 'lodash'; // eslint-disable-line lodash/import-sco
 import moment from 'moment'; // eslint-disable-line no-restricted-imports
 
 import { AppEvents, dateMath, UrlQueryValue } from '@grafana/data';
-import { getBackendSrv, locationService } from '@grafana/runtime';
+import { config, getBackendSrv, locationService } from '@grafana/runtime';
 import { backendSrv } from 'app/core/services/backend_srv';
 import impressionSrv from 'app/core/services/impression_srv';
 import kbn from 'app/core/utils/kbn';
@@ -52,6 +52,8 @@ export class DashboardLoaderSrv {
       promise = backendSrv
         .getPublicDashboardByUid(uid)
         .then((result) => {
+          // the query handler sends the values of these variables along with the queries
+          config.publicDashboardVariables = result.meta.publicDashboardVariables;
           return result;
         })
         .catch((e) => {
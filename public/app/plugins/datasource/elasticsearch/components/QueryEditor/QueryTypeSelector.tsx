@@ -1,6 +1,7 @@
 import React from 'react';
 
 import { SelectableValue } from '@grafana/data';
+import { config } from '@grafana/runtime';
 import { RadioButtonGroup } from '@grafana/ui';
 
 import { useDispatch } from '../../hooks/useStatelessReducer';
@@ -9,6 +10,7 @@ import { MetricAggregation, QueryType } from '../../types';
 import { useQuery } from './ElasticsearchQueryContext';
 import { changeMetricType } from './MetricAggregationsEditor/state/actions';
 import { metricAggregationConfig } from './MetricAggregationsEditor/utils';
+import { changeQueryType } from './state';
 
 const OPTIONS: Array<SelectableValue<QueryType>> = [
   { value: 'metrics', label: 'Metrics' },
@@ -17,6 +19,9 @@ const OPTIONS: Array<SelectableValue<QueryType>> = [
   { value: 'raw_document', label: 'Raw Document' },
 ];
 
+// ES|QL queries are only supported when running queries through the backend
+const ESQL_OPTION: SelectableValue<QueryType> = { value: 'esql', label: 'ES|QL' };
+
 function queryTypeToMetricType(type: QueryType): MetricAggregation['type'] {
   switch (type) {
     case 'logs':
@@ -42,11 +47,16 @@ export const QueryTypeSelector = () => {
     return null;
   }
 
-  const queryType = metricAggregationConfig[firstMetric.type].impliedQueryType;
+  const queryType = query.queryType === 'esql' ? 'esql' : metricAggregationConfig[firstMetric.type].impliedQueryType;
+  const options = config.featureToggles.enableElasticsearchBackendQuerying ? [...OPTIONS, ESQL_OPTION] : OPTIONS;
 
   const onChange = (newQueryType: QueryType) => {
+    if (newQueryType === 'esql') {
+      dispatch(changeQueryType('esql'));
+      return;
+    }
     dispatch(changeMetricType({ id: firstMetric.id, type: queryTypeToMetricType(newQueryType) }));
   };
 
-  return <RadioButtonGroup<QueryType> fullWidth={false} options={OPTIONS} value={queryType} onChange={onChange} />;
+  return <RadioButtonGroup<QueryType> fullWidth={false} options={options} value={queryType} onChange={onChange} />;
 };
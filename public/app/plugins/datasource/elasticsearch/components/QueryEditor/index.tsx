@@ -87,12 +87,49 @@ export const ElasticSearchQueryField = ({ value, onChange }: { value?: string; o
   );
 };
 
+export const EsqlQueryField = ({ value, onChange }: { value?: string; onChange: (v: string) => void }) => {
+  const styles = useStyles2(getStyles);
+
+  return (
+    <div className={styles.queryItem}>
+      <QueryField
+        query={value}
+        onChange={onChange}
+        placeholder="FROM logs-* | WHERE $__timeFilter | STATS count() BY BUCKET(@timestamp, $__interval)"
+        portalOrigin="elasticsearch"
+      />
+    </div>
+  );
+};
+
 const QueryEditorForm = ({ value }: Props) => {
   const dispatch = useDispatch();
   const nextId = useNextId();
   const inputId = useId();
   const styles = useStyles2(getStyles);
 
+  if (value.queryType === 'esql') {
+    return (
+      <>
+        <div className={styles.root}>
+          <InlineLabel width={17}>Query type</InlineLabel>
+          <div className={styles.queryItem}>
+            <QueryTypeSelector />
+          </div>
+        </div>
+        <div className={styles.root}>
+          <InlineLabel
+            width={17}
+            tooltip="Supports the $__timeFilter, $__timeFilter(field), $__timeFrom, $__timeTo, $__interval and $__interval_ms macros."
+          >
+            ES|QL Query
+          </InlineLabel>
+          <EsqlQueryField onChange={(query) => dispatch(changeQuery(query))} value={value?.query} />
+        </div>
+      </>
+    );
+  }
+
   const isTimeSeries = isTimeSeriesQuery(value);
 
   const showBucketAggregationsEditor = value.metrics?.every(
@@ -1,7 +1,16 @@
 import { ElasticsearchQuery } from '../../types';
 import { reducerTester } from '../reducerTester';
 
-import { aliasPatternReducer, changeAliasPattern, changeQuery, initQuery, queryReducer } from './state';
+import { changeMetricType } from './MetricAggregationsEditor/state/actions';
+import {
+  aliasPatternReducer,
+  changeAliasPattern,
+  changeQuery,
+  changeQueryType,
+  initQuery,
+  queryReducer,
+  queryTypeReducer,
+} from './state';
 
 describe('Query Reducer', () => {
   describe('On Init', () => {
@@ -34,6 +43,13 @@ describe('Query Reducer', () => {
       .thenStateShouldEqual(expectedQuery);
   });
 
+  it('Should reset `query` when the query type changes', () => {
+    reducerTester<ElasticsearchQuery['query']>()
+      .givenReducer(queryReducer, 'Some lucene query')
+      .whenActionIsDispatched(changeQueryType('esql'))
+      .thenStateShouldEqual('');
+  });
+
   it('Should not change state with other action types', () => {
     const initialState: ElasticsearchQuery['query'] = 'Some lucene query';
 
@@ -63,3 +79,19 @@ describe('Alias Pattern Reducer', () => {
       .thenStateShouldEqual(initialState);
   });
 });
+
+describe('Query Type Reducer', () => {
+  it('Should correctly set `queryType`', () => {
+    reducerTester<ElasticsearchQuery['queryType']>()
+      .givenReducer(queryTypeReducer, undefined)
+      .whenActionIsDispatched(changeQueryType('esql'))
+      .thenStateShouldEqual('esql');
+  });
+
+  it('Should unset `queryType` when the metric type changes', () => {
+    reducerTester<ElasticsearchQuery['queryType']>()
+      .givenReducer(queryTypeReducer, 'esql')
+      .whenActionIsDispatched(changeMetricType({ id: '1', type: 'logs' }))
+      .thenStateShouldEqual(undefined);
+  });
+});
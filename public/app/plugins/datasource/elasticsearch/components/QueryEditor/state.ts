@@ -2,6 +2,8 @@ import { Action, createAction } from '@reduxjs/toolkit';
 
 import { ElasticsearchQuery } from '../../types';
 
+import { changeMetricType } from './MetricAggregationsEditor/state/actions';
+
 /**
  * When the `initQuery` Action is dispatched, the query gets populated with default values where values are not present.
  * This means it won't override any existing value in place, but just ensure the query is in a "runnable" state.
@@ -12,6 +14,8 @@ export const changeQuery = createAction<ElasticsearchQuery['query']>('change_que
 
 export const changeAliasPattern = createAction<ElasticsearchQuery['alias']>('change_alias_pattern');
 
+export const changeQueryType = createAction<ElasticsearchQuery['queryType']>('change_query_type');
+
 export const queryReducer = (prevQuery: ElasticsearchQuery['query'], action: Action) => {
   if (changeQuery.match(action)) {
     return action.payload;
@@ -21,9 +25,29 @@ export const queryReducer = (prevQuery: ElasticsearchQuery['query'], action: Act
     return prevQuery || '';
   }
 
+  // Lucene and ES|QL queries are not interchangeable
+  if (changeQueryType.match(action)) {
+    return '';
+  }
+
   return prevQuery;
 };
 
+/**
+ * The query type is only set for ES|QL queries, the type of the other queries is implied by their first metric.
+ */
+export const queryTypeReducer = (prevQueryType: ElasticsearchQuery['queryType'], action: Action) => {
+  if (changeQueryType.match(action)) {
+    return action.payload;
+  }
+
+  if (changeMetricType.match(action)) {
+    return undefined;
+  }
+
+  return prevQueryType;
+};
+
 export const aliasPatternReducer = (prevAliasPattern: ElasticsearchQuery['alias'], action: Action) => {
   if (changeAliasPattern.match(action)) {
     return action.payload;
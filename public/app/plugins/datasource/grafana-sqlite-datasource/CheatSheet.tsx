@@ -0,0 +1,76 @@
+import { css } from '@emotion/css';
+import React from 'react';
+
+import { GrafanaTheme2 } from '@grafana/data';
+import { useStyles2 } from '@grafana/ui';
+
+export function CheatSheet() {
+  const styles = useStyles2(getStyles);
+
+  return (
+    <div>
+      <h2>SQLite cheat sheet</h2>
+      Time series:
+      <ul className={styles.ulPadding}>
+        <li>
+          return column named <i>time</i> (in seconds since the epoch or a DATETIME column)
+        </li>
+        <li>return column(s) with numeric datatype as values</li>
+      </ul>
+      Optional:
+      <ul className={styles.ulPadding}>
+        <li>
+          return column named <i>metric</i> to represent the series name.
+        </li>
+        <li>If multiple value columns are returned the metric column is used as prefix.</li>
+        <li>If no column named metric is found the column name of the value column is used as series name</li>
+      </ul>
+      <p>Result sets of time series queries need to be sorted by time.</p>
+      Table:
+      <ul className={styles.ulPadding}>
+        <li>return any set of columns</li>
+      </ul>
+      Macros:
+      <ul className={styles.ulPadding}>
+        <li>$__time(column) -&gt; CAST(strftime(&apos;%s&apos;, column) AS INTEGER) AS &quot;time&quot;</li>
+        <li>
+          $__timeFilter(column) -&gt; datetime(column) BETWEEN datetime(1492750877, &apos;unixepoch&apos;) AND
+          datetime(1492750877, &apos;unixepoch&apos;)
+        </li>
+        <li>$__unixEpochFilter(column) -&gt; column &gt;= 1492750877 AND column &lt;= 1492750877</li>
+        <li>
+          $__timeGroup(column,&apos;5m&apos;) -&gt; CAST(strftime(&apos;%s&apos;, column) AS INTEGER) / 300 * 300
+        </li>
+        <li>$__unixEpochGroup(column,&apos;5m&apos;) -&gt; column / 300 * 300</li>
+      </ul>
+      <p>
+        The time macros work on columns holding ISO 8601 text, for example <i>2017-04-21 05:01:17</i>. The unix epoch
+        macros work on columns holding seconds since the epoch.
+      </p>
+      Example of group by and order by with $__timeGroup:
+      <pre>
+        SELECT
+        <br />
+        &nbsp;&nbsp;$__timeGroupAlias(timestamp_col, &apos;1h&apos;),
+        <br />
+        &nbsp;&nbsp;sum(value_double) as value
+        <br />
+        FROM yourtable
+        <br />
+        GROUP BY 1<br />
+        ORDER BY 1
+        <br />
+      </pre>
+      The connection is read-only, statements changing the database file and ATTACH are rejected.
+    </div>
+  );
+}
+
+function getStyles(theme: GrafanaTheme2) {
+  return {
+    ulPadding: css({
+      margin: theme.spacing(1, 0),
+      paddingLeft: theme.spacing(5),
+    }),
+  };
+}
@@ -0,0 +1,49 @@
+import React from 'react';
+
+import { DataSourcePluginOptionsEditorProps, onUpdateDatasourceJsonDataOption } from '@grafana/data';
+import { ConfigSection, DataSourceDescription } from '@grafana/experimental';
+import { ConnectionLimits, Divider } from '@grafana/sql';
+import { Alert, Field, Input } from '@grafana/ui';
+
+import { SQLiteOptions } from '../types';
+
+export const ConfigurationEditor = (props: DataSourcePluginOptionsEditorProps<SQLiteOptions>) => {
+  const { options } = props;
+
+  return (
+    <>
+      <DataSourceDescription
+        dataSourceName="SQLite"
+        docsLink="https://grafana.com/docs/grafana/latest/datasources/sqlite/"
+        hasRequiredFields={true}
+      />
+
+      <Alert title="Read-only access" severity="info">
+        The database file is opened read-only, statements changing the database and ATTACH are rejected. The file must
+        be listed, or be inside a directory listed, in <code>sqlite_allowed_paths</code> of the{' '}
+        <code>[sql_datasources]</code> section of the Grafana configuration.
+      </Alert>
+
+      <Divider />
+
+      <ConfigSection title="Connection">
+        <Field label="Database file path" description="Absolute path to the SQLite database file" required>
+          <Input
+            width={60}
+            name="path"
+            type="text"
+            value={options.jsonData.path || ''}
+            placeholder="/var/lib/grafana/sqlite/metrics.db"
+            onChange={onUpdateDatasourceJsonDataOption(props, 'path')}
+          />
+        </Field>
+      </ConfigSection>
+
+      <Divider />
+
+      <ConfigSection title="Additional settings">
+        <ConnectionLimits options={options} onOptionsChange={props.onOptionsChange} />
+      </ConfigSection>
+    </>
+  );
+};
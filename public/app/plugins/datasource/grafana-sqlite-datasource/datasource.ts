@@ -0,0 +1,73 @@
+import { DataSourceInstanceSettings, TimeRange } from '@grafana/data';
+import { LanguageDefinition } from '@grafana/experimental';
+import { DB, formatSQL, SQLQuery, SqlDatasource, SQLSelectableValue } from '@grafana/sql';
+
+import { getFieldType, quoteIdentifierIfNecessary, quoteLiteral, toRawSql } from './sqlUtil';
+import { SQLiteColumn, SQLiteOptions } from './types';
+
+export class SQLiteDatasource extends SqlDatasource {
+  sqlLanguageDefinition: LanguageDefinition | undefined;
+
+  constructor(instanceSettings: DataSourceInstanceSettings<SQLiteOptions>) {
+    super(instanceSettings);
+  }
+
+  getQueryModel() {
+    return { quoteLiteral };
+  }
+
+  getSqlLanguageDefinition(): LanguageDefinition {
+    if (this.sqlLanguageDefinition !== undefined) {
+      return this.sqlLanguageDefinition;
+    }
+
+    this.sqlLanguageDefinition = {
+      id: 'sql',
+      formatter: formatSQL,
+    };
+
+    return this.sqlLanguageDefinition;
+  }
+
+  // the schema is read from the schema introspection resources of the backend
+  async fetchTables(): Promise<string[]> {
+    const tables = await this.getResource<string[]>('tables');
+    return tables.map(quoteIdentifierIfNecessary);
+  }
+
+  async fetchFields(query: Partial<SQLQuery>): Promise<SQLSelectableValue[]> {
+    if (!query.table) {
+      return [];
+    }
+    const table = query.table.replace(/^"(.*)"$/, '$1').replace(/""/g, '"');
+    const columns = await this.getResource<SQLiteColumn[]>('columns', { table });
+    return columns.map((c) => ({
+      name: c.name,
+      text: c.name,
+      value: quoteIdentifierIfNecessary(c.name),
+      type: c.type,
+      label: c.name,
+      raqbFieldType: getFieldType(c.type),
+    }));
+  }
+
+  getDB(): DB {
+    if (this.db !== undefined) {
+      return this.db;
+    }
+
+    return {
+      // SQLite has a single database per file, there is no dataset to pick
+      init: () => Promise.resolve(true),
+      datasets: () => Promise.resolve([]),
+      tables: () => this.fetchTables(),
+      fields: (query: SQLQuery) => this.fetchFields(query),
+      validateQuery: (query: SQLQuery, _range?: TimeRange) =>
+        Promise.resolve({ query, error: '', isError: false, isValid: true }),
+      dsID: () => this.id,
+      toRawSql,
+      functions: () => ['AVG', 'COUNT', 'MAX', 'MIN', 'SUM', 'TOTAL'],
+      getEditorLanguageDefinition: () => this.getSqlLanguageDefinition(),
+    };
+  }
+}
@@ -0,0 +1,74 @@
+import { isEmpty } from 'lodash';
+
+import { createSelectClause, haveColumns, RAQBFieldTypes, SQLQuery } from '@grafana/sql';
+
+// getFieldType maps the declared type of a column to a query builder type with the type affinity rules of SQLite
+export function getFieldType(type: string): RAQBFieldTypes {
+  const t = type.toUpperCase();
+  if (t.includes('BOOL')) {
+    return 'boolean';
+  }
+  if (t.includes('DATETIME') || t.includes('TIMESTAMP')) {
+    return 'datetime';
+  }
+  if (t.includes('DATE')) {
+    return 'date';
+  }
+  if (
+    t.includes('INT') ||
+    t.includes('REAL') ||
+    t.includes('FLOA') ||
+    t.includes('DOUB') ||
+    t.includes('NUM') ||
+    t.includes('DEC')
+  ) {
+    return 'number';
+  }
+  return 'text';
+}
+
+export function quoteLiteral(value: string) {
+  return "'" + value.replace(/'/g, "''") + "'";
+}
+
+export function quoteIdentifierIfNecessary(value: string) {
+  return /^[A-Za-z_][A-Za-z0-9_]*$/.test(value) ? value : '"' + value.replace(/"/g, '""') + '"';
+}
+
+export function toRawSql({ sql, table }: SQLQuery): string {
+  let rawQuery = '';
+
+  // Return early with empty string if there is no sql column
+  if (!sql || !haveColumns(sql.columns)) {
+    return rawQuery;
+  }
+
+  rawQuery += createSelectClause(sql.columns);
+
+  if (table) {
+    rawQuery += `FROM ${table} `;
+  }
+
+  if (sql.whereString) {
+    rawQuery += `WHERE ${sql.whereString} `;
+  }
+
+  if (sql.groupBy?.[0]?.property.name) {
+    const groupBy = sql.groupBy.map((g) => g.property.name).filter((g) => !isEmpty(g));
+    rawQuery += `GROUP BY ${groupBy.join(', ')} `;
+  }
+
+  if (sql.orderBy?.property.name) {
+    rawQuery += `ORDER BY ${sql.orderBy.property.name} `;
+  }
+
+  if (sql.orderBy?.property.name && sql.orderByDirection) {
+    rawQuery += `${sql.orderByDirection} `;
+  }
+
+  // Altough LIMIT 0 doesn't make sense, it is still possible to have LIMIT 0
+  if (sql.limit !== undefined && sql.limit >= 0) {
+    rawQuery += `LIMIT ${sql.limit} `;
+  }
+  return rawQuery;
+}
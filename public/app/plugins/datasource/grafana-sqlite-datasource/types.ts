@@ -0,0 +1,13 @@
+import { SQLOptions, SQLQuery } from '@grafana/sql';
+
+export interface SQLiteOptions extends SQLOptions {
+  path?: string;
+}
+
+export interface SQLiteQuery extends SQLQuery {}
+
+export interface SQLiteColumn {
+  name: string;
+  type: string;
+  nullable: boolean;
+}
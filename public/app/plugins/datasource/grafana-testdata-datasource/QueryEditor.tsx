@@ -14,6 +14,7 @@ import { GrafanaLiveEditor } from './components/GrafanaLiveEditor';
 import { NodeGraphEditor } from './components/NodeGraphEditor';
 import { PredictablePulseEditor } from './components/PredictablePulseEditor';
 import { RawFrameEditor } from './components/RawFrameEditor';
+import { ReplayEditor } from './components/ReplayEditor';
 import { SimulationQueryEditor } from './components/SimulationQueryEditor';
 import { USAQueryEditor, usaQueryModes } from './components/USAQueryEditor';
 import { defaultCSVWaveQuery, defaultPulseQuery, defaultQuery } from './constants';
@@ -37,7 +38,7 @@ export interface EditorProps {
 
 export type Props = QueryEditorProps<TestDataDataSource, TestDataDataQuery>;
 
-export const QueryEditor = ({ query, datasource, onChange, onRunQuery }: Props) => {
+export const QueryEditor = ({ query, datasource, onChange, onRunQuery, data }: Props) => {
   query = { ...defaultQuery, ...query };
 
   const { loading, value: scenarioList } = useAsync(async () => {
@@ -285,6 +286,9 @@ export const QueryEditor = ({ query, datasource, onChange, onRunQuery }: Props)
       {scenarioId === TestDataQueryType.RawFrame && (
         <RawFrameEditor onChange={onUpdate} query={query} ds={datasource} />
       )}
+      {scenarioId === TestDataQueryType.Replay && (
+        <ReplayEditor onChange={onUpdate} query={query} ds={datasource} data={data} />
+      )}
       {scenarioId === TestDataQueryType.CSVFile && <CSVFileEditor onChange={onUpdate} query={query} ds={datasource} />}
       {scenarioId === TestDataQueryType.CSVContent && (
         <CSVContentEditor onChange={onUpdate} query={query} ds={datasource} />
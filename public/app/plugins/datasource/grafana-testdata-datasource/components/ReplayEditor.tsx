@@ -0,0 +1,80 @@
+import React, { useState } from 'react';
+import { useAsyncFn, useEffectOnce } from 'react-use';
+
+import { PanelData, SelectableValue } from '@grafana/data';
+import { Button, InlineField, InlineFieldRow, Input, Select } from '@grafana/ui';
+
+import { EditorProps } from '../QueryEditor';
+
+interface Props extends EditorProps {
+  data?: PanelData;
+}
+
+export const ReplayEditor = ({ onChange, query, ds, data }: Props) => {
+  const [name, setName] = useState('');
+
+  const [recordings, loadRecordings] = useAsyncFn(async () => {
+    const names = await ds.getRecordings();
+    return names.map((value) => ({ label: value, value }));
+  }, [ds]);
+  useEffectOnce(() => {
+    loadRecordings();
+  });
+
+  const [recordState, record] = useAsyncFn(async () => {
+    if (!data) {
+      return;
+    }
+    // Record everything in the panel except what this query is replaying
+    const frames = data.series.filter((frame) => frame.refId !== query.refId);
+    await ds.saveRecording(name, frames, data.timeRange);
+    await loadRecordings();
+    onChange({ ...query, stringInput: name });
+  }, [ds, data, name, query]);
+
+  const onChangeRecording = ({ value }: SelectableValue<string>) => {
+    onChange({ ...query, stringInput: value });
+  };
+
+  return (
+    <>
+      <InlineFieldRow>
+        <InlineField label="Recording" labelWidth={14}>
+          <Select
+            width={32}
+            isLoading={recordings.loading}
+            onChange={onChangeRecording}
+            placeholder="Select recording"
+            options={recordings.value ?? []}
+            value={query.stringInput}
+            allowCustomValue
+          />
+        </InlineField>
+      </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField
+          label="Record panel"
+          labelWidth={14}
+          tooltip="Store the current results of the other queries in this panel under a new name"
+          invalid={Boolean(recordState.error)}
+          error={recordState.error?.message}
+        >
+          <Input
+            width={32}
+            placeholder="Recording name"
+            value={name}
+            onChange={(e) => setName(e.currentTarget.value)}
+          />
+        </InlineField>
+        <Button
+          variant="secondary"
+          icon="save"
+          disabled={!name || !data?.series.length || recordState.loading}
+          onClick={() => record()}
+        >
+          Record
+        </Button>
+      </InlineFieldRow>
+    </>
+  );
+};
@@ -5,6 +5,7 @@ import {
   AnnotationEvent,
   ArrayDataFrame,
   DataFrame,
+  dataFrameToJSON,
   DataQueryRequest,
   DataQueryResponse,
   DataSourceInstanceSettings,
@@ -220,6 +221,18 @@ export class TestDataDataSource extends DataSourceWithBackend<TestDataDataQuery>
     return this.scenariosCache;
   }
 
+  getRecordings(): Promise<string[]> {
+    return this.getResource('recordings');
+  }
+
+  saveRecording(name: string, frames: DataFrame[], range: TimeRange) {
+    return this.postResource(`recordings/${encodeURIComponent(name)}`, {
+      from: range.from.toISOString(),
+      to: range.to.toISOString(),
+      frames: frames.map((frame) => dataFrameToJSON(frame)),
+    });
+  }
+
   variablesQuery(
     target: TestDataDataQuery,
     options: DataQueryRequest<TestDataDataQuery>
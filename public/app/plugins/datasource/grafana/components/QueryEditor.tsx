@@ -69,6 +69,31 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
       value: GrafanaQueryType.List,
       description: 'Show directory listings for public resources',
     },
+    {
+      label: 'Alert states',
+      value: GrafanaQueryType.AlertStates,
+      description: 'Current state of the alert instances',
+    },
+    {
+      label: 'Alert state history',
+      value: GrafanaQueryType.AlertStateHistory,
+      description: 'State transitions of the alert instances',
+    },
+    {
+      label: 'Rule evaluations',
+      value: GrafanaQueryType.RuleEvaluations,
+      description: 'Latest evaluation time, duration and health of the alert rules',
+    },
+    {
+      label: 'Data source health',
+      value: GrafanaQueryType.DataSourceHealth,
+      description: 'Latest background health check of the data sources',
+    },
+    {
+      label: 'Active users',
+      value: GrafanaQueryType.ActiveUsers,
+      description: 'Number of active users and sessions',
+    },
   ];
 
   constructor(props: Props) {
@@ -310,6 +335,47 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
     onRunQuery();
   };
 
+  onRuleUIDChange = (e: React.FormEvent<HTMLInputElement>) => {
+    const { onChange, query } = this.props;
+    onChange({ ...query, ruleUID: e.currentTarget.value || undefined });
+  };
+
+  onLimitChange = (e: React.FormEvent<HTMLInputElement>) => {
+    const { onChange, query } = this.props;
+    const limit = parseInt(e.currentTarget.value, 10);
+    onChange({ ...query, limit: isNaN(limit) ? undefined : limit });
+  };
+
+  renderAlertingQuery() {
+    const { query, onRunQuery } = this.props;
+
+    return (
+      <InlineFieldRow>
+        <InlineField label="Rule UID" labelWidth={labelWidth} tooltip="Only return the data of this alert rule">
+          <Input
+            width={30}
+            placeholder="All rules"
+            value={query.ruleUID ?? ''}
+            onChange={this.onRuleUIDChange}
+            onBlur={onRunQuery}
+          />
+        </InlineField>
+        {query.queryType === GrafanaQueryType.AlertStateHistory && (
+          <InlineField label="Limit" tooltip="Maximum number of state transitions">
+            <Input
+              type="number"
+              width={12}
+              placeholder="auto"
+              value={query.limit ?? ''}
+              onChange={this.onLimitChange}
+              onBlur={onRunQuery}
+            />
+          </InlineField>
+        )}
+      </InlineFieldRow>
+    );
+  }
+
   renderListPublicFiles() {
     let { path } = this.props.query;
     let { folders } = this.state;
@@ -471,6 +537,10 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
         {queryType === GrafanaQueryType.LiveMeasurements && this.renderMeasurementsQuery()}
         {queryType === GrafanaQueryType.List && this.renderListPublicFiles()}
         {queryType === GrafanaQueryType.Snapshot && this.renderSnapshotQuery()}
+        {(queryType === GrafanaQueryType.AlertStates ||
+          queryType === GrafanaQueryType.AlertStateHistory ||
+          queryType === GrafanaQueryType.RuleEvaluations) &&
+          this.renderAlertingQuery()}
         {queryType === GrafanaQueryType.Search && (
           <SearchEditor value={query.search ?? {}} onChange={this.onSearchChange} />
         )}
@@ -19,6 +19,13 @@ export enum GrafanaQueryType {
   List = 'list',
   Read = 'read',
   Search = 'search',
+
+  // backend, self-observability
+  AlertStates = 'alertStates',
+  AlertStateHistory = 'alertStateHistory',
+  RuleEvaluations = 'ruleEvaluations',
+  DataSourceHealth = 'dataSourceHealth',
+  ActiveUsers = 'activeUsers',
 }
 
 export interface GrafanaQuery extends DataQuery {
@@ -31,6 +38,8 @@ export interface GrafanaQuery extends DataQuery {
   snapshot?: DataFrameJSON[];
   timeRegion?: TimeRegionConfig;
   file?: GrafanaQueryFile;
+  ruleUID?: string; // for alert states, history and rule evaluations
+  limit?: number; // for alert state history
 }
 
 export interface GrafanaQueryFile {
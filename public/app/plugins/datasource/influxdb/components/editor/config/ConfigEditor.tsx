@@ -16,6 +16,7 @@ import { InfluxOptions, InfluxOptionsV1, InfluxVersion } from '../../../types';
 import { InfluxFluxConfig } from './InfluxFluxConfig';
 import { InfluxInfluxQLConfig } from './InfluxInfluxQLConfig';
 import { InfluxSqlConfig } from './InfluxSQLConfig';
+import { LinkMappings } from './LinkMappings';
 
 const versionMap: Record<InfluxVersion, SelectableValue<InfluxVersion>> = {
   [InfluxVersion.InfluxQL]: {
@@ -173,6 +174,19 @@ export class ConfigEditor extends PureComponent<Props, State> {
             />
           </InlineField>
         </FieldSet>
+
+        <LinkMappings
+          value={options.jsonData.linkMappings}
+          onChange={(linkMappings) =>
+            onOptionsChange({
+              ...options,
+              jsonData: {
+                ...options.jsonData,
+                linkMappings,
+              },
+            })
+          }
+        />
       </>
     );
   }
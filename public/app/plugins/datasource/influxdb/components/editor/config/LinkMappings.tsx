@@ -0,0 +1,180 @@
+import { css } from '@emotion/css';
+import { uniqueId } from 'lodash';
+import React, { useState } from 'react';
+
+import {
+  DataLinkBuiltInVars,
+  DataSourceInstanceSettings,
+  GrafanaTheme2,
+  VariableOrigin,
+  VariableSuggestion,
+} from '@grafana/data';
+import { DataSourcePicker } from '@grafana/runtime';
+import {
+  Button,
+  DataLinkInput,
+  FieldSet,
+  InlineField,
+  InlineFieldRow,
+  InlineLabel,
+  InlineSwitch,
+  Input,
+  useStyles2,
+} from '@grafana/ui';
+
+import { InfluxLinkMapping } from '../../../types';
+
+const suggestions: VariableSuggestion[] = [
+  {
+    value: DataLinkBuiltInVars.valueRaw,
+    label: 'Raw value',
+    documentation: 'Raw value of the field',
+    origin: VariableOrigin.Value,
+  },
+];
+
+type Props = {
+  value?: InfluxLinkMapping[];
+  onChange: (value: InfluxLinkMapping[]) => void;
+};
+
+export const LinkMappings = ({ value, onChange }: Props) => {
+  const styles = useStyles2(getStyles);
+
+  return (
+    <FieldSet>
+      <h3 className="page-heading">Data links</h3>
+      <p className="text-help">
+        Add links to the fields returned by InfluxQL, Flux and SQL queries, for example to open the trace stored in a
+        trace_id column.
+      </p>
+      {value?.map((mapping, index) => (
+        <LinkMapping
+          key={index}
+          className={styles.mapping}
+          value={mapping}
+          onChange={(newMapping) => {
+            const newMappings = [...value];
+            newMappings.splice(index, 1, newMapping);
+            onChange(newMappings);
+          }}
+          onDelete={() => {
+            const newMappings = [...value];
+            newMappings.splice(index, 1);
+            onChange(newMappings);
+          }}
+        />
+      ))}
+      <Button
+        type="button"
+        variant="secondary"
+        icon="plus"
+        onClick={(event) => {
+          event.preventDefault();
+          onChange([...(value || []), { field: '', url: '' }]);
+        }}
+      >
+        Add
+      </Button>
+    </FieldSet>
+  );
+};
+
+type LinkMappingProps = {
+  value: InfluxLinkMapping;
+  onChange: (value: InfluxLinkMapping) => void;
+  onDelete: () => void;
+  className?: string;
+};
+
+const LinkMapping = ({ value, onChange, onDelete, className }: LinkMappingProps) => {
+  const styles = useStyles2(getStyles);
+  const [htmlPrefix] = useState(() => uniqueId('influxdb-link-mapping'));
+  const [showInternalLink, setShowInternalLink] = useState(Boolean(value.datasourceUid));
+
+  return (
+    <div className={className}>
+      <InlineFieldRow>
+        <InlineField
+          label="Field"
+          htmlFor={`${htmlPrefix}-field`}
+          labelWidth={12}
+          tooltip="Can be exact field name or a regex pattern that will match on the field name."
+        >
+          <Input
+            id={`${htmlPrefix}-field`}
+            value={value.field}
+            onChange={(event) => onChange({ ...value, field: event.currentTarget.value })}
+            width={60}
+          />
+        </InlineField>
+        <Button
+          variant="destructive"
+          title="Remove link"
+          icon="times"
+          onClick={(event) => {
+            event.preventDefault();
+            onDelete();
+          }}
+        />
+      </InlineFieldRow>
+
+      <InlineFieldRow>
+        <div className={styles.urlField}>
+          <InlineLabel width={12}>{showInternalLink ? 'Query' : 'URL'}</InlineLabel>
+          <DataLinkInput
+            placeholder={showInternalLink ? '${__value.raw}' : 'http://example.com/${__value.raw}'}
+            value={value.url || ''}
+            onChange={(url) => onChange({ ...value, url })}
+            suggestions={suggestions}
+          />
+        </div>
+        <InlineField
+          label="URL Label"
+          htmlFor={`${htmlPrefix}-url-label`}
+          labelWidth={14}
+          tooltip="Use to override the button label."
+        >
+          <Input
+            id={`${htmlPrefix}-url-label`}
+            value={value.urlDisplayLabel}
+            onChange={(event) => onChange({ ...value, urlDisplayLabel: event.currentTarget.value })}
+          />
+        </InlineField>
+      </InlineFieldRow>
+
+      <InlineFieldRow>
+        <InlineField label="Internal link" labelWidth={12}>
+          <InlineSwitch
+            label="Internal link"
+            value={showInternalLink}
+            onChange={() => {
+              if (showInternalLink) {
+                onChange({ ...value, datasourceUid: undefined });
+              }
+              setShowInternalLink(!showInternalLink);
+            }}
+          />
+        </InlineField>
+        {showInternalLink && (
+          <DataSourcePicker
+            tracing={true}
+            onChange={(ds: DataSourceInstanceSettings) => onChange({ ...value, datasourceUid: ds.uid })}
+            current={value.datasourceUid}
+          />
+        )}
+      </InlineFieldRow>
+    </div>
+  );
+};
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  mapping: css({
+    marginBottom: theme.spacing(2),
+  }),
+  urlField: css({
+    display: 'flex',
+    flex: 1,
+    marginRight: theme.spacing(0.5),
+  }),
+});
@@ -24,6 +24,15 @@ export interface InfluxOptions extends DataSourceJsonData {
   // With SQL
   metadata?: Array<Record<string, string>>;
   insecureGrpc?: boolean;
+
+  linkMappings?: InfluxLinkMapping[];
+}
+
+export interface InfluxLinkMapping {
+  field: string;
+  url: string;
+  urlDisplayLabel?: string;
+  datasourceUid?: string;
 }
 
 /**
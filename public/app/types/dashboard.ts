@@ -65,6 +65,7 @@ export interface DashboardMeta {
   annotationsPermissions?: AnnotationsPermissions;
   publicDashboardUid?: string;
   publicDashboardEnabled?: boolean;
+  publicDashboardVariables?: string[];
   dashboardNotFound?: boolean;
   isEmbedded?: boolean;
   isNew?: boolean;